@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"quickattendance-go/internal/config"
+	"quickattendance-go/internal/repository"
+	"quickattendance-go/internal/service"
+	"quickattendance-go/pkg/logger"
+	"syscall"
+	"time"
+	_ "time/tzdata" // La imagen alpine no incluye la base de zonas horarias
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Uso:
+//
+//	scheduler                                 # corre ausencias, acumulación de permisos y vencimiento de la bolsa de horas cada ABSENCE_JOB_INTERVAL
+//	scheduler -from 2026-01-01 -to 2026-01-31 # backfill de un rango y termina
+func main() {
+	from := flag.String("from", "", "backfill start date (YYYY-MM-DD)")
+	to := flag.String("to", "", "backfill end date (YYYY-MM-DD), defaults to -from")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("no .env file found")
+	}
+	cfg := config.Load()
+	logger.Setup(cfg.Env)
+
+	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{TranslateError: true})
+	if err != nil {
+		slog.Error("failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+
+	// Repositories
+	agencyRepo := repository.NewAgencyRepo(db)
+	userRepo := repository.NewUserRepo(db)
+	scheduleRepo := repository.NewScheduleRepo(db)
+	holidayRepo := repository.NewHolidayRepo(db)
+	rotationRepo := repository.NewShiftRotationRepo(db)
+	scheduleOverrideRepo := repository.NewScheduleOverrideRepo(db)
+	leaveTypeRepo := repository.NewLeaveTypeRepo(db)
+	leaveRepo := repository.NewLeaveRequestRepo(db)
+	leaveBalanceRepo := repository.NewLeaveBalanceRepo(db)
+	timeBankRepo := repository.NewTimeBankRepo(db)
+	timesheetRepo := repository.NewTimesheetRepo(db)
+	attendanceRepo := repository.NewAttendanceRepo(db)
+	qrUseRepo := repository.NewQRTokenUseRepo(db)
+	txManager := repository.NewGormTransactor(db)
+
+	// Services
+	scheduleSvc := service.NewScheduleService(scheduleRepo, scheduleOverrideRepo, holidayRepo, rotationRepo, leaveRepo, userRepo, agencyRepo, txManager)
+	absenceSvc := service.NewAbsenceService(agencyRepo, userRepo, attendanceRepo, timesheetRepo, scheduleSvc)
+	leaveBalanceSvc := service.NewLeaveBalanceService(leaveBalanceRepo, leaveTypeRepo, leaveRepo, userRepo, agencyRepo)
+	timeBankSvc := service.NewTimeBankService(timeBankRepo, attendanceRepo, leaveRepo, userRepo, agencyRepo)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if *from != "" {
+		if *to == "" {
+			*to = *from
+		}
+		if err := backfill(ctx, absenceSvc, *from, *to); err != nil {
+			slog.Error("Backfill failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	slog.Info("Scheduler operativo", "interval", cfg.AbsenceJobInterval)
+
+	ticker := time.NewTicker(cfg.AbsenceJobInterval)
+	defer ticker.Stop()
+
+	for {
+		runAbsenceJob(ctx, absenceSvc)
+		runLeaveAccrualJob(ctx, leaveBalanceSvc)
+		runTimeBankExpiryJob(ctx, timeBankSvc)
+		purgeQRTokenUses(ctx, qrUseRepo)
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Deteniendo scheduler...")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runAbsenceJob(ctx context.Context, absenceSvc *service.AbsenceService) {
+	created, err := absenceSvc.DetectRecentAbsences(ctx, time.Now())
+	if err != nil {
+		slog.Error("Absence job failed", "error", err)
+		return
+	}
+	slog.Info("Absence job finished", "created", created)
+}
+
+// runLeaveAccrualJob guarda las acumulaciones, asignaciones, topes y vencimientos de saldo que ya ocurrieron
+func runLeaveAccrualJob(ctx context.Context, leaveBalanceSvc *service.LeaveBalanceService) {
+	created, err := leaveBalanceSvc.AccrueAll(ctx, time.Now())
+	if err != nil {
+		slog.Error("Leave accrual job failed", "error", err)
+		return
+	}
+	slog.Info("Leave accrual job finished", "created", created)
+}
+
+// runTimeBankExpiryJob descuenta de la bolsa de horas los créditos que superaron la vigencia de su agencia
+func runTimeBankExpiryJob(ctx context.Context, timeBankSvc *service.TimeBankService) {
+	created, err := timeBankSvc.ExpireAll(ctx, time.Now())
+	if err != nil {
+		slog.Error("Time bank expiry job failed", "error", err)
+		return
+	}
+	slog.Info("Time bank expiry job finished", "created", created)
+}
+
+// purgeQRTokenUses borra los usos de tokens QR que ya expiraron: un token vencido se rechaza
+// antes de consultar la tabla, así que esos registros no sirven para detectar repeticiones
+func purgeQRTokenUses(ctx context.Context, qrUseRepo *repository.QRTokenUseRepo) {
+	deleted, err := qrUseRepo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		slog.Error("QR token cleanup failed", "error", err)
+		return
+	}
+	slog.Info("QR token cleanup finished", "deleted", deleted)
+}
+
+func backfill(ctx context.Context, absenceSvc *service.AbsenceService, fromStr string, toStr string) error {
+	// Días calendario: cada agencia los evalúa en su propia zona horaria
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		return err
+	}
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		return err
+	}
+
+	created, err := absenceSvc.BackfillAbsences(ctx, from, to, time.Now())
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Backfill finished", "from", fromStr, "to", toStr, "created", created)
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	_ "quickattendance-go/docs" // Importar los documentos generados por swag
 	"quickattendance-go/internal/config"
-	"quickattendance-go/internal/domain"
 	"quickattendance-go/internal/repository"
 	"quickattendance-go/internal/service"
 	"quickattendance-go/internal/transport/http/handlers"
@@ -15,6 +14,7 @@ import (
 	"quickattendance-go/pkg/messaging"
 	"quickattendance-go/pkg/security"
 	"time"
+	_ "time/tzdata" // La imagen alpine no incluye la base de zonas horarias
 
 	"github.com/joho/godotenv"
 	"golang.org/x/time/rate"
@@ -54,7 +54,7 @@ func main() {
 	var db *gorm.DB
 	var err error
 	for i := range 10 {
-		db, err = gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{})
+		db, err = gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{TranslateError: true})
 		if err == nil {
 			break
 		}
@@ -67,10 +67,14 @@ func main() {
 		os.Exit(1)
 	}
 
-	db.AutoMigrate(&domain.Agency{}, &domain.User{}, &domain.Schedule{}, &domain.Attendance{})
+	if err := repository.Migrate(db, cfg.ServerTimeZone); err != nil {
+		slog.Error("failed to migrate database", "error", err)
+		os.Exit(1)
+	}
 
 	// Utilities
 	jwtService := security.NewJWTService(cfg.JWTSecret)
+	qrService := security.NewQRService(cfg.QRSecret, cfg.QRTokenRotation)
 	hasher := security.NewPasswordHasher(cfg.BCryptCost)
 	tokenTTL := cfg.AccessTokenTTL
 
@@ -87,20 +91,43 @@ func main() {
 	userRepo := repository.NewUserRepo(db)
 	scheduleRepo := repository.NewScheduleRepo(db)
 	attendanceRepo := repository.NewAttendanceRepo(db)
+	correctionRepo := repository.NewCorrectionRepo(db)
+	revisionRepo := repository.NewAttendanceRevisionRepo(db)
+	qrUseRepo := repository.NewQRTokenUseRepo(db)
+	nfcTagRepo := repository.NewNFCTagRepo(db)
+	siteRepo := repository.NewSiteRepo(db)
+	holidayRepo := repository.NewHolidayRepo(db)
+	rotationRepo := repository.NewShiftRotationRepo(db)
+	scheduleOverrideRepo := repository.NewScheduleOverrideRepo(db)
+	leaveTypeRepo := repository.NewLeaveTypeRepo(db)
+	leaveRepo := repository.NewLeaveRequestRepo(db)
+	leaveBalanceRepo := repository.NewLeaveBalanceRepo(db)
+	timeBankRepo := repository.NewTimeBankRepo(db)
+	timesheetRepo := repository.NewTimesheetRepo(db)
 	txManager := repository.NewGormTransactor(db)
 
 	// Services
 	agencySvc := service.NewAgencyService(agencyRepo, userRepo, hasher, txManager)
 	userSvc := service.NewUserService(userRepo, agencyRepo, jwtService, hasher, emailProducer, tokenTTL)
-	scheduleSvc := service.NewScheduleService(scheduleRepo, userRepo, txManager)
-	attendanceSvc := service.NewAttendanceService(attendanceRepo, userRepo, scheduleSvc, txManager)
+	scheduleSvc := service.NewScheduleService(scheduleRepo, scheduleOverrideRepo, holidayRepo, rotationRepo, leaveRepo, userRepo, agencyRepo, txManager)
+	fraudScorer := service.NewFraudScorer(attendanceRepo)
+	timeBankSvc := service.NewTimeBankService(timeBankRepo, attendanceRepo, leaveRepo, userRepo, agencyRepo)
+	timesheetSvc := service.NewTimesheetService(timesheetRepo, attendanceRepo, userRepo, agencyRepo, scheduleSvc, txManager)
+	attendanceSvc := service.NewAttendanceService(attendanceRepo, revisionRepo, qrUseRepo, nfcTagRepo, siteRepo, userRepo, agencyRepo, scheduleSvc, timeBankSvc, timesheetSvc, fraudScorer, qrService, txManager)
+	nfcTagSvc := service.NewNFCTagService(nfcTagRepo, siteRepo, agencyRepo)
+	siteSvc := service.NewSiteService(siteRepo)
+	holidaySvc := service.NewHolidayService(holidayRepo, agencyRepo, txManager)
+	rotationSvc := service.NewShiftRotationService(rotationRepo, userRepo, agencyRepo, txManager)
+	correctionSvc := service.NewCorrectionService(correctionRepo, attendanceRepo, userRepo, agencyRepo, attendanceSvc, emailProducer, txManager)
+	leaveBalanceSvc := service.NewLeaveBalanceService(leaveBalanceRepo, leaveTypeRepo, leaveRepo, userRepo, agencyRepo)
+	leaveSvc := service.NewLeaveService(leaveTypeRepo, leaveRepo, attendanceRepo, userRepo, agencyRepo, attendanceSvc, scheduleSvc, leaveBalanceSvc, timeBankSvc, emailProducer, txManager)
 
 	// Rate Limiting Config (Production values)
 	rps := rate.Limit(5)
 	burst := 10
 
 	// Router
-	r := handlers.NewRouter(agencySvc, userSvc, scheduleSvc, attendanceSvc, jwtService, rps, burst)
+	r := handlers.NewRouter(agencySvc, userSvc, scheduleSvc, attendanceSvc, correctionSvc, nfcTagSvc, siteSvc, holidaySvc, rotationSvc, leaveSvc, leaveBalanceSvc, timeBankSvc, timesheetSvc, jwtService, rps, burst)
 
 	// Server
 	fmt.Printf("Server running on port %s\n", cfg.HTTPPort)
@@ -158,21 +158,96 @@ const docTemplate = `{
                 }
             }
         },
-        "/attendance/list": {
-            "get": {
+        "/attendance": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns a list of attendance records for the agency. Employees can only see their own records.",
+                "description": "Records an attendance for a user on an arbitrary date with explicit check-in and optional check-out times (e.g. the employee's phone died). Statuses are computed from the schedule applicable on that date. The check-in must fall within that date's shift in the agency time zone (from midnight until the next midnight, or until the scheduled exit for overnight shifts) and the check-out within 24 hours of it, otherwise 400. The reason is mandatory and stored in the attendance history. Dates within a locked timesheet are rejected with 409.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "attendance"
                 ],
-                "summary": "List attendance records",
+                "summary": "Create attendance for any date (Admin)",
+                "parameters": [
+                    {
+                        "description": "Attendance details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AdminCreateAttendanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AttendanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/attendance/corrections": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the correction requests of the agency. Employees can only see their own requests.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "corrections"
+                ],
+                "summary": "List correction requests",
                 "parameters": [
                     {
                         "type": "string",
@@ -182,8 +257,20 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Date filter (YYYY-MM-DD)",
-                        "name": "date",
+                        "description": "Status (pending, approved, rejected)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -193,7 +280,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/domain.Attendance"
+                                "$ref": "#/definitions/dto.CorrectionResponse"
                             }
                         }
                     },
@@ -216,16 +303,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/attendance/mark": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Marks user attendance for today. Geolocation check is applied for remote work.",
+                "description": "Submits a correction request for the current user's attendance: a missing punch (punch_type + requested_time) or a wrong punch time (attendance_id + punch_id + requested_time). Dates within a locked timesheet are rejected with 409. Admins of the agency are notified by email.",
                 "consumes": [
                     "application/json"
                 ],
@@ -233,25 +318,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "attendance"
+                    "corrections"
                 ],
-                "summary": "Mark attendance",
+                "summary": "Request an attendance correction",
                 "parameters": [
                     {
-                        "description": "Attendance details",
+                        "description": "Correction details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.MarkAttendanceRequest"
+                            "$ref": "#/definitions/dto.CreateCorrectionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.Attendance"
+                            "$ref": "#/definitions/dto.CorrectionResponse"
                         }
                     },
                     "400": {
@@ -263,8 +348,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -293,14 +378,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/schedules": {
+        "/attendance/corrections/{id}/approve": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new work schedule for the agency (Admin only).",
+                "description": "Applies the correction to the attendance record (creating it for a missing check-in), recalculates statuses and totals, records the previous values in the attendance history and notifies the employee.",
                 "consumes": [
                     "application/json"
                 ],
@@ -308,25 +393,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "schedules"
+                    "corrections"
                 ],
-                "summary": "Create a new schedule",
+                "summary": "Approve a correction request",
                 "parameters": [
                     {
-                        "description": "Schedule details",
+                        "type": "string",
+                        "description": "Correction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review note",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateScheduleRequest"
+                            "$ref": "#/definitions/dto.ReviewCorrectionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Schedule"
+                            "$ref": "#/definitions/dto.CorrectionResponse"
                         }
                     },
                     "400": {
@@ -338,6 +429,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -359,41 +459,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/schedules/applicable": {
-            "get": {
+        "/attendance/corrections/{id}/reject": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns the schedule that applies to a user on a specific date.",
+                "description": "Rejects a pending correction request and notifies the employee. The attendance record is not modified.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "schedules"
+                    "corrections"
                 ],
-                "summary": "Get applicable schedule for a date",
+                "summary": "Reject a correction request",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID (defaults to current user)",
-                        "name": "user_id",
-                        "in": "query"
+                        "description": "Correction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query",
-                        "required": true
+                        "description": "Review note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewCorrectionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Schedule"
+                            "$ref": "#/definitions/dto.CorrectionResponse"
                         }
                     },
                     "400": {
@@ -413,32 +518,13 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/schedules/list": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns a list of schedules for the agency.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "schedules"
-                ],
-                "summary": "List all schedules",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Schedule"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -454,35 +540,77 @@ const docTemplate = `{
                 }
             }
         },
-        "/schedules/{id}": {
+        "/attendance/export": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns a specific schedule details.",
+                "description": "Downloads the attendance records matching the same filters as /attendance/list as a CSV or XLSX file, with user names, dates, check-in and check-out times (agency timezone), methods, statuses, site and computed durations in minutes (scheduled, worked, break, overtime, deficit, late, early leave). Pagination is ignored: every matching record is exported, ordered by date, and the file is streamed so large ranges do not need to fit in memory. Column labels follow lang, then the Accept-Language header, then Spanish. Employees can only export their own records.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "schedules"
+                    "attendance"
                 ],
-                "summary": "Get schedule by ID",
+                "summary": "Export attendance records",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schedule ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "File format (csv, xlsx). Default: csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column labels language (es, en)",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID filter (Admins only)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entry or exit status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entry status (present, late, absent)",
+                        "name": "entry_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exit status (on_time, early, short_hours, absent)",
+                        "name": "exit_status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Schedule"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -494,8 +622,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -504,47 +632,63 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/attendance/list": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing schedule (Admin only).",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns a list of attendance records for the agency. Employees can only see their own records.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "schedules"
+                    "attendance"
                 ],
-                "summary": "Update a schedule",
+                "summary": "List attendance records",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schedule ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User ID filter (Admins only)",
+                        "name": "user_id",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateScheduleRequest"
-                        }
-                    }
-                ],
+                        "type": "string",
+                        "description": "Date filter (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entry or exit status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entry status (present, late, absent)",
+                        "name": "entry_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exit status (on_time, early, short_hours, absent)",
+                        "name": "exit_status",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Schedule"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Attendance"
+                            }
                         }
                     },
                     "400": {
@@ -556,17 +700,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -575,38 +710,42 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/attendance/mark": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a schedule from the agency (Admin only).",
+                "description": "Registers a punch (in, out, break_start, break_end) on the daily attendance of the current shift. Punches after check-in attach to the open shift, even if it started the previous day. A new \"in\" after an \"out\" starts another interval (split shift). Geolocation check is applied for remote work. Method \"qr\" requires the qr_token shown by the agency's QR code; each code can be used once per user. Method \"nfc\" requires the nfc_tag_uid of a registered, active tag of the agency. Non-remote punches with coordinates record the site whose geofence contains them; if the agency requires on-site attendance, punches outside every site are rejected with the nearest site and distance. Geofences can be circles or polygons; \"accuracy\" (meters) is the fix's uncertainty radius, which must overlap or fit inside the fence depending on the agency's geofence_strictness, and is required and capped when the agency sets max_location_accuracy_meters.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "schedules"
+                    "attendance"
                 ],
-                "summary": "Delete a schedule",
+                "summary": "Mark attendance",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Schedule ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Attendance details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MarkAttendanceRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/domain.Attendance"
                         }
                     },
                     "400": {
@@ -635,44 +774,9 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/users/activate": {
-            "post": {
-                "description": "Activates a user account using the code sent via email. Returns JWT token.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
-                ],
-                "summary": "Activate a user account",
-                "parameters": [
-                    {
-                        "description": "Activation code and credentials",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ActivateUserRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -680,8 +784,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -701,65 +805,99 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/invite": {
-            "post": {
+        "/attendance/qr": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new user record and sends an invitation email (Admin only)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the signed token the agency's QR display must show right now. It rotates every QR_TOKEN_ROTATION and stays valid for one extra rotation; the display should fetch a new one at refresh_at.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
-                ],
-                "summary": "Invite a new user to the agency",
-                "parameters": [
-                    {
-                        "description": "Invitation details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.InviteUserRequest"
-                        }
-                    }
+                    "attendance"
                 ],
+                "summary": "Current attendance QR code (Admin)",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dto.QRTokenResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/attendance/suspicious": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns attendance records flagged for review because their locations look spoofed: impossible travel since the previous punch, exact coordinates reused from another day, or an implausible precision (accuracy below 1 m or coordinates rounded to 4 decimals). Reused coordinates and implausible precision only flag a record together with another signal. Resolved flags are no longer listed. Newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "List suspicious attendance (Admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID filter",
+                        "name": "user_id",
+                        "in": "query"
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.AttendanceResponse"
                             }
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -779,9 +917,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/login": {
-            "post": {
-                "description": "Authenticate user with email and password. Returns JWT token.",
+        "/attendance/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the check-in time (first \"in\" punch), the check-out time (last \"out\" punch, added if missing) and/or the notes of an attendance record. Statuses and totals are recalculated and the previous values are stored in the attendance history with the mandatory reason. The times must stay within the shift of the record's date, as on creation (400). Records within a locked timesheet cannot be edited (409).",
                 "consumes": [
                     "application/json"
                 ],
@@ -789,30 +932,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "attendance"
                 ],
-                "summary": "Login user",
+                "summary": "Edit attendance (Admin)",
                 "parameters": [
                     {
-                        "description": "Login credentials",
+                        "type": "string",
+                        "description": "Attendance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginUserRequest"
+                            "$ref": "#/definitions/dto.AdminUpdateAttendanceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AttendanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -832,30 +999,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
+        "/attendance/{id}/history": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns the profile of the currently authenticated user.",
+                "description": "Returns the changes applied to an attendance record after it was registered (approved corrections, admin edits and voids), with previous and new values and who made them. Employees can only see their own records.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "attendance"
+                ],
+                "summary": "Attendance change history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Attendance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get current user profile",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.AttendanceRevisionResponse"
+                            }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -883,336 +1062,5943 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
+        "/attendance/{id}/resolve-flag": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clears the review flag of a suspicious attendance once an admin has checked it. The fraud signals and score are kept, and the record is only flagged again if a later punch adds a signal it did not have. The reason is mandatory and recorded in the history. Returns 409 if the record is not flagged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Resolve a suspicious attendance flag (Admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Attendance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResolveFlagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AttendanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/attendance/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Voids an attendance record. It is kept with its history but excluded from listings, and the day can be registered again. The reason is mandatory. Records within a locked timesheet cannot be voided (409).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Void attendance (Admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Attendance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VoidAttendanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AttendanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/holidays": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a holiday or closure day to the agency calendar (Admin only). No work is expected on these dates: schedules do not apply and no absences are recorded. Recurring holidays repeat every year on the same day and month.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holidays"
+                ],
+                "summary": "Add a holiday",
+                "parameters": [
+                    {
+                        "description": "Holiday details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateHolidayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HolidayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/holidays/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds the events of an .ics file to the agency calendar (Admin only). Events with an endless yearly RRULE become recurring holidays; when the rule has COUNT or UNTIL, each occurrence is added as its own holiday. Multi-day events are expanded day by day, and UTC times are converted to the agency's time zone before taking the day. Dates already in the calendar are skipped.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holidays"
+                ],
+                "summary": "Import holidays from iCalendar",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "iCalendar (.ics) file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "holiday or closure (default holiday)",
+                        "name": "type",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HolidayImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/holidays/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the agency calendar. With year, returns that year's holidays plus recurring ones from earlier years.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holidays"
+                ],
+                "summary": "List holidays",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year filter",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.HolidayResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/holidays/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a date from the agency calendar (Admin only). Absences are not recreated for past dates until the absence backfill runs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holidays"
+                ],
+                "summary": "Delete a holiday",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Holiday ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/balances": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns, for each leave type that tracks a balance, the user's balance today and the balance projected to date, including accruals, anniversary grants, carry-over caps and expiries not generated yet and approved leave already deducted. pending_days are requested but not approved yet. Employees can only see their own balances.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Get leave balances",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (Admins only, defaults to the current user)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Projection date (YYYY-MM-DD), defaults to today. At most 5 years ahead",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LeaveBalanceResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/balances/adjustments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records a manual movement on a user's balance, e.g. the opening balance when tracking starts or a correction (Admin only). days is positive to add and negative to subtract; a note is required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Adjust a leave balance",
+                "parameters": [
+                    {
+                        "description": "Adjustment details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateLeaveAdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LeaveBalanceEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/balances/ledger": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every recorded grant, accrual, deduction, adjustment, carry-over cap and expiry of the user's leave balances, latest first. Employees can only see their own movements.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "List leave balance movements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (Admins only, defaults to the current user)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Leave type filter",
+                        "name": "leave_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kind (accrual, grant, deduction, adjustment, carry_over_cap, expiry)",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LeaveBalanceEntryResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/requests": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Submits a leave request for the current user covering full days (start_date to end_date) or half of a single day (half_day). It cannot overlap another pending or approved request. Only days with a scheduled shift count towards days; for leave types that track a balance they cannot exceed the balance projected to start_date minus other pending requests (409). Comp time types (uses_time_bank) likewise check the scheduled minutes against the time bank. Admins of the agency are notified by email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Request leave",
+                "parameters": [
+                    {
+                        "description": "Leave details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateLeaveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LeaveRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/requests/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the leave requests of the agency, latest start date first. Employees can only see their own requests.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "List leave requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID filter (Admins only)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Leave type filter",
+                        "name": "leave_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (pending, approved, rejected, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Requests overlapping from this date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Requests overlapping until this date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LeaveRequestResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/requests/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approves a pending leave request and notifies the employee. From then on no work is expected on the covered days (a half-day leave shortens the shift to the half still worked), and absences already recorded by the job on those days are voided and logged in the attendance history (409 if one of them falls within a locked timesheet). For leave types that track a balance the days are deducted on start_date; approval fails with 409 if the balance is no longer enough. Comp time types debit their scheduled minutes from the time bank on the approval date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Approve a leave request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Leave request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewLeaveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LeaveRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/requests/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraws one of the current user's own leave requests while it is still pending.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Cancel a leave request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Leave request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LeaveRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/requests/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rejects a pending leave request and notifies the employee.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Reject a leave request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Leave request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewLeaveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LeaveRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/types": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a kind of leave to the agency, e.g. vacation, sick leave or personal day (Admin only). With uses_time_bank the type is comp time and approved requests are paid from the time bank. With track_balance each employee gets a balance that grows with the accrual policy (monthly, or a yearly grant on the hire anniversary); on every anniversary the balance above carry_over_cap_days is dropped, and the carried days still unused carry_over_expiry_months into the new year expire. Tracking starts on the current date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Create a leave type",
+                "parameters": [
+                    {
+                        "description": "Leave type details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateLeaveTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LeaveTypeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/types/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the leave types of the agency.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "List leave types",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Active filter",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LeaveTypeResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leave/types/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renames a leave type, changes whether it is paid, its balance policy, or deactivates it (Admin only). Inactive types accept no new requests; existing ones are kept. Policy changes apply to movements generated from then on; turning track_balance on restarts tracking on the current date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Update a leave type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Leave type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateLeaveTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LeaveTypeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/nfc-tags": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registers an NFC tag of the agency (Admin only). The UID is stored in uppercase hexadecimal without separators. The optional site_id is the site where the tag is installed (400 if it is not a site of the agency); punches read from the tag are recorded at that site.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nfc-tags"
+                ],
+                "summary": "Register an NFC tag",
+                "parameters": [
+                    {
+                        "description": "Tag details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateNFCTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.NFCTagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/nfc-tags/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the NFC tags registered in the agency (Admin only).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nfc-tags"
+                ],
+                "summary": "List NFC tags",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Filter by active state",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.NFCTagResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/nfc-tags/usage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the punches registered with NFC tags, most recent first. Punches of voided attendance records are excluded (Admin only).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nfc-tags"
+                ],
+                "summary": "List NFC tag usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.NFCTagUsageResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/nfc-tags/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the label, site or active state of a tag (Admin only). Send the nil UUID as site_id to detach the tag from its site. Inactive tags are rejected when marking attendance.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nfc-tags"
+                ],
+                "summary": "Update an NFC tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateNFCTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.NFCTagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new work schedule for the agency (Admin only). With effective_from the schedule only applies from that date. Assigned users cannot have another schedule on the same weekdays (409 with the conflicting schedule). With type \"flexible\", entry and exit define the band where work counts, core_start/core_end the time the employee must be present (lateness and early leave are checked against it) and required_minutes the daily total; checking out with fewer minutes is reported as short_hours.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Create a new schedule",
+                "parameters": [
+                    {
+                        "description": "Schedule details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/applicable": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the schedule that applies to a user on a specific date. A date override replaces entry, exit and grace period and is reported in \"override\"; agency-wide overrides only apply on days the user already works. On holidays and closure days of the agency calendar no work is expected: the response is 404 with the holiday. The same applies to days covered by an approved full-day leave (404 with the leave), while an approved half-day leave shortens the shift to the half still worked and is reported in \"leave\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Get applicable schedule for a date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (defaults to current user)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/conflicts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns users assigned to two schedules that apply on the same weekdays from today on (Admin only). New assignments are validated, so these come from earlier data; meanwhile the oldest schedule is applied.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "List overlapping schedule assignments",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ScheduleConflictResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the schedules of the agency: the versions in effect today and the ones scheduled to start later. Ended versions are listed in /schedules/{id}/versions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "List all schedules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Schedule"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/overrides": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces entry time, exit time and grace period for a date or date range (Admin only), e.g. closing early on a Friday. Without user_ids it applies to the whole agency, but only on days each user already works (never on holidays or days off). With user_ids it also adds the working day for those users, even on holidays or days off. User overrides win over agency ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Create a schedule override",
+                "parameters": [
+                    {
+                        "description": "Override details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateScheduleOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ScheduleOverrideResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/overrides/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the schedule overrides of the agency (Admin only). With user_id, returns that user's overrides plus agency-wide ones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "List schedule overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID filter",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Overrides ending on or after this date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Overrides starting on or before this date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ScheduleOverrideResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/overrides/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a schedule override (Admin only). Attendance already recorded keeps the times it was evaluated with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Delete a schedule override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Override ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/rotations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a rotating shift pattern (Admin only): an ordered list of shifts, each lasting some consecutive days, repeated in a cycle from anchor_date (e.g. 2 days, 2 nights, 4 off).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rotations"
+                ],
+                "summary": "Create a shift rotation",
+                "parameters": [
+                    {
+                        "description": "Rotation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateShiftRotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShiftRotationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/rotations/assignments/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the user from the rotation as of today (Admin only); the assignment is kept for past dates. An assignment that has not started yet is deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rotations"
+                ],
+                "summary": "End a rotation assignment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Assignment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/rotations/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the shift rotations of the agency (Admin only).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rotations"
+                ],
+                "summary": "List shift rotations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ShiftRotationResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/rotations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a shift rotation with its shifts (Admin only).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rotations"
+                ],
+                "summary": "Get a shift rotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rotation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShiftRotationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a shift rotation that was never assigned (Admin only). Rotations with assignments are kept so past dates still resolve.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rotations"
+                ],
+                "summary": "Delete a shift rotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rotation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/rotations/{id}/assignments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns current, upcoming and ended assignments of the rotation (Admin only).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rotations"
+                ],
+                "summary": "List the assignments of a shift rotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rotation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RotationAssignmentResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assigns a user to the rotation from start_date (default today, never in the past) with an offset in days within the cycle (Admin only). A previous assignment of the user ends the day before. While assigned, the rotation replaces the user's weekly schedule.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rotations"
+                ],
+                "summary": "Assign a user to a shift rotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rotation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Assignment details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssignRotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RotationAssignmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a specific schedule details.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Get schedule by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing schedule (Admin only). Name and is_default apply to every version. Changes to days, times, flexible settings or assigned users create a new version starting on effective_from (default today, never in the past) and close the previous one the day before, so past dates keep the rules they had. Only the latest version can be edited, and assigned users cannot end up with another schedule on the same weekdays (409).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Update a schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retires a schedule as of today (Admin only): versions that have not started yet are removed and the rest end yesterday, so past dates keep their schedule.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Delete a schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every version of the schedule series the given version belongs to, oldest first, with their effective dates (Admin only).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "List the versions of a schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID (any version)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ScheduleResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sites": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an office site for on-site attendance (Admin only). The geofence is a circle (latitude, longitude and radius_meters) or a polygon of at least 3 points; a polygon's center defaults to the average of its points.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Create a site",
+                "parameters": [
+                    {
+                        "description": "Site details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateSiteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SiteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sites/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the sites of the agency with their geofences. Admin only: employees cannot read the geofences.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "List sites (Admin)",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Filter by active state",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.SiteResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sites/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the name or geofence of a site, or deactivates it (Admin only). Sending \"polygon\" replaces the polygon; an empty list turns the site back into a circle, which needs radius_meters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sites"
+                ],
+                "summary": "Update a site",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateSiteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SiteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/time-bank/adjustments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records a manual movement on a user's time bank, e.g. an opening balance or overtime paid out instead of banked (Admin only). minutes is positive to credit and negative to debit; a note is required. The agency must have the time bank enabled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-bank"
+                ],
+                "summary": "Adjust a time bank",
+                "parameters": [
+                    {
+                        "description": "Adjustment details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTimeBankAdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TimeBankEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/time-bank/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the user's banked minutes: credited for working past the scheduled exit, debited for leaving early, taking comp time or expired credits. Credits are used oldest first; expirations lists when the unused ones expire. pending_minutes are requested as comp time but not approved yet. Employees can only see their own balance.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-bank"
+                ],
+                "summary": "Get time bank balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (Admins only, defaults to the current user)",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TimeBankBalanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/time-bank/ledger": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every movement of the user's time bank, latest first. Movements from an attendance are recorded as differences: when the record is corrected or voided a new movement reverses or adjusts what it contributed. Employees can only see their own movements.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-bank"
+                ],
+                "summary": "List time bank movements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (Admins only, defaults to the current user)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kind (overtime, early_leave, comp_time, adjustment, expiry)",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.TimeBankEntryResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the user's timesheet for a month: one row per day with the scheduled minutes, attendance, lateness, early leave, absence, approved leave or holiday, and the month totals. Locked timesheets return the content that was acknowledged; otherwise it is computed from the current data and changed tells whether it differs from the acknowledged version. Employees can only see their own timesheet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Get a monthly timesheet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "period",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID (Admins only, defaults to the current user)",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TimesheetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheets/acknowledge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The current user confirms they reviewed their timesheet for a month that has already ended. The content they saw is stored with the time and IP address of the acknowledgement. It can be acknowledged again while it is not locked, e.g. after a correction.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Acknowledge a timesheet",
+                "parameters": [
+                    {
+                        "description": "Month",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AcknowledgeTimesheetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TimesheetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheets/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the timesheet status of every active user of the agency for a month, with the totals they acknowledged. Users who have not acknowledged it yet are listed as open.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "List timesheets of a month (Admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "period",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.TimesheetSummaryResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheets/lock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Locks a timesheet the employee acknowledged. From then on attendance records of that user within the month cannot be created, edited, voided or corrected (409). Fails with 409 if the timesheet was not acknowledged or the attendance changed since then.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Lock a timesheet (Admin)",
+                "parameters": [
+                    {
+                        "description": "User and month",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TimesheetActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TimesheetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/timesheets/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reopens a locked timesheet so the month's attendance can be corrected. The acknowledgement is discarded and the employee must acknowledge it again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timesheets"
+                ],
+                "summary": "Unlock a timesheet (Admin)",
+                "parameters": [
+                    {
+                        "description": "User and month",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TimesheetActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TimesheetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/activate": {
+            "post": {
+                "description": "Activates a user account using the code sent via email. Returns JWT token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Activate a user account",
+                "parameters": [
+                    {
+                        "description": "Activation code and credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ActivateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/invite": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new user record and sends an invitation email (Admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Invite a new user to the agency",
+                "parameters": [
+                    {
+                        "description": "Invitation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InviteUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "Authenticate user with email and password. Returns JWT token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the profile of the currently authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get current user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
         "domain.Agency": {
             "type": "object",
             "properties": {
                 "address": {
                     "type": "string"
                 },
-                "createdAt": {
-                    "type": "string"
+                "createdAt": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "geofenceStrictness": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isActive": {
+                    "type": "boolean"
+                },
+                "maxLocationAccuracyMeters": {
+                    "description": "Precisión de las ubicaciones: 0 = no se exige precisión",
+                    "type": "integer"
+                },
+                "minOvertimeMinutes": {
+                    "description": "Bajo este umbral el exceso no cuenta como horas extra",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "requireOnSiteLocation": {
+                    "description": "Las marcas presenciales (no remotas) deben caer dentro de alguna sede activa",
+                    "type": "boolean"
+                },
+                "timeZone": {
+                    "description": "Nombre IANA, ej: \"America/Santiago\"",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.User"
+                    }
+                },
+                "workRoundingMinutes": {
+                    "description": "Reglas de cálculo de horas trabajadas",
+                    "type": "integer"
+                },
+                "workRoundingMode": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Attendance": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "description": "Radio de precisión horizontal en metros informado por el dispositivo",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "agency": {
+                    "$ref": "#/definitions/domain.Agency"
+                },
+                "agencyID": {
+                    "type": "string"
+                },
+                "checkInTime": {
+                    "description": "NULL cuando el registro es una ausencia generada por el sistema",
+                    "type": "string"
+                },
+                "checkOutTime": {
+                    "description": "Puede ser NULL hasta que salgan",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "Día en que comienza el turno",
+                    "type": "string"
+                },
+                "deficitMinutes": {
+                    "type": "integer"
+                },
+                "entryStatus": {
+                    "type": "string"
+                },
+                "exitStatus": {
+                    "description": "NULL hasta el checkout",
+                    "type": "string"
+                },
+                "flaggedForReview": {
+                    "description": "El puntaje alcanzó el umbral: un admin debe revisar el registro",
+                    "type": "boolean"
+                },
+                "flexBandEnd": {
+                    "type": "string"
+                },
+                "flexBandStart": {
+                    "description": "Jornada flexible: ScheduleEntryTime y ScheduleExitTime son el tiempo núcleo, solo cuenta lo trabajado\ndentro de la banda y se exigen RequiredMinutes en lugar de la duración del turno",
+                    "type": "string"
+                },
+                "fraudScore": {
+                    "description": "Suma de los pesos de FraudSignals, máximo 100",
+                    "type": "integer"
+                },
+                "fraudSignals": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "description": "Geolocation",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "longitude": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "methodIn": {
+                    "type": "string"
+                },
+                "methodOut": {
+                    "description": "Opcional hasta el checkout",
+                    "type": "string"
+                },
+                "nfctagID": {
+                    "description": "Etiqueta y sede de la entrada, si se marcó con nfc",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "overtimeMinutes": {
+                    "type": "integer"
+                },
+                "punches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AttendancePunch"
+                    }
+                },
+                "requiredMinutes": {
+                    "type": "integer"
+                },
+                "scheduleEntryTime": {
+                    "type": "string"
+                },
+                "scheduleExitTime": {
+                    "type": "string"
+                },
+                "scheduledMinutes": {
+                    "description": "Se calculan al hacer checkout",
+                    "type": "integer"
+                },
+                "site": {
+                    "type": "string"
+                },
+                "siteID": {
+                    "description": "Sede cuya geocerca contiene la ubicación de la entrada",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/domain.User"
+                },
+                "userID": {
+                    "type": "string"
+                },
+                "voidReason": {
+                    "type": "string"
+                },
+                "voidedAt": {
+                    "description": "Un registro anulado se conserva para el historial pero no cuenta en listados ni reportes",
+                    "type": "string"
+                },
+                "voidedByID": {
+                    "type": "string"
+                },
+                "workedMinutes": {
+                    "description": "Suma de los intervalos entrada/salida, sin pausas",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AttendancePunch": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "attendanceID": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "fraudSignals": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "longitude": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "nfctagID": {
+                    "type": "string"
+                },
+                "site": {
+                    "description": "Copia del nombre de la sede de SiteID al momento de marcar",
+                    "type": "string"
+                },
+                "siteID": {
+                    "description": "Sede cuya geocerca contiene la ubicación de la marca",
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "site_id": {
+                    "description": "Sede de la etiqueta nfc, si no la que contiene la ubicación de la marca",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.GeoPoint": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.Role": {
+            "type": "string",
+            "enum": [
+                "admin",
+                "employee"
+            ],
+            "x-enum-varnames": [
+                "RoleAdmin",
+                "RoleEmployee"
+            ]
+        },
+        "domain.Schedule": {
+            "type": "object",
+            "properties": {
+                "agency": {
+                    "$ref": "#/definitions/domain.Agency"
+                },
+                "agencyID": {
+                    "type": "string"
+                },
+                "assignedUsers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.User"
+                    }
+                },
+                "coreEndMinutes": {
+                    "type": "integer"
+                },
+                "coreStartMinutes": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "daysOfWeek": {
+                    "description": "Máscara de bits de los días (0=Dom, 1=Lun...); antes era un texto \"1,2,3\" en days_of_week",
+                    "type": "integer"
+                },
+                "earlyLeaveToleranceMinutes": {
+                    "description": "Minutos antes de la salida en que el checkout aún no se considera anticipado",
+                    "type": "integer"
+                },
+                "effectiveFrom": {
+                    "description": "Vigencia inclusiva; nil = sin límite por ese lado",
+                    "type": "string"
+                },
+                "effectiveTo": {
+                    "type": "string"
+                },
+                "entryTimeMinutes": {
+                    "type": "integer"
+                },
+                "exitTimeMinutes": {
+                    "description": "Si es \u003c= EntryTimeMinutes el turno termina al día siguiente",
+                    "type": "integer"
+                },
+                "gracePeriodMinutes": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isDefault": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "requiredMinutes": {
+                    "type": "integer"
+                },
+                "seriesID": {
+                    "description": "Igual al ID de la primera versión",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Jornada flexible: EntryTimeMinutes y ExitTimeMinutes delimitan la banda en que se puede trabajar,\nel atraso y la salida anticipada se evalúan contra el tiempo núcleo y al salir se exigen RequiredMinutes",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Status": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "active",
+                "inactive"
+            ],
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusActive",
+                "StatusInactive"
+            ]
+        },
+        "domain.TimesheetDay": {
+            "type": "object",
+            "properties": {
+                "absent": {
+                    "type": "boolean"
+                },
+                "attendance_id": {
+                    "type": "string"
+                },
+                "check_in_time": {
+                    "type": "string"
+                },
+                "check_out_time": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "Format: YYYY-MM-DD",
+                    "type": "string"
+                },
+                "deficit_minutes": {
+                    "type": "integer"
+                },
+                "early_leave_minutes": {
+                    "type": "integer"
+                },
+                "entry_status": {
+                    "type": "string"
+                },
+                "exit_status": {
+                    "type": "string"
+                },
+                "holiday": {
+                    "description": "Feriado o cierre del calendario",
+                    "type": "string"
+                },
+                "late_minutes": {
+                    "type": "integer"
+                },
+                "leave": {
+                    "description": "Tipo del permiso aprobado que cubre el día",
+                    "type": "string"
+                },
+                "leave_days": {
+                    "description": "1 o 0.5 (medio día)",
+                    "type": "number"
+                },
+                "overtime_minutes": {
+                    "type": "integer"
+                },
+                "scheduled_minutes": {
+                    "type": "integer"
+                },
+                "worked_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.TimesheetTotals": {
+            "type": "object",
+            "properties": {
+                "absence_days": {
+                    "type": "integer"
+                },
+                "deficit_minutes": {
+                    "type": "integer"
+                },
+                "early_leave_days": {
+                    "type": "integer"
+                },
+                "early_leave_minutes": {
+                    "type": "integer"
+                },
+                "holiday_days": {
+                    "type": "integer"
+                },
+                "late_days": {
+                    "type": "integer"
+                },
+                "late_minutes": {
+                    "type": "integer"
+                },
+                "leave_days": {
+                    "type": "number"
+                },
+                "overtime_minutes": {
+                    "type": "integer"
+                },
+                "scheduled_days": {
+                    "type": "integer"
+                },
+                "scheduled_minutes": {
+                    "type": "integer"
+                },
+                "worked_days": {
+                    "type": "integer"
+                },
+                "worked_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.User": {
+            "type": "object",
+            "properties": {
+                "activationCode": {
+                    "type": "string"
+                },
+                "agency": {
+                    "$ref": "#/definitions/domain.Agency"
+                },
+                "agencyID": {
+                    "type": "string"
+                },
+                "codeExpiry": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "firstName": {
+                    "type": "string"
+                },
+                "homeLatitude": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "homeLongitude": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "homeRadiusMeters": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "passwordHash": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/domain.Role"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.Status"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AcknowledgeTimesheetRequest": {
+            "type": "object",
+            "required": [
+                "period"
+            ],
+            "properties": {
+                "period": {
+                    "description": "Format: YYYY-MM",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ActivateProfile": {
+            "type": "object",
+            "required": [
+                "first_name"
+            ],
+            "properties": {
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ActivateUserRequest": {
+            "type": "object",
+            "required": [
+                "activation_token",
+                "password",
+                "profile"
+            ],
+            "properties": {
+                "activation_token": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "profile": {
+                    "$ref": "#/definitions/dto.ActivateProfile"
+                }
+            }
+        },
+        "dto.AdminCreateAttendanceRequest": {
+            "type": "object",
+            "required": [
+                "check_in_time",
+                "date",
+                "reason",
+                "user_id"
+            ],
+            "properties": {
+                "check_in_time": {
+                    "type": "string"
+                },
+                "check_out_time": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "Format: YYYY-MM-DD (shift start day)",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminUpdateAttendanceRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "check_in_time": {
+                    "type": "string"
+                },
+                "check_out_time": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AgencyResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "geofence_strictness": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_location_accuracy_meters": {
+                    "type": "integer"
+                },
+                "min_overtime_minutes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "require_on_site_location": {
+                    "type": "boolean"
+                },
+                "time_bank_enabled": {
+                    "type": "boolean"
+                },
+                "time_bank_expiry_days": {
+                    "type": "integer"
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "work_rounding_minutes": {
+                    "type": "integer"
+                },
+                "work_rounding_mode": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AssignRotationRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "offset_days": {
+                    "description": "Days the user is ahead in the cycle",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "start_date": {
+                    "description": "Format: YYYY-MM-DD. Defaults to today",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AttendanceResponse": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number"
+                },
+                "agency_id": {
+                    "type": "string"
+                },
+                "check_in_time": {
+                    "type": "string"
+                },
+                "check_out_time": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "deficit_minutes": {
+                    "type": "integer"
+                },
+                "entry_status": {
+                    "type": "string"
+                },
+                "exit_status": {
+                    "type": "string"
+                },
+                "flagged_for_review": {
+                    "type": "boolean"
+                },
+                "flex_band_end": {
+                    "type": "string"
+                },
+                "flex_band_start": {
+                    "description": "Flexible schedules: schedule entry/exit are the core time and only work inside the band counts",
+                    "type": "string"
+                },
+                "fraud_score": {
+                    "type": "integer"
+                },
+                "fraud_signals": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "method_in": {
+                    "type": "string"
+                },
+                "method_out": {
+                    "type": "string"
+                },
+                "nfc_tag_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "overtime_minutes": {
+                    "type": "integer"
+                },
+                "punches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PunchResponse"
+                    }
+                },
+                "required_minutes": {
+                    "type": "integer"
+                },
+                "schedule_entry_time": {
+                    "type": "string"
+                },
+                "schedule_exit_time": {
+                    "type": "string"
+                },
+                "scheduled_minutes": {
+                    "type": "integer"
+                },
+                "site": {
+                    "type": "string"
+                },
+                "site_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "void_reason": {
+                    "type": "string"
+                },
+                "voided_at": {
+                    "type": "string"
+                },
+                "worked_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AttendanceRevisionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "attendance_id": {
+                    "type": "string"
+                },
+                "changed_by_id": {
+                    "type": "string"
+                },
+                "correction_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "leave_request_id": {
+                    "type": "string"
+                },
+                "new_check_in_time": {
+                    "type": "string"
+                },
+                "new_check_out_time": {
+                    "type": "string"
+                },
+                "new_entry_status": {
+                    "type": "string"
+                },
+                "new_exit_status": {
+                    "type": "string"
+                },
+                "new_notes": {
+                    "type": "string"
+                },
+                "previous_check_in_time": {
+                    "type": "string"
+                },
+                "previous_check_out_time": {
+                    "type": "string"
+                },
+                "previous_entry_status": {
+                    "type": "string"
+                },
+                "previous_exit_status": {
+                    "type": "string"
+                },
+                "previous_notes": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CorrectionResponse": {
+            "type": "object",
+            "properties": {
+                "agency_id": {
+                    "type": "string"
+                },
+                "attendance_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "punch_id": {
+                    "type": "string"
+                },
+                "punch_type": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_time": {
+                    "type": "string"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateCorrectionRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "requested_time",
+                "type"
+            ],
+            "properties": {
+                "attendance_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "Format: YYYY-MM-DD. Required when attendance_id is empty",
+                    "type": "string"
+                },
+                "punch_id": {
+                    "type": "string"
+                },
+                "punch_type": {
+                    "type": "string",
+                    "enum": [
+                        "in",
+                        "out",
+                        "break_start",
+                        "break_end"
+                    ]
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_time": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "missing_punch",
+                        "wrong_time"
+                    ]
+                }
+            }
+        },
+        "dto.CreateHolidayRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "name"
+            ],
+            "properties": {
+                "date": {
+                    "description": "Format: YYYY-MM-DD",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "recurring": {
+                    "description": "Repeats every year on the same day and month",
+                    "type": "boolean"
+                },
+                "type": {
+                    "description": "Defaults to holiday",
+                    "type": "string",
+                    "enum": [
+                        "holiday",
+                        "closure"
+                    ]
+                }
+            }
+        },
+        "dto.CreateLeaveAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "days",
+                "leave_type_id",
+                "note",
+                "user_id"
+            ],
+            "properties": {
+                "date": {
+                    "description": "Format: YYYY-MM-DD. Defaults to today",
+                    "type": "string"
+                },
+                "days": {
+                    "description": "Positive adds, negative subtracts",
+                    "type": "number"
+                },
+                "leave_type_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateLeaveRequest": {
+            "type": "object",
+            "required": [
+                "leave_type_id",
+                "reason",
+                "start_date"
+            ],
+            "properties": {
+                "end_date": {
+                    "description": "Format: YYYY-MM-DD, inclusive. Defaults to start_date",
+                    "type": "string"
+                },
+                "half_day": {
+                    "description": "Only for single-day requests. Empty = full day",
+                    "type": "string",
+                    "enum": [
+                        "morning",
+                        "afternoon"
+                    ]
+                },
+                "leave_type_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "description": "Format: YYYY-MM-DD",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateLeaveTypeRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "accrual_days": {
+                    "description": "Per month (monthly) or per year (anniversary)",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "accrual_type": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "monthly",
+                        "anniversary"
+                    ]
+                },
+                "carry_over_cap_days": {
+                    "description": "Max days kept on each hire anniversary. null = no cap",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "carry_over_expiry_months": {
+                    "description": "Carried days expire this many months into the new year. 0 = never",
+                    "type": "integer",
+                    "maximum": 11,
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paid": {
+                    "description": "Defaults to true",
+                    "type": "boolean"
+                },
+                "track_balance": {
+                    "description": "Requests consume a per-user balance",
+                    "type": "boolean"
+                },
+                "uses_time_bank": {
+                    "description": "Comp time: approved requests debit their scheduled minutes from the time bank",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CreateNFCTagRequest": {
+            "type": "object",
+            "required": [
+                "label",
+                "uid"
+            ],
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "site_id": {
+                    "description": "Site where the tag is installed",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "e.g. \"04:A2:3B:C1:5D:80\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateScheduleOverrideRequest": {
+            "type": "object",
+            "required": [
+                "entry_time_minutes",
+                "exit_time_minutes",
+                "name",
+                "start_date"
+            ],
+            "properties": {
+                "end_date": {
+                    "description": "Format: YYYY-MM-DD, inclusive. Defaults to start_date",
+                    "type": "string"
+                },
+                "entry_time_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
+                },
+                "exit_time_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
+                },
+                "grace_period_minutes": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "description": "Format: YYYY-MM-DD",
+                    "type": "string"
+                },
+                "user_ids": {
+                    "description": "Empty applies to the whole agency",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CreateScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "assigned_users_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "core_end_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
+                },
+                "core_start_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
+                },
+                "days_of_week": {
+                    "description": "0 = Sunday",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "early_leave_tolerance_minutes": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "effective_from": {
+                    "description": "Format: YYYY-MM-DD. Empty applies to any date",
+                    "type": "string"
+                },
+                "entry_time_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
+                },
+                "exit_time_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
+                },
+                "grace_period_minutes": {
+                    "type": "integer"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required_minutes": {
+                    "description": "Minutes to work per day",
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 0
+                },
+                "type": {
+                    "description": "Flexible: entry/exit are the band where work counts, lateness is judged against the core time",
+                    "type": "string",
+                    "enum": [
+                        "fixed",
+                        "flexible"
+                    ]
+                }
+            }
+        },
+        "dto.CreateShiftRotationRequest": {
+            "type": "object",
+            "required": [
+                "anchor_date",
+                "name",
+                "shifts"
+            ],
+            "properties": {
+                "anchor_date": {
+                    "description": "Format: YYYY-MM-DD. First day of the cycle for offset 0",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "shifts": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.RotationShiftRequest"
+                    }
+                }
+            }
+        },
+        "dto.CreateSiteRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "name": {
+                    "type": "string"
+                },
+                "polygon": {
+                    "type": "array",
+                    "minItems": 3,
+                    "items": {
+                        "$ref": "#/definitions/dto.GeoPointRequest"
+                    }
+                },
+                "radius_meters": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "dto.CreateTimeBankAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "minutes",
+                "note",
+                "user_id"
+            ],
+            "properties": {
+                "date": {
+                    "description": "Format: YYYY-MM-DD. Defaults to today",
+                    "type": "string"
+                },
+                "minutes": {
+                    "description": "Positive credits, negative debits",
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GeoPointRequest": {
+            "type": "object",
+            "required": [
+                "latitude",
+                "longitude"
+            ],
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                }
+            }
+        },
+        "dto.HolidayImportResponse": {
+            "type": "object",
+            "properties": {
+                "holidays": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.HolidayResponse"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Dates already in the calendar",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.HolidayResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "recurring": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.InviteUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "hire_date": {
+                    "description": "Format: YYYY-MM-DD. Leave accrual starts here; defaults to the invitation date",
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LeaveBalanceEntryResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "days": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "leave_request_id": {
+                    "type": "string"
+                },
+                "leave_type_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LeaveBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "As of today",
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "leave_type": {
+                    "$ref": "#/definitions/dto.LeaveTypeResponse"
+                },
+                "pending_days": {
+                    "description": "Requested in pending requests, not yet deducted",
+                    "type": "number"
+                },
+                "projected_balance": {
+                    "description": "As of date, with accruals, grants, caps and expiries up to then",
+                    "type": "number"
+                },
+                "projection": {
+                    "description": "Movements after today up to date; planned ones have no id",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LeaveBalanceEntryResponse"
+                    }
+                }
+            }
+        },
+        "dto.LeaveRequestResponse": {
+            "type": "object",
+            "properties": {
+                "agency_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "days": {
+                    "description": "Working days covered according to the user's schedules",
+                    "type": "number"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "half_day": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "leave_type": {
+                    "$ref": "#/definitions/dto.LeaveTypeResponse"
+                },
+                "minutes": {
+                    "description": "Scheduled minutes on those days",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LeaveTypeResponse": {
+            "type": "object",
+            "properties": {
+                "accrual_days": {
+                    "type": "number"
+                },
+                "accrual_type": {
+                    "type": "string"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "carry_over_cap_days": {
+                    "type": "number"
+                },
+                "carry_over_expiry_months": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paid": {
+                    "type": "boolean"
+                },
+                "track_balance": {
+                    "type": "boolean"
+                },
+                "tracked_since": {
+                    "description": "Format: YYYY-MM-DD. Automatic movements start on this date",
+                    "type": "string"
+                },
+                "uses_time_bank": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.LoginUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "dto.MarkAttendanceRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "accuracy": {
+                    "description": "Horizontal accuracy radius in meters",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "agency_id": {
+                    "type": "string"
+                },
+                "is_remote": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "nfc_tag_uid": {
+                    "description": "Required when method is \"nfc\"",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "qr_token": {
+                    "description": "Required when method is \"qr\"",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "in",
+                        "out",
+                        "break_start",
+                        "break_end"
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NFCTagResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "site": {
+                    "description": "Site name",
+                    "type": "string"
+                },
+                "site_id": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NFCTagUsageResponse": {
+            "type": "object",
+            "properties": {
+                "attendance_id": {
+                    "type": "string"
+                },
+                "nfc_tag_id": {
+                    "type": "string"
+                },
+                "punch_id": {
+                    "type": "string"
+                },
+                "site": {
+                    "description": "Site name when the punch was registered",
+                    "type": "string"
+                },
+                "site_id": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PunchResponse": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number"
+                },
+                "fraud_signals": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "nfc_tag_id": {
+                    "type": "string"
+                },
+                "site": {
+                    "type": "string"
+                },
+                "site_id": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.QRTokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "refresh_at": {
+                    "description": "Time to fetch the next code",
+                    "type": "string"
+                },
+                "rotation_seconds": {
+                    "type": "integer"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RegisterAgencyRequest": {
+            "type": "object",
+            "required": [
+                "admin_email",
+                "domain",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "admin_email": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "description": "IANA name, defaults to UTC",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ResolveFlagRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReviewCorrectionRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReviewLeaveRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RotationAssignmentResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "description": "Inclusive. null = open-ended",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "offset_days": {
+                    "type": "integer"
+                },
+                "rotation_id": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RotationDayResponse": {
+            "type": "object",
+            "properties": {
+                "assignment_id": {
+                    "type": "string"
+                },
+                "cycle_day": {
+                    "description": "1 = first day of the cycle",
+                    "type": "integer"
+                },
+                "rotation_id": {
+                    "type": "string"
+                },
+                "rotation_name": {
+                    "type": "string"
+                },
+                "shift_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RotationShiftRequest": {
+            "type": "object",
+            "required": [
+                "days",
+                "name"
+            ],
+            "properties": {
+                "days": {
+                    "description": "Consecutive days of this shift in the cycle",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "early_leave_tolerance_minutes": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "entry_time_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
+                },
+                "exit_time_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
                 },
-                "domain": {
-                    "type": "string"
+                "grace_period_minutes": {
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "id": {
+                "name": {
                     "type": "string"
                 },
-                "isActive": {
+                "off": {
+                    "description": "Rest days: times are ignored",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.RotationShiftResponse": {
+            "type": "object",
+            "properties": {
+                "crosses_midnight": {
                     "type": "boolean"
                 },
-                "name": {
-                    "type": "string"
+                "days": {
+                    "type": "integer"
                 },
-                "phone": {
-                    "type": "string"
+                "early_leave_tolerance_minutes": {
+                    "type": "integer"
                 },
-                "updatedAt": {
+                "entry_time_minutes": {
+                    "type": "integer"
+                },
+                "exit_time_minutes": {
+                    "type": "integer"
+                },
+                "grace_period_minutes": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 },
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.User"
-                    }
+                "off": {
+                    "type": "boolean"
                 }
             }
         },
-        "domain.Attendance": {
+        "dto.ScheduleConflictResponse": {
             "type": "object",
             "properties": {
-                "agency": {
-                    "$ref": "#/definitions/domain.Agency"
+                "days_of_week": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "agencyID": {
+                "from": {
+                    "description": "First date both apply (from today on)",
                     "type": "string"
                 },
-                "checkInTime": {
-                    "description": "Si se crea al entrar, es obligatorio",
-                    "type": "string"
+                "schedules": {
+                    "description": "Oldest first; it is the one applied meanwhile",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ScheduleRefResponse"
+                    }
                 },
-                "checkOutTime": {
-                    "description": "Puede ser NULL hasta que salgan",
+                "to": {
+                    "description": "Last date both apply. null = open-ended",
                     "type": "string"
                 },
-                "createdAt": {
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponse"
+                }
+            }
+        },
+        "dto.ScheduleOverrideResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "date": {
+                "created_by_id": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "crosses_midnight": {
+                    "type": "boolean"
                 },
-                "latitude": {
-                    "description": "Geolocation",
-                    "type": "number",
-                    "format": "float64"
+                "end_date": {
+                    "type": "string"
                 },
-                "longitude": {
-                    "type": "number",
-                    "format": "float64"
+                "entry_time_minutes": {
+                    "type": "integer"
                 },
-                "methodIn": {
-                    "type": "string"
+                "exit_time_minutes": {
+                    "type": "integer"
                 },
-                "methodOut": {
-                    "description": "Opcional hasta el checkout",
-                    "type": "string"
+                "grace_period_minutes": {
+                    "type": "integer"
                 },
-                "notes": {
+                "id": {
                     "type": "string"
                 },
-                "scheduleEntryTime": {
+                "name": {
                     "type": "string"
                 },
-                "scheduleExitTime": {
+                "start_date": {
                     "type": "string"
                 },
-                "status": {
+                "user_ids": {
+                    "description": "Empty when it applies to the whole agency",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ScheduleRefResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "name": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/domain.User"
-                },
-                "userID": {
-                    "type": "string"
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.Role": {
-            "type": "string",
-            "enum": [
-                "admin",
-                "employee"
-            ],
-            "x-enum-varnames": [
-                "RoleAdmin",
-                "RoleEmployee"
-            ]
-        },
-        "domain.Schedule": {
+        "dto.ScheduleResponse": {
             "type": "object",
             "properties": {
-                "agency": {
-                    "$ref": "#/definitions/domain.Agency"
+                "agency_id": {
+                    "type": "string"
                 },
-                "agencyID": {
+                "assigned_users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserResponse"
+                    }
+                },
+                "core_end_minutes": {
+                    "type": "integer"
+                },
+                "core_start_minutes": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "assignedUsers": {
+                "crosses_midnight": {
+                    "type": "boolean"
+                },
+                "days_of_week": {
+                    "description": "El cliente deberia recibir un arreglo de enteros para los dias de la semana",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.User"
+                        "type": "integer"
                     }
                 },
-                "createdAt": {
+                "early_leave_tolerance_minutes": {
+                    "type": "integer"
+                },
+                "effective_from": {
+                    "description": "Format: YYYY-MM-DD, null = no start limit",
                     "type": "string"
                 },
-                "daysOfWeek": {
-                    "description": "Recomendación: String para evitar líos de drivers con arrays",
+                "effective_to": {
+                    "description": "Format: YYYY-MM-DD, inclusive. null = open-ended",
                     "type": "string"
                 },
-                "entryTimeMinutes": {
+                "entry_time_minutes": {
                     "type": "integer"
                 },
-                "exitTimeMinutes": {
+                "exit_time_minutes": {
                     "type": "integer"
                 },
-                "gracePeriodMinutes": {
+                "grace_period_minutes": {
                     "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
-                "isDefault": {
+                "is_default": {
                     "type": "boolean"
                 },
+                "leave": {
+                    "description": "Permiso aprobado de medio día que recortó el turno de la fecha consultada (solo en /schedules/applicable)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.LeaveRequestResponse"
+                        }
+                    ]
+                },
                 "name": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "override": {
+                    "description": "Excepción que reemplazó entrada, salida y tolerancia en la fecha consultada (solo en /schedules/applicable)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.ScheduleOverrideResponse"
+                        }
+                    ]
+                },
+                "required_minutes": {
+                    "description": "Minutes to work per day",
+                    "type": "integer"
+                },
+                "rotation": {
+                    "description": "Turno de la rotación asignada que define la fecha consultada (solo en /schedules/applicable)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.RotationDayResponse"
+                        }
+                    ]
+                },
+                "series_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Jornada flexible: entrada y salida son la banda, el atraso se evalúa contra el tiempo núcleo",
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.Status": {
-            "type": "string",
-            "enum": [
-                "pending",
-                "active",
-                "inactive"
-            ],
-            "x-enum-varnames": [
-                "StatusPending",
-                "StatusActive",
-                "StatusInactive"
-            ]
-        },
-        "domain.User": {
+        "dto.ShiftRotationResponse": {
             "type": "object",
             "properties": {
-                "activationCode": {
+                "anchor_date": {
                     "type": "string"
                 },
-                "agency": {
-                    "$ref": "#/definitions/domain.Agency"
+                "created_at": {
+                    "type": "string"
                 },
-                "agencyID": {
+                "cycle_days": {
+                    "type": "integer"
+                },
+                "id": {
                     "type": "string"
                 },
-                "codeExpiry": {
+                "name": {
                     "type": "string"
                 },
-                "createdAt": {
+                "shifts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RotationShiftResponse"
+                    }
+                }
+            }
+        },
+        "dto.SiteResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "email": {
+                "id": {
                     "type": "string"
                 },
-                "firstName": {
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
                     "type": "string"
                 },
-                "homeLatitude": {
-                    "type": "number",
-                    "format": "float64"
+                "polygon": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.GeoPoint"
+                    }
                 },
-                "homeLongitude": {
-                    "type": "number",
-                    "format": "float64"
+                "radius_meters": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TimeBankBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "balance_minutes": {
+                    "description": "Negative when more was debited than credited",
+                    "type": "integer"
+                },
+                "expirations": {
+                    "description": "Upcoming expiries, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TimeBankExpirationResponse"
+                    }
+                },
+                "pending_minutes": {
+                    "description": "Requested as comp time in pending leave requests",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TimeBankEntryResponse": {
+            "type": "object",
+            "properties": {
+                "attendance_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "string"
                 },
-                "homeRadiusMeters": {
-                    "type": "integer"
+                "date": {
+                    "type": "string"
+                },
+                "expired_entry_id": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "lastName": {
+                "kind": {
                     "type": "string"
                 },
-                "passwordHash": {
+                "leave_request_id": {
                     "type": "string"
                 },
-                "role": {
-                    "$ref": "#/definitions/domain.Role"
+                "minutes": {
+                    "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/domain.Status"
+                "note": {
+                    "type": "string"
                 },
-                "updatedAt": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.ActivateProfile": {
+        "dto.TimeBankExpirationResponse": {
             "type": "object",
-            "required": [
-                "first_name"
-            ],
             "properties": {
-                "first_name": {
+                "date": {
+                    "description": "Format: YYYY-MM-DD",
                     "type": "string"
                 },
-                "last_name": {
-                    "type": "string"
+                "minutes": {
+                    "description": "Still unused credit that expires on date",
+                    "type": "integer"
                 }
             }
         },
-        "dto.ActivateUserRequest": {
+        "dto.TimesheetActionRequest": {
             "type": "object",
             "required": [
-                "activation_token",
-                "password",
-                "profile"
+                "period",
+                "user_id"
             ],
             "properties": {
-                "activation_token": {
+                "period": {
+                    "description": "Format: YYYY-MM",
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
-                },
-                "profile": {
-                    "$ref": "#/definitions/dto.ActivateProfile"
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "dto.AgencyResponse": {
+        "dto.TimesheetResponse": {
             "type": "object",
             "properties": {
-                "address": {
+                "acknowledged_at": {
                     "type": "string"
                 },
-                "created_at": {
+                "acknowledged_ip": {
                     "type": "string"
                 },
-                "domain": {
+                "changed": {
+                    "description": "Acknowledged, but the attendance changed since then: it must be acknowledged again",
+                    "type": "boolean"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TimesheetDay"
+                    }
+                },
+                "end_date": {
                     "type": "string"
                 },
                 "id": {
+                    "description": "null until the employee acknowledges it",
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "name": {
+                "locked_at": {
                     "type": "string"
                 },
-                "phone": {
+                "locked_by_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "period": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.CreateScheduleRequest": {
-            "type": "object",
-            "properties": {
-                "assigned_users_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "days_of_week": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
                 },
-                "entry_time_minutes": {
-                    "type": "integer"
-                },
-                "exit_time_minutes": {
-                    "type": "integer"
+                "start_date": {
+                    "type": "string"
                 },
-                "grace_period_minutes": {
-                    "type": "integer"
+                "status": {
+                    "type": "string"
                 },
-                "is_default": {
-                    "type": "boolean"
+                "totals": {
+                    "$ref": "#/definitions/domain.TimesheetTotals"
                 },
-                "name": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.InviteUserRequest": {
+        "dto.TimesheetSummaryResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "first_name"
-            ],
             "properties": {
-                "email": {
+                "acknowledged_at": {
                     "type": "string"
                 },
                 "first_name": {
@@ -1220,94 +7006,146 @@ const docTemplate = `{
                 },
                 "last_name": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.LoginUserRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
+                },
+                "locked_at": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "period": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "totals": {
+                    "description": "As acknowledged; null while open",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TimesheetTotals"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "dto.MarkAttendanceRequest": {
+        "dto.UpdateAgencyRequest": {
             "type": "object",
             "properties": {
-                "agency_id": {
+                "address": {
                     "type": "string"
                 },
-                "is_remote": {
-                    "type": "boolean"
+                "geofence_strictness": {
+                    "type": "string",
+                    "enum": [
+                        "overlap",
+                        "contain"
+                    ]
                 },
-                "latitude": {
-                    "type": "number"
+                "max_location_accuracy_meters": {
+                    "description": "0 = accuracy not required",
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "longitude": {
-                    "type": "number"
+                "min_overtime_minutes": {
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "method": {
+                "name": {
                     "type": "string"
                 },
-                "notes": {
+                "phone": {
                     "type": "string"
                 },
-                "type": {
-                    "type": "string"
+                "require_on_site_location": {
+                    "type": "boolean"
                 },
-                "user_id": {
+                "time_bank_enabled": {
+                    "description": "Bank overtime and early leaves as comp time",
+                    "type": "boolean"
+                },
+                "time_bank_expiry_days": {
+                    "description": "Unused credits expire after this many days. 0 = never",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "time_zone": {
                     "type": "string"
+                },
+                "work_rounding_minutes": {
+                    "type": "integer",
+                    "maximum": 60,
+                    "minimum": 0
+                },
+                "work_rounding_mode": {
+                    "type": "string",
+                    "enum": [
+                        "nearest",
+                        "floor",
+                        "ceil"
+                    ]
                 }
             }
         },
-        "dto.RegisterAgencyRequest": {
+        "dto.UpdateLeaveTypeRequest": {
             "type": "object",
-            "required": [
-                "admin_email",
-                "domain",
-                "name",
-                "password"
-            ],
             "properties": {
-                "address": {
-                    "type": "string"
+                "accrual_days": {
+                    "type": "number",
+                    "minimum": 0
                 },
-                "admin_email": {
-                    "type": "string"
+                "accrual_type": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "monthly",
+                        "anniversary"
+                    ]
                 },
-                "domain": {
-                    "type": "string"
+                "active": {
+                    "description": "Inactive types accept no new requests",
+                    "type": "boolean"
                 },
-                "name": {
-                    "type": "string"
+                "carry_over_cap_days": {
+                    "type": "number",
+                    "minimum": 0
                 },
-                "password": {
+                "carry_over_expiry_months": {
+                    "type": "integer",
+                    "maximum": 11,
+                    "minimum": 0
+                },
+                "name": {
                     "type": "string",
-                    "minLength": 8
+                    "minLength": 1
                 },
-                "phone": {
-                    "type": "string"
+                "paid": {
+                    "type": "boolean"
+                },
+                "remove_carry_over_cap": {
+                    "description": "Carry the whole balance over",
+                    "type": "boolean"
+                },
+                "track_balance": {
+                    "type": "boolean"
+                },
+                "uses_time_bank": {
+                    "type": "boolean"
                 }
             }
         },
-        "dto.UpdateAgencyRequest": {
+        "dto.UpdateNFCTagRequest": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
+                "active": {
+                    "type": "boolean"
                 },
-                "name": {
-                    "type": "string"
+                "label": {
+                    "type": "string",
+                    "minLength": 1
                 },
-                "phone": {
+                "site_id": {
+                    "description": "The nil UUID detaches the tag from its site",
                     "type": "string"
                 }
             }
@@ -1321,17 +7159,39 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "core_end_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
+                },
+                "core_start_minutes": {
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
+                },
                 "days_of_week": {
                     "type": "array",
                     "items": {
                         "type": "integer"
                     }
                 },
+                "early_leave_tolerance_minutes": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "effective_from": {
+                    "description": "Fecha desde la que rigen los cambios de días, horas o usuarios (YYYY-MM-DD). Por defecto, hoy",
+                    "type": "string"
+                },
                 "entry_time_minutes": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
                 },
                 "exit_time_minutes": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 1439,
+                    "minimum": 0
                 },
                 "grace_period_minutes": {
                     "type": "integer"
@@ -1341,6 +7201,103 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "required_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 0
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "fixed",
+                        "flexible"
+                    ]
+                }
+            }
+        },
+        "dto.UpdateSiteRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "polygon": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GeoPointRequest"
+                    }
+                },
+                "radius_meters": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "dto.UserResponse": {
+            "type": "object",
+            "properties": {
+                "agency_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "hire_date": {
+                    "description": "Format: YYYY-MM-DD",
+                    "type": "string"
+                },
+                "home_latitude": {
+                    "type": "number"
+                },
+                "home_longitude": {
+                    "type": "number"
+                },
+                "home_radius_meters": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.Status"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.VoidAttendanceRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
                 }
             }
         }
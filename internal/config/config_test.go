@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLocalTimeZone(t *testing.T) {
+	dir := t.TempDir()
+
+	symlink := filepath.Join(dir, "localtime-link")
+	if err := os.Symlink("/usr/share/zoneinfo/America/Santiago", symlink); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	copied := filepath.Join(dir, "localtime-copy")
+	if err := os.WriteFile(copied, []byte("TZif"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name      string
+		tz        *string
+		localtime string
+		want      string
+	}{
+		{"TZ", ptr("America/Bogota"), missing, "America/Bogota"},
+		{"TZ with colon", ptr(":Europe/Madrid"), missing, "Europe/Madrid"},
+		{"empty TZ", ptr(""), symlink, "UTC"},
+		{"symlinked localtime", nil, symlink, "America/Santiago"},
+		{"missing localtime", nil, missing, "UTC"},
+		{"copied localtime", nil, copied, ""},
+	}
+
+	// time.Local is named "Local" when it was loaded from TZ or /etc/localtime
+	local := time.Local
+	time.Local = time.FixedZone("Local", 0)
+	t.Cleanup(func() { time.Local = local })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TZ", "")
+			if tt.tz == nil {
+				os.Unsetenv("TZ")
+			} else {
+				os.Setenv("TZ", *tt.tz)
+			}
+
+			if got := localTimeZone(tt.localtime); got != tt.want {
+				t.Errorf("localTimeZone() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalTimeZoneNamedLocal(t *testing.T) {
+	t.Setenv("TZ", "")
+	os.Unsetenv("TZ")
+
+	local := time.Local
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	time.Local = santiago
+	t.Cleanup(func() { time.Local = local })
+
+	if got := localTimeZone(filepath.Join(t.TempDir(), "missing")); got != "America/Santiago" {
+		t.Errorf("localTimeZone() = %q, want the name of time.Local", got)
+	}
+}
+
+func ptr(s string) *string { return &s }
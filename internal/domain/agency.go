@@ -14,6 +14,24 @@ var (
 	ErrInvalidAgency      = errors.New("invalid agency")
 	ErrAgencyExists       = errors.New("agency already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidTimeZone    = errors.New("invalid time zone")
+)
+
+// RoundingMode define cómo se redondean los minutos trabajados al intervalo configurado en la agencia
+type RoundingMode string
+
+var (
+	RoundingNearest RoundingMode = "nearest"
+	RoundingFloor   RoundingMode = "floor"
+	RoundingCeil    RoundingMode = "ceil"
+)
+
+// GeofenceStrictness define cuándo una ubicación con margen de error se considera dentro de una geocerca
+type GeofenceStrictness string
+
+var (
+	GeofenceOverlap GeofenceStrictness = "overlap" // Basta con que el círculo de precisión toque la geocerca
+	GeofenceContain GeofenceStrictness = "contain" // El círculo de precisión debe quedar completo dentro
 )
 
 type Agency struct {
@@ -22,10 +40,48 @@ type Agency struct {
 	Domain    string    `gorm:"uniqueIndex;not null"`
 	Address   string
 	Phone     string
+	TimeZone  string `gorm:"not null;default:'UTC'"` // Nombre IANA, ej: "America/Santiago"
 	IsActive  bool   `gorm:"default:true"`
 	Users     []User `gorm:"foreignKey:AgencyID;"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// Reglas de cálculo de horas trabajadas
+	WorkRoundingMinutes int          `gorm:"not null;default:0"` // 0 = sin redondeo
+	WorkRoundingMode    RoundingMode `gorm:"not null;default:'nearest'"`
+	MinOvertimeMinutes  int          `gorm:"not null;default:0"` // Bajo este umbral el exceso no cuenta como horas extra
+
+	// Las marcas presenciales (no remotas) deben caer dentro de alguna sede activa
+	RequireOnSiteLocation bool `gorm:"not null;default:false"`
+
+	// Precisión de las ubicaciones: 0 = no se exige precisión
+	MaxLocationAccuracyMeters int                `gorm:"not null;default:0"`
+	GeofenceStrictness        GeofenceStrictness `gorm:"not null;default:'overlap'"`
+
+	// Bolsa de horas: acredita lo trabajado después de la salida y descuenta las salidas anticipadas
+	TimeBankEnabled    bool `gorm:"not null;default:false"`
+	TimeBankExpiryDays int  `gorm:"not null;default:0"` // Días en que vence cada crédito no usado; 0 = no vence
+}
+
+// ValidTimeZone indica si name es una zona IANA cargable. "Local" se rechaza porque dependería del servidor.
+func ValidTimeZone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// Location devuelve la zona horaria de la agencia. Fechas, horarios y atrasos se evalúan en ella.
+func (a *Agency) Location() *time.Location {
+	if a.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(a.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
 
 func (a *Agency) BeforeCreate(tx *gorm.DB) error {
@@ -40,5 +96,6 @@ type AgencyRepo interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*Agency, error)
 	GetByName(ctx context.Context, name string) (*Agency, error)
 	GetByDomain(ctx context.Context, domain string) (*Agency, error)
+	ListActive(ctx context.Context) ([]*Agency, error)
 	Update(ctx context.Context, agency *Agency) error
 }
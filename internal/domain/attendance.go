@@ -10,40 +10,91 @@ import (
 )
 
 var (
-	ErrAttendanceNotFound  = errors.New("attendance not found")
-	ErrNoAttendancesExist  = errors.New("this agency has no attendances")
-	ErrAttendanceExists    = errors.New("attendance already exists")
-	ErrAttendanceInvalid   = errors.New("invalid attendance")
-	ErrInvalidUserOrAgency = errors.New("invalid user or agency")
-	ErrGeofenceViolation   = errors.New("location out of range")
-	ErrManualNotAllowed    = errors.New("only admins can mark attendance manually")
-	ErrInvalidAttendance   = errors.New("invalid attendance data")
-	ErrHomeLocationNotSet  = errors.New("user does not have home location configured")
+	ErrAttendanceNotFound   = errors.New("attendance not found")
+	ErrNoAttendancesExist   = errors.New("this agency has no attendances")
+	ErrAttendanceExists     = errors.New("attendance already exists")
+	ErrAttendanceInvalid    = errors.New("invalid attendance")
+	ErrInvalidUserOrAgency  = errors.New("invalid user or agency")
+	ErrGeofenceViolation    = errors.New("location out of range")
+	ErrAccuracyRequired     = errors.New("location accuracy is required")
+	ErrLocationImprecise    = errors.New("location accuracy exceeds the allowed maximum")
+	ErrManualNotAllowed     = errors.New("only admins can mark attendance manually")
+	ErrInvalidAttendance    = errors.New("invalid attendance data")
+	ErrHomeLocationNotSet   = errors.New("user does not have home location configured")
+	ErrInvalidDateRange     = errors.New("invalid date range")
+	ErrInvalidPunchOrder    = errors.New("punch out of order")
+	ErrAttendanceVoided     = errors.New("attendance has been voided")
+	ErrAttendanceInFuture   = errors.New("attendance time is in the future")
+	ErrOutsideShiftWindow   = errors.New("punch time is outside the shift of the attendance date")
+	ErrAttendanceNotFlagged = errors.New("attendance is not flagged for review")
 )
 
 type Attendance struct {
 	ID                uuid.UUID         `gorm:"type:uuid;primaryKey"`
-	UserID            uuid.UUID         `gorm:"type:uuid;not null;index"`
+	UserID            uuid.UUID         `gorm:"type:uuid;not null;index;uniqueIndex:idx_attendance_user_date,where:voided_at IS NULL"`
 	User              User              `gorm:"foreignKey:UserID"`
 	AgencyID          uuid.UUID         `gorm:"type:uuid;not null;index"`
 	Agency            Agency            `gorm:"foreignKey:AgencyID"`
-	CheckInTime       time.Time         `gorm:"not null"` // Si se crea al entrar, es obligatorio
+	CheckInTime       *time.Time        // NULL cuando el registro es una ausencia generada por el sistema
 	ScheduleEntryTime time.Time         `gorm:"not null"`
-	Status            AttendanceStatus  `gorm:"not null"`
+	EntryStatus       AttendanceStatus  `gorm:"not null"`
 	CheckOutTime      *time.Time        // Puede ser NULL hasta que salgan
+	ExitStatus        *AttendanceStatus // NULL hasta el checkout
 	ScheduleExitTime  time.Time         `gorm:"not null"`
-	Date              time.Time         `gorm:"type:date;not null"`
+	Date              time.Time         `gorm:"type:date;not null;uniqueIndex:idx_attendance_user_date,where:voided_at IS NULL"` // Día en que comienza el turno
 	MethodIn          AttendanceMethod  `gorm:"not null"`
 	MethodOut         *AttendanceMethod // Opcional hasta el checkout
 	Notes             *string
+	Punches           []AttendancePunch `gorm:"foreignKey:AttendanceID"`
+	WorkedMinutes     int               `gorm:"not null;default:0"` // Suma de los intervalos entrada/salida, sin pausas
+	ScheduledMinutes  int               `gorm:"not null;default:0"` // Se calculan al hacer checkout
+	OvertimeMinutes   int               `gorm:"not null;default:0"`
+	DeficitMinutes    int               `gorm:"not null;default:0"`
+	NFCTagID          *uuid.UUID        `gorm:"type:uuid"` // Etiqueta de la entrada, si se marcó con nfc
+	Site              *string           // Nombre de la sede de SiteID al momento de la entrada
+	SiteID            *uuid.UUID        `gorm:"type:uuid;index"` // Sede de la etiqueta nfc, si no la que contiene la ubicación de la entrada
+	VoidedAt          *time.Time        // Un registro anulado se conserva para el historial pero no cuenta en listados ni reportes
+	VoidedByID        *uuid.UUID        `gorm:"type:uuid"`
+	VoidReason        *string
+	FraudScore        int           `gorm:"not null;default:0"` // Suma de los pesos de FraudSignals, máximo 100
+	FraudSignals      []FraudSignal `gorm:"serializer:json"`
+	FlaggedForReview  bool          `gorm:"not null;default:false;index"` // El puntaje alcanzó el umbral: un admin debe revisar el registro
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 
 	// Geolocation
 	Latitude  *float64
 	Longitude *float64
+	Accuracy  *float64 // Radio de precisión horizontal en metros informado por el dispositivo
+
+	// Jornada flexible: ScheduleEntryTime y ScheduleExitTime son el tiempo núcleo, solo cuenta lo trabajado
+	// dentro de la banda y se exigen RequiredMinutes en lugar de la duración del turno
+	FlexBandStart   *time.Time
+	FlexBandEnd     *time.Time
+	RequiredMinutes int `gorm:"not null;default:0"`
+}
+
+// AttendancePunch es cada marca individual dentro del registro diario (entrada, salida, inicio y fin de pausa).
+// CheckInTime y CheckOutTime de Attendance corresponden a la primera entrada y la última salida.
+type AttendancePunch struct {
+	ID           uuid.UUID        `gorm:"type:uuid;primaryKey"`
+	AttendanceID uuid.UUID        `gorm:"type:uuid;not null;index"`
+	Type         AttendanceType   `gorm:"not null"`
+	Time         time.Time        `gorm:"not null"`
+	Method       AttendanceMethod `gorm:"not null"`
+	Latitude     *float64
+	Longitude    *float64
+	Accuracy     *float64
+	NFCTagID     *uuid.UUID    `gorm:"type:uuid;index"`
+	Site         *string       // Copia del nombre de la sede de SiteID al momento de marcar
+	SiteID       *uuid.UUID    `gorm:"type:uuid"` // Sede de la etiqueta nfc, si no la que contiene la ubicación de la marca
+	FraudSignals []FraudSignal `gorm:"serializer:json"`
+	CreatedAt    time.Time
 }
 
+// Un mismo día puede tener atraso en la entrada y salida anticipada, por eso
+// Attendance guarda un resultado para la entrada (present, late, absent) y otro
+// para la salida (on_time, early, short_hours, absent).
 type AttendanceStatus string
 
 var (
@@ -51,6 +102,9 @@ var (
 	StatusAbsent  AttendanceStatus = "absent"
 	StatusLate    AttendanceStatus = "late"
 	StatusEarly   AttendanceStatus = "early"
+	StatusOnTime  AttendanceStatus = "on_time"
+	// Jornada flexible: salió después del tiempo núcleo pero sin completar los minutos exigidos
+	StatusShortHours AttendanceStatus = "short_hours"
 )
 
 type AttendanceMethod string
@@ -60,13 +114,16 @@ var (
 	MethodQR       AttendanceMethod = "qr"
 	MethodTelework AttendanceMethod = "telework"
 	MethodNFC      AttendanceMethod = "nfc"
+	MethodSystem   AttendanceMethod = "system" // Registros generados por jobs (ej: ausencias)
 )
 
 type AttendanceType string
 
 var (
-	TypeIn  AttendanceType = "in"
-	TypeOut AttendanceType = "out"
+	TypeIn         AttendanceType = "in"
+	TypeOut        AttendanceType = "out"
+	TypeBreakStart AttendanceType = "break_start"
+	TypeBreakEnd   AttendanceType = "break_end"
 )
 
 func (a *Attendance) BeforeCreate(tx *gorm.DB) error {
@@ -76,20 +133,42 @@ func (a *Attendance) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (p *AttendancePunch) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
 type AttendanceFilter struct {
-	UserID    uuid.UUID
-	StartDate *time.Time
-	EndDate   *time.Time
-	Status    AttendanceStatus
-	Page      int
-	Limit     int
+	UserID      uuid.UUID
+	StartDate   *time.Time
+	EndDate     *time.Time
+	Status      AttendanceStatus // Coincide con la entrada o con la salida
+	EntryStatus AttendanceStatus
+	ExitStatus  AttendanceStatus
+	Flagged     *bool
+	Page        int
+	Limit       int
 }
 
 type AttendanceRepo interface {
 	Create(ctx context.Context, attendance *Attendance) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Attendance, error)
-	GetTodayByUserID(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID) (*Attendance, error)
+	// GetByIDForUpdate bloquea la fila (SELECT ... FOR UPDATE) hasta que termine la transacción del contexto
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*Attendance, error)
+	GetOpenByUserID(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, since time.Time) (*Attendance, error)
+	GetByUserAndDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*Attendance, error)
+	ExistsByUserAndDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (bool, error)
 	List(ctx context.Context, agencyID uuid.UUID, filter AttendanceFilter) ([]*Attendance, error)
+	// ListInBatches recorre todos los registros del filtro (ignora la paginación) en lotes de batchSize, por fecha
+	ListInBatches(ctx context.Context, agencyID uuid.UUID, filter AttendanceFilter, batchSize int, fn func([]*Attendance) error) error
 	Update(ctx context.Context, attendance *Attendance) error
+	AddPunch(ctx context.Context, punch *AttendancePunch) error
+	UpdatePunch(ctx context.Context, punch *AttendancePunch) error
+	// GetLastLocatedPunch devuelve la última marca con coordenadas del usuario anterior a before, o nil
+	GetLastLocatedPunch(ctx context.Context, userID uuid.UUID, before time.Time) (*AttendancePunch, error)
+	// CountPunchesAtLocation cuenta las marcas del usuario anteriores a before con exactamente esas coordenadas
+	CountPunchesAtLocation(ctx context.Context, userID uuid.UUID, lat float64, lng float64, before time.Time) (int64, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCorrectionNotFound    = errors.New("correction request not found")
+	ErrCorrectionNotPending  = errors.New("correction request already reviewed")
+	ErrInvalidCorrection     = errors.New("invalid correction request")
+	ErrPunchNotFound         = errors.New("punch not found")
+	ErrCorrectionInTheFuture = errors.New("requested time is in the future")
+)
+
+type CorrectionType string
+
+var (
+	CorrectionMissingPunch CorrectionType = "missing_punch" // Agrega una marca olvidada
+	CorrectionWrongTime    CorrectionType = "wrong_time"    // Cambia la hora de una marca existente
+)
+
+type CorrectionStatus string
+
+var (
+	CorrectionPending  CorrectionStatus = "pending"
+	CorrectionApproved CorrectionStatus = "approved"
+	CorrectionRejected CorrectionStatus = "rejected"
+)
+
+// AttendanceCorrection es la solicitud de un empleado para corregir su asistencia.
+// Solo al aprobarse se aplica sobre las marcas del registro diario.
+type AttendanceCorrection struct {
+	ID            uuid.UUID        `gorm:"type:uuid;primaryKey"`
+	AgencyID      uuid.UUID        `gorm:"type:uuid;not null;index"`
+	UserID        uuid.UUID        `gorm:"type:uuid;not null;index"`
+	User          User             `gorm:"foreignKey:UserID"`
+	AttendanceID  *uuid.UUID       `gorm:"type:uuid;index"` // NULL si ese día no hay registro (ej: olvidó la entrada)
+	Date          time.Time        `gorm:"type:date;not null"`
+	Type          CorrectionType   `gorm:"not null"`
+	PunchType     *AttendanceType  // missing_punch: tipo de la marca faltante
+	PunchID       *uuid.UUID       `gorm:"type:uuid"` // wrong_time: marca a corregir
+	RequestedTime time.Time        `gorm:"not null"`
+	Reason        string           `gorm:"not null"`
+	Status        CorrectionStatus `gorm:"not null;default:'pending'"`
+	ReviewerID    *uuid.UUID       `gorm:"type:uuid"`
+	ReviewNote    *string
+	ReviewedAt    *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (c *AttendanceCorrection) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+type RevisionAction string
+
+var (
+	RevisionCorrectionApproved RevisionAction = "correction_approved"
+	RevisionAdminCreated       RevisionAction = "admin_created"
+	RevisionAdminEdited        RevisionAction = "admin_edited"
+	RevisionVoided             RevisionAction = "voided"
+	RevisionLeaveApproved      RevisionAction = "leave_approved" // Ausencia anulada al aprobarse un permiso para ese día
+	RevisionFlagResolved       RevisionAction = "flag_resolved"  // Un admin revisó los indicios de ubicación falsa y quitó la marca
+)
+
+// AttendanceRevision guarda el historial de cambios aplicados a una asistencia ya registrada:
+// quién lo hizo, por qué y los valores anteriores y nuevos.
+type AttendanceRevision struct {
+	ID                   uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	AttendanceID         uuid.UUID      `gorm:"type:uuid;not null;index"`
+	AgencyID             uuid.UUID      `gorm:"type:uuid;not null;index"`
+	ChangedByID          uuid.UUID      `gorm:"type:uuid;not null"`
+	Action               RevisionAction `gorm:"not null"`
+	Reason               string         `gorm:"not null"`
+	CorrectionID         *uuid.UUID     `gorm:"type:uuid"`
+	LeaveRequestID       *uuid.UUID     `gorm:"type:uuid"`
+	PreviousCheckInTime  *time.Time
+	PreviousCheckOutTime *time.Time
+	PreviousEntryStatus  AttendanceStatus
+	PreviousExitStatus   *AttendanceStatus
+	PreviousNotes        *string
+	NewCheckInTime       *time.Time
+	NewCheckOutTime      *time.Time
+	NewEntryStatus       AttendanceStatus
+	NewExitStatus        *AttendanceStatus
+	NewNotes             *string
+	CreatedAt            time.Time
+}
+
+func (r *AttendanceRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+type CorrectionFilter struct {
+	UserID uuid.UUID
+	Status CorrectionStatus
+	Page   int
+	Limit  int
+}
+
+type CorrectionRepo interface {
+	Create(ctx context.Context, correction *AttendanceCorrection) error
+	GetByID(ctx context.Context, id uuid.UUID) (*AttendanceCorrection, error)
+	// GetByIDForUpdate bloquea la fila (SELECT ... FOR UPDATE) hasta que termine la transacción del contexto
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*AttendanceCorrection, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter CorrectionFilter) ([]*AttendanceCorrection, error)
+	Update(ctx context.Context, correction *AttendanceCorrection) error
+}
+
+type AttendanceRevisionRepo interface {
+	Create(ctx context.Context, revision *AttendanceRevision) error
+	ListByAttendanceID(ctx context.Context, attendanceID uuid.UUID) ([]*AttendanceRevision, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+// FraudSignal es un indicio de que la ubicación enviada por el dispositivo es falsa
+type FraudSignal string
+
+var (
+	SignalImpossibleTravel    FraudSignal = "impossible_travel"    // Distancia a la marca anterior imposible de recorrer en el tiempo transcurrido
+	SignalReusedCoordinates   FraudSignal = "reused_coordinates"   // Exactamente las mismas coordenadas que una marca de otro día
+	SignalSuspiciousPrecision FraudSignal = "suspicious_precision" // Precisión informada mejor de lo que un GPS real puede lograr
+)
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrHolidayNotFound = errors.New("holiday not found")
+	ErrHolidayExists   = errors.New("holiday already exists for that date")
+	ErrInvalidHoliday  = errors.New("invalid holiday date")
+	ErrNonWorkingDay   = errors.New("no work expected on this date")
+	ErrInvalidCalendar = errors.New("invalid calendar file")
+)
+
+// HolidayType distingue los feriados de los cierres propios de la agencia (ej: vacaciones colectivas)
+type HolidayType string
+
+var (
+	HolidayPublic  HolidayType = "holiday"
+	HolidayClosure HolidayType = "closure"
+)
+
+// Holiday es un día del calendario de la agencia en que no se espera que nadie trabaje.
+// Si es recurrente se repite cada año en el mismo día y mes a partir de Date.
+type Holiday struct {
+	ID        uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	AgencyID  uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_holiday_agency_date"`
+	Date      time.Time   `gorm:"type:date;not null;uniqueIndex:idx_holiday_agency_date"`
+	Name      string      `gorm:"not null"`
+	Type      HolidayType `gorm:"not null;default:'holiday'"`
+	Recurring bool        `gorm:"not null;default:false"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (h *Holiday) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
+
+// NonWorkingDayError se devuelve al resolver el horario de una fecha marcada en el calendario de la agencia
+type NonWorkingDayError struct {
+	Holiday *Holiday
+}
+
+func (e *NonWorkingDayError) Error() string {
+	return fmt.Sprintf("no work expected on this date: %s", e.Holiday.Name)
+}
+
+func (e *NonWorkingDayError) Unwrap() error {
+	return ErrNonWorkingDay
+}
+
+type HolidayFilter struct {
+	Year  int // Feriados de ese año, incluidos los recurrentes de años anteriores
+	Page  int
+	Limit int
+}
+
+type HolidayRepo interface {
+	Create(ctx context.Context, holiday *Holiday) error
+	// CreateBatch inserta los feriados ignorando las fechas que ya existen; devuelve los que se crearon
+	CreateBatch(ctx context.Context, holidays []*Holiday) ([]*Holiday, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Holiday, error)
+	// FindByDate devuelve el feriado de esa fecha (exacto o recurrente), o nil si es un día normal
+	FindByDate(ctx context.Context, agencyID uuid.UUID, date time.Time) (*Holiday, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter HolidayFilter) ([]*Holiday, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
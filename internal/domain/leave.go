@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLeaveTypeNotFound    = errors.New("leave type not found")
+	ErrLeaveTypeExists      = errors.New("leave type already exists")
+	ErrLeaveTypeInactive    = errors.New("leave type is inactive")
+	ErrLeaveRequestNotFound = errors.New("leave request not found")
+	ErrLeaveNotPending      = errors.New("leave request already reviewed")
+	ErrInvalidLeaveRequest  = errors.New("invalid leave request")
+	ErrLeaveOverlap         = errors.New("leave request overlaps another request")
+	ErrOnLeave              = errors.New("user is on leave on this date")
+)
+
+// LeaveType es una clase de permiso definida por la agencia (ej: vacaciones, licencia médica, día personal)
+type LeaveType struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey"`
+	AgencyID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_leave_type_agency_name"`
+	Name     string    `gorm:"not null;uniqueIndex:idx_leave_type_agency_name"`
+	Paid     bool      `gorm:"not null;default:true"`
+	Active   bool      `gorm:"not null;default:true"` // Los inactivos no admiten nuevas solicitudes
+
+	// Política de saldo. El año de permisos de cada usuario empieza en su aniversario de contratación.
+	TrackBalance          bool         `gorm:"not null;default:false"` // Si es false las solicitudes no consumen saldo
+	TrackedSince          *time.Time   `gorm:"type:date"`              // Primer día en que se generan movimientos automáticos
+	AccrualType           LeaveAccrual `gorm:"not null;default:'none'"`
+	AccrualDays           float64      `gorm:"not null;default:0"` // Días por mes (monthly) o por año (anniversary)
+	CarryOverCapDays      *float64     // Máximo que pasa al año siguiente; nil = sin tope
+	CarryOverExpiryMonths int          `gorm:"not null;default:0"`     // Meses del año nuevo en que vence lo arrastrado; 0 = no vence
+	UsesTimeBank          bool         `gorm:"not null;default:false"` // Tiempo compensatorio: se descuenta de la bolsa de horas
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+func (t *LeaveType) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// LeaveAccrual indica cómo se acumula el saldo de un tipo de permiso
+type LeaveAccrual string
+
+var (
+	AccrualNone        LeaveAccrual = "none"        // Solo ajustes manuales
+	AccrualMonthly     LeaveAccrual = "monthly"     // AccrualDays en cada mes cumplido desde la contratación
+	AccrualAnniversary LeaveAccrual = "anniversary" // AccrualDays en cada aniversario de contratación
+)
+
+type LeaveStatus string
+
+var (
+	LeavePending   LeaveStatus = "pending"
+	LeaveApproved  LeaveStatus = "approved"
+	LeaveRejected  LeaveStatus = "rejected"
+	LeaveCancelled LeaveStatus = "cancelled"
+)
+
+// LeaveHalf indica qué mitad del turno cubre un permiso de medio día; vacío es el día completo
+type LeaveHalf string
+
+var (
+	LeaveFullDay   LeaveHalf = ""
+	LeaveMorning   LeaveHalf = "morning"   // Primera mitad del turno
+	LeaveAfternoon LeaveHalf = "afternoon" // Segunda mitad del turno
+)
+
+// LeaveRequest es la solicitud de un empleado para ausentarse entre StartDate y EndDate.
+// Solo al aprobarse deja de esperarse que trabaje esos días (o esa mitad del turno).
+type LeaveRequest struct {
+	ID          uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	AgencyID    uuid.UUID   `gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID   `gorm:"type:uuid;not null;index"`
+	User        User        `gorm:"foreignKey:UserID"`
+	LeaveTypeID uuid.UUID   `gorm:"type:uuid;not null;index"`
+	LeaveType   LeaveType   `gorm:"foreignKey:LeaveTypeID"`
+	StartDate   time.Time   `gorm:"type:date;not null;index"`
+	EndDate     time.Time   `gorm:"type:date;not null;index"` // Inclusive
+	HalfDay     LeaveHalf   `gorm:"not null;default:''"`      // Solo cuando StartDate = EndDate
+	Days        float64     `gorm:"not null;default:0"`       // Días hábiles que cubre según los horarios del usuario
+	Minutes     int         `gorm:"not null;default:0"`       // Minutos programados en esos días
+	Reason      string      `gorm:"not null"`
+	Status      LeaveStatus `gorm:"not null;default:'pending'"`
+	ReviewerID  *uuid.UUID  `gorm:"type:uuid"`
+	ReviewNote  *string
+	ReviewedAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (l *LeaveRequest) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// OnLeaveError se devuelve al resolver el horario de una fecha cubierta por un permiso aprobado de día completo.
+// Como en los feriados, ese día no se espera trabajo.
+type OnLeaveError struct {
+	Leave *LeaveRequest
+}
+
+func (e *OnLeaveError) Error() string {
+	return fmt.Sprintf("user is on leave on this date: %s", e.Leave.LeaveType.Name)
+}
+
+func (e *OnLeaveError) Unwrap() []error {
+	return []error{ErrOnLeave, ErrNonWorkingDay}
+}
+
+type LeaveTypeFilter struct {
+	Active *bool
+}
+
+type LeaveRequestFilter struct {
+	UserID      uuid.UUID
+	LeaveTypeID uuid.UUID
+	Status      LeaveStatus
+	StartDate   *time.Time // Solicitudes que se cruzan con el rango
+	EndDate     *time.Time
+	Page        int
+	Limit       int
+}
+
+type LeaveTypeRepo interface {
+	Create(ctx context.Context, leaveType *LeaveType) error
+	GetByID(ctx context.Context, id uuid.UUID) (*LeaveType, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter LeaveTypeFilter) ([]*LeaveType, error)
+	Update(ctx context.Context, leaveType *LeaveType) error
+}
+
+type LeaveRequestRepo interface {
+	Create(ctx context.Context, leave *LeaveRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*LeaveRequest, error)
+	// GetByIDForUpdate bloquea la fila (SELECT ... FOR UPDATE) hasta que termine la transacción del contexto
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*LeaveRequest, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter LeaveRequestFilter) ([]*LeaveRequest, error)
+	Update(ctx context.Context, leave *LeaveRequest) error
+	// ListActiveOverlapping devuelve las solicitudes pendientes o aprobadas del usuario que se cruzan con [start, end]
+	ListActiveOverlapping(ctx context.Context, userID uuid.UUID, start time.Time, end time.Time) ([]*LeaveRequest, error)
+	// ListApprovedForDate devuelve los permisos aprobados del usuario que cubren date (a lo más dos medios días)
+	ListApprovedForDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) ([]*LeaveRequest, error)
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInsufficientLeaveBalance = errors.New("insufficient leave balance")
+	ErrLeaveBalanceNotTracked   = errors.New("leave type does not track a balance")
+	ErrInvalidLeaveAdjustment   = errors.New("invalid leave balance adjustment")
+)
+
+type LeaveEntryKind string
+
+var (
+	LeaveEntryAccrual      LeaveEntryKind = "accrual"        // Acumulación mensual
+	LeaveEntryGrant        LeaveEntryKind = "grant"          // Asignación anual en el aniversario
+	LeaveEntryDeduction    LeaveEntryKind = "deduction"      // Consumo de un permiso aprobado
+	LeaveEntryAdjustment   LeaveEntryKind = "adjustment"     // Ajuste manual de un administrador
+	LeaveEntryCarryOverCap LeaveEntryKind = "carry_over_cap" // Excedente sobre el tope que no pasa al año nuevo
+	LeaveEntryExpiry       LeaveEntryKind = "expiry"         // Días arrastrados que no se usaron a tiempo
+)
+
+// LeaveBalanceEntry es un movimiento del saldo de un usuario para un tipo de permiso; el saldo es la suma de Days.
+// Los movimientos automáticos (sin LeaveRequestID ni CreatedByID) son únicos por tipo y fecha,
+// así el job puede volver a generarlos sin duplicar.
+type LeaveBalanceEntry struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	AgencyID       uuid.UUID      `gorm:"type:uuid;not null;index"`
+	UserID         uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_leave_entry_auto,where:leave_request_id IS NULL AND created_by_id IS NULL"`
+	LeaveTypeID    uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_leave_entry_auto,where:leave_request_id IS NULL AND created_by_id IS NULL"`
+	Date           time.Time      `gorm:"type:date;not null;uniqueIndex:idx_leave_entry_auto,where:leave_request_id IS NULL AND created_by_id IS NULL"` // Día en que se aplica
+	Kind           LeaveEntryKind `gorm:"not null;uniqueIndex:idx_leave_entry_auto,where:leave_request_id IS NULL AND created_by_id IS NULL"`
+	Days           float64        `gorm:"not null"` // Positivo suma al saldo, negativo lo descuenta
+	LeaveRequestID *uuid.UUID     `gorm:"type:uuid;index"`
+	CreatedByID    *uuid.UUID     `gorm:"type:uuid"` // Administrador que hizo el ajuste
+	Note           *string
+	CreatedAt      time.Time
+}
+
+func (e *LeaveBalanceEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// Automatic indica si el movimiento lo genera la política del tipo de permiso
+func (e *LeaveBalanceEntry) Automatic() bool {
+	return e.LeaveRequestID == nil && e.CreatedByID == nil
+}
+
+type LeaveBalanceFilter struct {
+	UserID      uuid.UUID
+	LeaveTypeID uuid.UUID
+	Kind        LeaveEntryKind
+	Page        int
+	Limit       int
+}
+
+type LeaveBalanceRepo interface {
+	Create(ctx context.Context, entry *LeaveBalanceEntry) error
+	// CreateBatch inserta los movimientos ignorando los automáticos que ya existen; devuelve cuántos se crearon
+	CreateBatch(ctx context.Context, entries []*LeaveBalanceEntry) (int64, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter LeaveBalanceFilter) ([]*LeaveBalanceEntry, error)
+	// ListByUserAndType devuelve todos los movimientos del usuario para el tipo, en orden cronológico
+	ListByUserAndType(ctx context.Context, userID uuid.UUID, leaveTypeID uuid.UUID) ([]*LeaveBalanceEntry, error)
+	// LockByUserAndType bloquea (SELECT ... FOR UPDATE) los movimientos del usuario para el tipo hasta que termine
+	// la transacción del contexto
+	LockByUserAndType(ctx context.Context, userID uuid.UUID, leaveTypeID uuid.UUID) error
+}
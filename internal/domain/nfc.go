@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNFCTagNotFound = errors.New("nfc tag not found")
+	ErrNFCTagRequired = errors.New("nfc tag uid is required")
+	ErrNFCTagInactive = errors.New("nfc tag is inactive")
+	ErrNFCTagExists   = errors.New("nfc tag already registered")
+)
+
+// NFCTag es una etiqueta física instalada en una sede. Las marcas con método nfc
+// deben leer una etiqueta registrada y activa de la agencia.
+type NFCTag struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	AgencyID  uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_nfc_tag_agency_uid"`
+	UID       string     `gorm:"not null;uniqueIndex:idx_nfc_tag_agency_uid"` // Normalizado: hexadecimal en mayúsculas sin separadores
+	Label     string     `gorm:"not null"`
+	SiteID    *uuid.UUID `gorm:"type:uuid;index"` // Sede donde está instalada
+	Site      *Site      `gorm:"foreignKey:SiteID"`
+	Active    bool       `gorm:"not null;default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (t *NFCTag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// NFCTagUsage es cada marca registrada con una etiqueta
+type NFCTagUsage struct {
+	PunchID      uuid.UUID
+	AttendanceID uuid.UUID
+	UserID       uuid.UUID
+	NFCTagID     uuid.UUID
+	SiteID       *uuid.UUID
+	Site         *string
+	Type         AttendanceType
+	Time         time.Time
+}
+
+type NFCTagFilter struct {
+	Active *bool
+	Page   int
+	Limit  int
+}
+
+type NFCTagUsageFilter struct {
+	TagID     uuid.UUID
+	UserID    uuid.UUID
+	StartDate *time.Time
+	EndDate   *time.Time
+	Page      int
+	Limit     int
+}
+
+type NFCTagRepo interface {
+	Create(ctx context.Context, tag *NFCTag) error
+	GetByID(ctx context.Context, id uuid.UUID) (*NFCTag, error)
+	GetByUID(ctx context.Context, agencyID uuid.UUID, uid string) (*NFCTag, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter NFCTagFilter) ([]*NFCTag, error)
+	Update(ctx context.Context, tag *NFCTag) error
+	ListUsage(ctx context.Context, agencyID uuid.UUID, filter NFCTagUsageFilter) ([]*NFCTagUsage, error)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrQRTokenRequired = errors.New("qr token is required")
+	ErrQRTokenInvalid  = errors.New("invalid qr token")
+	ErrQRTokenExpired  = errors.New("qr token expired")
+	ErrQRTokenReplayed = errors.New("qr token already used")
+)
+
+// QRTokenUse registra que un usuario ya marcó con un token QR. El mismo código lo escanean
+// todos los empleados de la sede, por lo que la repetición se controla por usuario.
+type QRTokenUse struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	AgencyID  uuid.UUID `gorm:"type:uuid;not null"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_qr_token_use"`
+	Nonce     string    `gorm:"not null;uniqueIndex:idx_qr_token_use"` // jti del token
+	ExpiresAt time.Time `gorm:"not null;index"`                        // Después de esto el token ya no es válido y el registro puede borrarse
+	CreatedAt time.Time
+}
+
+func (u *QRTokenUse) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
+
+type QRTokenUseRepo interface {
+	Create(ctx context.Context, use *QRTokenUse) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,36 +17,166 @@ var (
 	ErrDefaultScheduleAlreadyExists = errors.New("default schedule already exists")
 	ErrScheduleNameAlreadyExists    = errors.New("schedule name already exists")
 	ErrDeleteDefaultSchedule        = errors.New("cannot delete the default schedule of an agency")
+	ErrScheduleVersionNotCurrent    = errors.New("only the latest version of a schedule can be edited")
+	ErrInvalidEffectiveDate         = errors.New("invalid effective date")
+	ErrInvalidFlexibleSchedule      = errors.New("flexible schedule needs a core time inside the band and required minutes that fit in it")
+	ErrScheduleAssignmentConflict   = errors.New("user already has another schedule on those days")
+	ErrInvalidWeekday               = errors.New("days of week must be between 0 (Sunday) and 6 (Saturday)")
 )
 
+// ScheduleType distingue los horarios de entrada y salida fijas de los de jornada flexible
+type ScheduleType string
+
+var (
+	ScheduleFixed    ScheduleType = "fixed"
+	ScheduleFlexible ScheduleType = "flexible"
+)
+
+// WeekdaySet guarda los días de la semana como máscara de bits: el bit n corresponde al día n (0=Dom, 1=Lun...)
+type WeekdaySet int
+
+// NewWeekdaySet arma el conjunto validando que cada día esté entre 0 y 6
+func NewWeekdaySet(days []int) (WeekdaySet, error) {
+	var set WeekdaySet
+	for _, d := range days {
+		if d < 0 || d > 6 {
+			return 0, ErrInvalidWeekday
+		}
+		set |= 1 << d
+	}
+	return set, nil
+}
+
+func (w WeekdaySet) Has(day time.Weekday) bool {
+	return w&(1<<day) != 0
+}
+
+// Days devuelve los días del conjunto de domingo a sábado
+func (w WeekdaySet) Days() []int {
+	days := []int{}
+	for d := 0; d <= 6; d++ {
+		if w&(1<<d) != 0 {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// Schedule es una versión de un horario. Editar los días, horas o usuarios de un horario crea una versión
+// nueva de la misma serie en lugar de modificar la anterior, para que las fechas pasadas se sigan
+// evaluando con las reglas que tenían.
 type Schedule struct {
 	ID       uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SeriesID uuid.UUID `gorm:"type:uuid;index"` // Igual al ID de la primera versión
+	Version  int       `gorm:"not null;default:1"`
 	AgencyID uuid.UUID `gorm:"type:uuid;not null;index"`
 	Agency   Agency    `gorm:"foreignKey:AgencyID"`
 	Name     string    `gorm:"not null"`
-	// Recomendación: String para evitar líos de drivers con arrays
-	DaysOfWeek         string `gorm:"not null"` // Ej: "1,2,3,4,5,"
-	EntryTimeMinutes   int    `gorm:"not null"`
-	ExitTimeMinutes    int    `gorm:"not null"`
-	GracePeriodMinutes int    `gorm:"not null"`
-	IsDefault          bool   `gorm:"not null"`
-	AssignedUsers      []User `gorm:"many2many:schedule_users;"`
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	// Máscara de bits de los días (0=Dom, 1=Lun...); antes era un texto "1,2,3" en days_of_week
+	DaysOfWeek         WeekdaySet `gorm:"column:weekdays;not null;default:0"` // Ej: lunes a viernes = 0b0111110
+	EntryTimeMinutes   int        `gorm:"not null"`
+	ExitTimeMinutes    int        `gorm:"not null"` // Si es <= EntryTimeMinutes el turno termina al día siguiente
+	GracePeriodMinutes int        `gorm:"not null"`
+	// Minutos antes de la salida en que el checkout aún no se considera anticipado
+	EarlyLeaveToleranceMinutes int    `gorm:"not null;default:0"`
+	IsDefault                  bool   `gorm:"not null"`
+	AssignedUsers              []User `gorm:"many2many:schedule_users;"`
+	// Jornada flexible: EntryTimeMinutes y ExitTimeMinutes delimitan la banda en que se puede trabajar,
+	// el atraso y la salida anticipada se evalúan contra el tiempo núcleo y al salir se exigen RequiredMinutes
+	Type             ScheduleType `gorm:"not null;default:'fixed'"`
+	CoreStartMinutes int          `gorm:"not null;default:0"`
+	CoreEndMinutes   int          `gorm:"not null;default:0"`
+	RequiredMinutes  int          `gorm:"not null;default:0"`
+	// Vigencia inclusiva; nil = sin límite por ese lado
+	EffectiveFrom *time.Time `gorm:"type:date"`
+	EffectiveTo   *time.Time `gorm:"type:date;index"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 func (s *Schedule) BeforeCreate(tx *gorm.DB) error {
 	if s.ID == uuid.Nil {
 		s.ID = uuid.New()
 	}
+	if s.SeriesID == uuid.Nil {
+		s.SeriesID = s.ID
+	}
+	return nil
+}
+
+// CrossesMidnight indica si la salida cae en el día siguiente a la entrada (ej: 22:00 - 06:00)
+func (s *Schedule) CrossesMidnight() bool {
+	return s.ExitTimeMinutes <= s.EntryTimeMinutes
+}
+
+// IsFlexible indica si el horario es de jornada flexible con tiempo núcleo
+func (s *Schedule) IsFlexible() bool {
+	return s.Type == ScheduleFlexible
+}
+
+// Validate verifica que el tiempo núcleo quede dentro de la banda y que los minutos exigidos quepan en ella.
+// La banda puede cruzar medianoche, por eso las horas se comparan como desfases desde su inicio.
+func (s *Schedule) Validate() error {
+	if !s.IsFlexible() {
+		return nil
+	}
+
+	band := BandOffset(s.EntryTimeMinutes, s.ExitTimeMinutes)
+	if band == 0 {
+		band = 24 * 60
+	}
+	coreStart := BandOffset(s.EntryTimeMinutes, s.CoreStartMinutes)
+	coreEnd := BandOffset(s.EntryTimeMinutes, s.CoreEndMinutes)
+
+	if coreEnd <= coreStart || coreEnd > band || s.RequiredMinutes <= 0 || s.RequiredMinutes > band {
+		return ErrInvalidFlexibleSchedule
+	}
 	return nil
 }
 
+// Overlaps devuelve los días de la semana que comparte con other si sus vigencias se cruzan, o nil
+func (s *Schedule) Overlaps(other *Schedule) []int {
+	if s.EffectiveTo != nil && other.EffectiveFrom != nil && s.EffectiveTo.Before(*other.EffectiveFrom) {
+		return nil
+	}
+	if other.EffectiveTo != nil && s.EffectiveFrom != nil && other.EffectiveTo.Before(*s.EffectiveFrom) {
+		return nil
+	}
+
+	shared := s.DaysOfWeek & other.DaysOfWeek
+	if shared == 0 {
+		return nil
+	}
+	return shared.Days()
+}
+
+// ScheduleConflictError se devuelve al asignar a un usuario un horario que se cruza con otro que ya tiene
+type ScheduleConflictError struct {
+	UserID   uuid.UUID
+	Schedule *Schedule // Horario con el que se cruza
+	Weekdays []int
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return fmt.Sprintf("user %s already has schedule %q on those days", e.UserID, e.Schedule.Name)
+}
+
+func (e *ScheduleConflictError) Unwrap() error {
+	return ErrScheduleAssignmentConflict
+}
+
+// BandOffset devuelve cuántos minutos después de bandStart cae minutes, dando la vuelta a medianoche
+func BandOffset(bandStart int, minutes int) int {
+	return ((minutes-bandStart)%(24*60) + 24*60) % (24 * 60)
+}
+
 type ScheduleFilter struct {
 	Name      string
 	IsDefault *bool
-	Page      int
-	Limit     int
+	// Solo versiones que no terminaron antes de esta fecha (vigentes o programadas)
+	ActiveFrom *time.Time
+	Page       int
+	Limit      int
 }
 
 type ScheduleRepo interface {
@@ -53,9 +184,13 @@ type ScheduleRepo interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*Schedule, error)
 	GetByAgencyID(ctx context.Context, agencyID uuid.UUID, filter ScheduleFilter) ([]*Schedule, error)
 	GetByDate(ctx context.Context, agencyID uuid.UUID, date time.Time) ([]*Schedule, error)
-	GetDefault(ctx context.Context, agencyID uuid.UUID) (*Schedule, error)
+	GetDefault(ctx context.Context, agencyID uuid.UUID, date time.Time) (*Schedule, error)
 	GetByName(ctx context.Context, agencyID uuid.UUID, name string) ([]*Schedule, error)
-	GetUserScheduleByDay(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, weekday string) (*Schedule, error)
+	GetUserScheduleByDay(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*Schedule, error)
+	GetVersions(ctx context.Context, seriesID uuid.UUID) ([]*Schedule, error)
+	// GetAssigned devuelve las versiones con usuarios asignados que no terminaron antes de from, con sus
+	// usuarios. Con userIDs solo las asignadas a alguno de ellos.
+	GetAssigned(ctx context.Context, agencyID uuid.UUID, userIDs []uuid.UUID, from time.Time) ([]*Schedule, error)
 	Update(ctx context.Context, schedule *Schedule) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
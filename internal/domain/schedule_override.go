@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrScheduleOverrideNotFound = errors.New("schedule override not found")
+	ErrInvalidScheduleOverride  = errors.New("invalid schedule override")
+)
+
+// ScheduleOverride reemplaza la entrada, salida y tolerancia de atraso en un rango de fechas
+// (ej: "este viernes se cierra a las 14:00"). Sin usuarios aplica a toda la agencia; con usuarios,
+// solo a ellos y por sobre las excepciones de la agencia. Prevalece sobre los horarios semanales y los feriados.
+type ScheduleOverride struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primaryKey"`
+	AgencyID           uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name               string    `gorm:"not null"`
+	StartDate          time.Time `gorm:"type:date;not null;index"`
+	EndDate            time.Time `gorm:"type:date;not null;index"` // Inclusive
+	EntryTimeMinutes   int       `gorm:"not null"`
+	ExitTimeMinutes    int       `gorm:"not null"` // Si es <= EntryTimeMinutes el turno termina al día siguiente
+	GracePeriodMinutes int       `gorm:"not null;default:0"`
+	Users              []User    `gorm:"many2many:schedule_override_users;"`
+	CreatedByID        uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (o *ScheduleOverride) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+func (o *ScheduleOverride) CrossesMidnight() bool {
+	return o.ExitTimeMinutes <= o.EntryTimeMinutes
+}
+
+type ScheduleOverrideFilter struct {
+	UserID    uuid.UUID  // Excepciones de ese usuario y las de toda la agencia
+	StartDate *time.Time // Excepciones que se cruzan con el rango
+	EndDate   *time.Time
+	Page      int
+	Limit     int
+}
+
+type ScheduleOverrideRepo interface {
+	Create(ctx context.Context, override *ScheduleOverride) error
+	GetByID(ctx context.Context, id uuid.UUID) (*ScheduleOverride, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter ScheduleOverrideFilter) ([]*ScheduleOverride, error)
+	// FindForDate devuelve la excepción que aplica al usuario en date con sus usuarios (ninguno si es de toda la
+	// agencia), o nil. Las del usuario ganan a las de la agencia y, entre varias, la de rango más corto.
+	FindForDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*ScheduleOverride, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewWeekdaySet(t *testing.T) {
+	tests := []struct {
+		name    string
+		days    []int
+		want    WeekdaySet
+		wantErr bool
+	}{
+		{"empty", nil, 0, false},
+		{"sunday", []int{0}, 0b0000001, false},
+		{"monday to friday", []int{1, 2, 3, 4, 5}, 0b0111110, false},
+		{"every day", []int{0, 1, 2, 3, 4, 5, 6}, 0b1111111, false},
+		{"repeated days", []int{1, 1, 3}, 0b0001010, false},
+		{"unordered", []int{6, 0}, 0b1000001, false},
+		{"negative day", []int{1, -1}, 0, true},
+		{"day 7", []int{7}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewWeekdaySet(tt.days)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewWeekdaySet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && err != ErrInvalidWeekday {
+				t.Errorf("NewWeekdaySet() error = %v, want ErrInvalidWeekday", err)
+			}
+			if got != tt.want {
+				t.Errorf("NewWeekdaySet() = %07b, want %07b", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekdaySetHasAndDays(t *testing.T) {
+	set, err := NewWeekdaySet([]int{5, 1, 3})
+	if err != nil {
+		t.Fatalf("NewWeekdaySet() error = %v", err)
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		want := day == time.Monday || day == time.Wednesday || day == time.Friday
+		if got := set.Has(day); got != want {
+			t.Errorf("Has(%s) = %v, want %v", day, got, want)
+		}
+	}
+
+	if got := set.Days(); !slices.Equal(got, []int{1, 3, 5}) {
+		t.Errorf("Days() = %v, want [1 3 5]", got)
+	}
+	if got := WeekdaySet(0).Days(); got == nil || len(got) != 0 {
+		t.Errorf("Days() of an empty set = %#v, want an empty non-nil slice", got)
+	}
+}
+
+func TestScheduleOverlaps(t *testing.T) {
+	date := func(month time.Month, day int) *time.Time {
+		d := time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	weekdays := WeekdaySet(0b0111110)
+	weekend := WeekdaySet(0b1000001)
+
+	tests := []struct {
+		name string
+		a, b Schedule
+		want []int
+	}{
+		{"same days", Schedule{DaysOfWeek: weekdays}, Schedule{DaysOfWeek: weekdays}, []int{1, 2, 3, 4, 5}},
+		{"disjoint days", Schedule{DaysOfWeek: weekdays}, Schedule{DaysOfWeek: weekend}, nil},
+		{"shared saturday", Schedule{DaysOfWeek: 0b1000010}, Schedule{DaysOfWeek: weekend}, []int{6}},
+		{
+			"consecutive versions",
+			Schedule{DaysOfWeek: weekdays, EffectiveTo: date(3, 31)},
+			Schedule{DaysOfWeek: weekdays, EffectiveFrom: date(4, 1)},
+			nil,
+		},
+		{
+			"overlapping validity",
+			Schedule{DaysOfWeek: weekdays, EffectiveTo: date(4, 1)},
+			Schedule{DaysOfWeek: 0b0000010, EffectiveFrom: date(4, 1)},
+			[]int{1},
+		},
+		{
+			"later schedule first",
+			Schedule{DaysOfWeek: weekdays, EffectiveFrom: date(5, 1)},
+			Schedule{DaysOfWeek: weekdays, EffectiveTo: date(4, 30)},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Overlaps(&tt.b); !slices.Equal(got, tt.want) {
+				t.Errorf("Overlaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
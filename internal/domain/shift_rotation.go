@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrShiftRotationNotFound      = errors.New("shift rotation not found")
+	ErrInvalidShiftRotation       = errors.New("invalid shift rotation")
+	ErrShiftRotationInUse         = errors.New("shift rotation has assigned users")
+	ErrRotationAssignmentNotFound = errors.New("rotation assignment not found")
+)
+
+// MaxRotationCycleDays limita el largo de un ciclo; las rotaciones reales se repiten cada pocas semanas
+const MaxRotationCycleDays = 366
+
+// ShiftRotation es un patrón de turnos que se repite cada CycleDays días contados desde AnchorDate
+// (ej: 2 días, 2 noches y 4 libres). A los usuarios asignados no se les aplica su horario semanal.
+type ShiftRotation struct {
+	ID         uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	AgencyID   uuid.UUID       `gorm:"type:uuid;not null;index"`
+	Name       string          `gorm:"not null"`
+	AnchorDate time.Time       `gorm:"type:date;not null"` // Día 1 del ciclo para un desfase 0
+	CycleDays  int             `gorm:"not null"`           // Suma de los días de todos los turnos
+	Shifts     []RotationShift `gorm:"foreignKey:RotationID;constraint:OnDelete:CASCADE"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (r *ShiftRotation) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// ShiftAt devuelve el turno que corresponde a date con el desfase offsetDays y la posición en el ciclo
+// (0 = primer día). Shifts debe estar ordenado por Position.
+func (r *ShiftRotation) ShiftAt(date time.Time, offsetDays int) (*RotationShift, int) {
+	if r.CycleDays <= 0 {
+		return nil, 0
+	}
+
+	// Días calendario entre el ancla y la fecha, sin depender de la zona horaria de date
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	anchor := time.Date(r.AnchorDate.Year(), r.AnchorDate.Month(), r.AnchorDate.Day(), 0, 0, 0, 0, time.UTC)
+	elapsed := int(day.Sub(anchor).Hours() / 24)
+
+	cycleDay := ((elapsed+offsetDays)%r.CycleDays + r.CycleDays) % r.CycleDays
+
+	start := 0
+	for i := range r.Shifts {
+		if cycleDay < start+r.Shifts[i].Days {
+			return &r.Shifts[i], cycleDay
+		}
+		start += r.Shifts[i].Days
+	}
+	return nil, cycleDay
+}
+
+// RotationShift es un tramo del ciclo: Days días seguidos con el mismo turno, o libres si Off
+type RotationShift struct {
+	ID                         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RotationID                 uuid.UUID `gorm:"type:uuid;not null;index"`
+	Position                   int       `gorm:"not null"`
+	Name                       string    `gorm:"not null"`
+	Days                       int       `gorm:"not null"`
+	Off                        bool      `gorm:"not null;default:false"`
+	EntryTimeMinutes           int       `gorm:"not null"`
+	ExitTimeMinutes            int       `gorm:"not null"` // Si es <= EntryTimeMinutes el turno termina al día siguiente
+	GracePeriodMinutes         int       `gorm:"not null"`
+	EarlyLeaveToleranceMinutes int       `gorm:"not null;default:0"`
+}
+
+func (s *RotationShift) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *RotationShift) CrossesMidnight() bool {
+	return s.ExitTimeMinutes <= s.EntryTimeMinutes
+}
+
+// RotationAssignment asigna un usuario a una rotación entre StartDate y EndDate (inclusive, nil = sin fin).
+// OffsetDays adelanta al usuario en el ciclo, para repartir a un equipo entre los distintos turnos.
+type RotationAssignment struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	AgencyID   uuid.UUID      `gorm:"type:uuid;not null;index"`
+	RotationID uuid.UUID      `gorm:"type:uuid;not null;index"`
+	Rotation   *ShiftRotation `gorm:"foreignKey:RotationID"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null;index"`
+	OffsetDays int            `gorm:"not null;default:0"`
+	StartDate  time.Time      `gorm:"type:date;not null"`
+	EndDate    *time.Time     `gorm:"type:date"`
+	CreatedAt  time.Time
+}
+
+func (a *RotationAssignment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+type ShiftRotationFilter struct {
+	Page  int
+	Limit int
+}
+
+type ShiftRotationRepo interface {
+	Create(ctx context.Context, rotation *ShiftRotation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*ShiftRotation, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter ShiftRotationFilter) ([]*ShiftRotation, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	CreateAssignment(ctx context.Context, assignment *RotationAssignment) error
+	GetAssignment(ctx context.Context, id uuid.UUID) (*RotationAssignment, error)
+	ListAssignments(ctx context.Context, rotationID uuid.UUID) ([]*RotationAssignment, error)
+	// ListUserAssignments devuelve las asignaciones del usuario que no terminaron antes de from
+	ListUserAssignments(ctx context.Context, userID uuid.UUID, from time.Time) ([]*RotationAssignment, error)
+	CountAssignments(ctx context.Context, rotationID uuid.UUID) (int64, error)
+	UpdateAssignment(ctx context.Context, assignment *RotationAssignment) error
+	DeleteAssignment(ctx context.Context, id uuid.UUID) error
+	// FindAssignment devuelve la asignación vigente del usuario en date con la rotación y sus turnos, o nil
+	FindAssignment(ctx context.Context, userID uuid.UUID, date time.Time) (*RotationAssignment, error)
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShiftRotationShiftAt(t *testing.T) {
+	// 2 days, 2 nights and 4 off: an 8-day cycle starting on 2026-03-02
+	rotation := &ShiftRotation{
+		AnchorDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		CycleDays:  8,
+		Shifts: []RotationShift{
+			{Position: 0, Name: "Day", Days: 2, EntryTimeMinutes: 8 * 60, ExitTimeMinutes: 20 * 60},
+			{Position: 1, Name: "Night", Days: 2, EntryTimeMinutes: 20 * 60, ExitTimeMinutes: 8 * 60},
+			{Position: 2, Name: "Off", Days: 4, Off: true},
+		},
+	}
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	tests := []struct {
+		name         string
+		date         time.Time
+		offset       int
+		wantShift    string
+		wantCycleDay int
+	}{
+		{"anchor date", date(3, 2), 0, "Day", 0},
+		{"second day", date(3, 3), 0, "Day", 1},
+		{"first night", date(3, 4), 0, "Night", 2},
+		{"last day off", date(3, 9), 0, "Off", 7},
+		{"next cycle", date(3, 10), 0, "Day", 0},
+		{"many cycles later", date(3, 2).AddDate(0, 0, 8*40+3), 0, "Night", 3},
+		{"before the anchor", date(3, 1), 0, "Off", 7},
+		{"long before the anchor", date(3, 2).AddDate(0, 0, -8*10-5), 0, "Night", 3},
+		{"offset moves the user ahead", date(3, 2), 2, "Night", 2},
+		{"offset wraps around the cycle", date(3, 9), 3, "Night", 2},
+		{"offset larger than the cycle", date(3, 2), 8 + 4, "Off", 4},
+		{"negative offset", date(3, 2), -1, "Off", 7},
+		{"time of day is ignored", time.Date(2026, 3, 4, 23, 30, 0, 0, time.UTC), 0, "Night", 2},
+		// The calendar day of the local time counts, not the UTC instant
+		{"local date in another zone", time.Date(2026, 3, 3, 23, 0, 0, 0, santiago), 0, "Day", 1},
+		// Counting across the DST change in April must not lose a day
+		{"across a DST change", time.Date(2026, 4, 7, 0, 0, 0, 0, santiago), 0, "Off", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shift, cycleDay := rotation.ShiftAt(tt.date, tt.offset)
+			if shift == nil {
+				t.Fatalf("ShiftAt() returned no shift")
+			}
+			if shift.Name != tt.wantShift || cycleDay != tt.wantCycleDay {
+				t.Errorf("ShiftAt() = %s day %d, want %s day %d", shift.Name, cycleDay, tt.wantShift, tt.wantCycleDay)
+			}
+		})
+	}
+}
+
+func TestShiftRotationShiftAtInvalid(t *testing.T) {
+	empty := &ShiftRotation{AnchorDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}
+	if shift, _ := empty.ShiftAt(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), 0); shift != nil {
+		t.Errorf("ShiftAt() on a rotation without cycle = %v, want nil", shift)
+	}
+
+	// Shifts that do not add up to the cycle leave the rest of it without a shift
+	short := &ShiftRotation{
+		AnchorDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		CycleDays:  4,
+		Shifts:     []RotationShift{{Name: "Day", Days: 2}},
+	}
+	if shift, cycleDay := short.ShiftAt(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), 0); shift != nil || cycleDay != 3 {
+		t.Errorf("ShiftAt() = %v day %d, want nil day 3", shift, cycleDay)
+	}
+}
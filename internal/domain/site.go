@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSiteNotFound       = errors.New("site not found")
+	ErrSiteExists         = errors.New("site name already exists")
+	ErrLocationRequired   = errors.New("location is required for on-site attendance")
+	ErrNoSitesConfigured  = errors.New("agency has no active sites")
+	ErrOutsideAllowedSite = errors.New("location outside allowed sites")
+	ErrInvalidSiteFence   = errors.New("site needs a radius or a polygon of at least 3 points")
+)
+
+// MinPolygonPoints es la cantidad mínima de vértices de una geocerca poligonal
+const MinPolygonPoints = 3
+
+// GeoPoint es un vértice de una geocerca poligonal
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Site es una sede de la agencia con su geocerca: un polígono si tiene vértices,
+// si no un círculo de RadiusMeters alrededor del centro.
+type Site struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	AgencyID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_site_agency_name"`
+	Name         string     `gorm:"not null;uniqueIndex:idx_site_agency_name"`
+	Latitude     float64    `gorm:"not null"`
+	Longitude    float64    `gorm:"not null"`
+	RadiusMeters int        `gorm:"not null;default:0"` // Solo para geocercas circulares
+	Polygon      []GeoPoint `gorm:"serializer:json"`    // Vértices en orden; el polígono se cierra solo
+	Active       bool       `gorm:"not null;default:true"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (s *Site) IsPolygon() bool {
+	return len(s.Polygon) >= MinPolygonPoints
+}
+
+func (s *Site) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// OutsideSiteError se devuelve cuando una marca presencial queda fuera de todas las sedes.
+// Informa la sede más cercana y cuántos metros de la ubicación (con su margen de precisión) quedaron fuera de su geocerca.
+type OutsideSiteError struct {
+	NearestSiteID   uuid.UUID
+	NearestSiteName string
+	DistanceMeters  float64
+}
+
+func (e *OutsideSiteError) Error() string {
+	return fmt.Sprintf("location is %.0f m outside the nearest site %q", e.DistanceMeters, e.NearestSiteName)
+}
+
+func (e *OutsideSiteError) Unwrap() error {
+	return ErrOutsideAllowedSite
+}
+
+type SiteFilter struct {
+	Active *bool
+	Page   int
+	Limit  int
+}
+
+type SiteRepo interface {
+	Create(ctx context.Context, site *Site) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Site, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter SiteFilter) ([]*Site, error)
+	Update(ctx context.Context, site *Site) error
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTimeBankDisabled          = errors.New("time bank is not enabled for this agency")
+	ErrInsufficientTimeBank      = errors.New("insufficient time bank balance")
+	ErrInvalidTimeBankAdjustment = errors.New("invalid time bank adjustment")
+)
+
+type TimeBankEntryKind string
+
+var (
+	TimeBankOvertime   TimeBankEntryKind = "overtime"    // Minutos trabajados después de la salida programada
+	TimeBankEarlyLeave TimeBankEntryKind = "early_leave" // Minutos que faltaron por salir antes
+	TimeBankCompTime   TimeBankEntryKind = "comp_time"   // Tiempo libre compensatorio de un permiso aprobado
+	TimeBankAdjustment TimeBankEntryKind = "adjustment"  // Ajuste manual de un administrador
+	TimeBankExpiry     TimeBankEntryKind = "expiry"      // Minutos acreditados que no se usaron antes de vencer
+)
+
+// TimeBankEntry es un movimiento de la bolsa de horas de un usuario; el saldo es la suma de Minutes.
+// Los movimientos de una asistencia se registran como diferencias: si el registro se corrige o se anula
+// se agrega un movimiento con el cambio, así la suma por asistencia siempre refleja su valor actual.
+type TimeBankEntry struct {
+	ID             uuid.UUID         `gorm:"type:uuid;primaryKey"`
+	AgencyID       uuid.UUID         `gorm:"type:uuid;not null;index"`
+	UserID         uuid.UUID         `gorm:"type:uuid;not null;index"`
+	Date           time.Time         `gorm:"type:date;not null;index"` // Día del turno, del permiso o del vencimiento
+	Kind           TimeBankEntryKind `gorm:"not null"`
+	Minutes        int               `gorm:"not null"` // Positivo acredita, negativo descuenta
+	AttendanceID   *uuid.UUID        `gorm:"type:uuid;index"`
+	LeaveRequestID *uuid.UUID        `gorm:"type:uuid;index"`
+	ExpiredEntryID *uuid.UUID        `gorm:"type:uuid;uniqueIndex"` // Crédito que venció (solo en expiry)
+	CreatedByID    *uuid.UUID        `gorm:"type:uuid"`             // Administrador que hizo el ajuste
+	Note           *string
+	CreatedAt      time.Time
+}
+
+func (e *TimeBankEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+type TimeBankFilter struct {
+	UserID    uuid.UUID
+	Kind      TimeBankEntryKind
+	StartDate *time.Time
+	EndDate   *time.Time
+	Page      int
+	Limit     int
+}
+
+type TimeBankRepo interface {
+	Create(ctx context.Context, entry *TimeBankEntry) error
+	// CreateBatch inserta los movimientos ignorando los vencimientos que ya existen; devuelve cuántos se crearon
+	CreateBatch(ctx context.Context, entries []*TimeBankEntry) (int64, error)
+	List(ctx context.Context, agencyID uuid.UUID, filter TimeBankFilter) ([]*TimeBankEntry, error)
+	// ListByUser devuelve todos los movimientos del usuario en orden cronológico
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*TimeBankEntry, error)
+	ListByAttendanceID(ctx context.Context, attendanceID uuid.UUID) ([]*TimeBankEntry, error)
+	// ListUserIDs devuelve los usuarios de la agencia que tienen movimientos
+	ListUserIDs(ctx context.Context, agencyID uuid.UUID) ([]uuid.UUID, error)
+	// LockByUser bloquea (SELECT ... FOR UPDATE) los movimientos del usuario hasta que termine la transacción del contexto
+	LockByUser(ctx context.Context, userID uuid.UUID) error
+}
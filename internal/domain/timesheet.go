@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTimesheetPeriod   = errors.New("invalid timesheet period")
+	ErrTimesheetPeriodNotEnded  = errors.New("timesheet period has not ended")
+	ErrTimesheetLocked          = errors.New("timesheet period is locked")
+	ErrTimesheetNotLocked       = errors.New("timesheet is not locked")
+	ErrTimesheetNotAcknowledged = errors.New("timesheet has not been acknowledged")
+	ErrTimesheetChanged         = errors.New("timesheet changed after it was acknowledged")
+)
+
+type TimesheetStatus string
+
+var (
+	TimesheetOpen         TimesheetStatus = "open"         // Sin acuse: se calcula con los datos actuales
+	TimesheetAcknowledged TimesheetStatus = "acknowledged" // El empleado aceptó el contenido guardado
+	TimesheetLocked       TimesheetStatus = "locked"       // Cerrada por un admin: la asistencia del período no se puede editar
+)
+
+// Timesheet es la planilla mensual de un usuario. Solo se guarda desde que el empleado la acepta:
+// Days y Totals son lo que aceptó y ContentHash su huella, así se detecta si los datos cambiaron
+// antes de que un admin la cierre.
+type Timesheet struct {
+	ID             uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	AgencyID       uuid.UUID       `gorm:"type:uuid;not null;index"`
+	UserID         uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_timesheet_user_period"`
+	PeriodStart    time.Time       `gorm:"type:date;not null;uniqueIndex:idx_timesheet_user_period"`
+	PeriodEnd      time.Time       `gorm:"type:date;not null"` // Inclusive
+	Status         TimesheetStatus `gorm:"not null"`
+	Days           []TimesheetDay  `gorm:"serializer:json"`
+	Totals         TimesheetTotals `gorm:"serializer:json"`
+	ContentHash    string          `gorm:"not null"` // SHA-256 de Days y Totals
+	AcknowledgedAt *time.Time
+	AcknowledgedIP *string
+	LockedAt       *time.Time
+	LockedByID     *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TimesheetDay resume un día del período
+type TimesheetDay struct {
+	Date              string            `json:"date"` // Format: YYYY-MM-DD
+	ScheduledMinutes  int               `json:"scheduled_minutes"`
+	AttendanceID      *uuid.UUID        `json:"attendance_id"`
+	CheckInTime       *time.Time        `json:"check_in_time"`
+	CheckOutTime      *time.Time        `json:"check_out_time"`
+	EntryStatus       AttendanceStatus  `json:"entry_status,omitempty"`
+	ExitStatus        *AttendanceStatus `json:"exit_status,omitempty"`
+	WorkedMinutes     int               `json:"worked_minutes"`
+	OvertimeMinutes   int               `json:"overtime_minutes"`
+	DeficitMinutes    int               `json:"deficit_minutes"`
+	LateMinutes       int               `json:"late_minutes"`
+	EarlyLeaveMinutes int               `json:"early_leave_minutes"`
+	Absent            bool              `json:"absent"`
+	Leave             string            `json:"leave,omitempty"`   // Tipo del permiso aprobado que cubre el día
+	LeaveDays         float64           `json:"leave_days"`        // 1 o 0.5 (medio día)
+	Holiday           string            `json:"holiday,omitempty"` // Feriado o cierre del calendario
+}
+
+// TimesheetTotals suma los días del período
+type TimesheetTotals struct {
+	ScheduledDays     int     `json:"scheduled_days"`
+	WorkedDays        int     `json:"worked_days"`
+	ScheduledMinutes  int     `json:"scheduled_minutes"`
+	WorkedMinutes     int     `json:"worked_minutes"`
+	OvertimeMinutes   int     `json:"overtime_minutes"`
+	DeficitMinutes    int     `json:"deficit_minutes"`
+	LateDays          int     `json:"late_days"`
+	LateMinutes       int     `json:"late_minutes"`
+	EarlyLeaveDays    int     `json:"early_leave_days"`
+	EarlyLeaveMinutes int     `json:"early_leave_minutes"`
+	AbsenceDays       int     `json:"absence_days"`
+	LeaveDays         float64 `json:"leave_days"`
+	HolidayDays       int     `json:"holiday_days"`
+}
+
+func (t *Timesheet) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+type TimesheetRepo interface {
+	Create(ctx context.Context, timesheet *Timesheet) error
+	Update(ctx context.Context, timesheet *Timesheet) error
+	// GetByUserAndPeriod devuelve la planilla guardada del usuario para el mes que empieza en periodStart, o nil
+	GetByUserAndPeriod(ctx context.Context, userID uuid.UUID, periodStart time.Time) (*Timesheet, error)
+	// GetByUserAndPeriodForUpdate bloquea la fila (SELECT ... FOR UPDATE) hasta que termine la transacción del contexto
+	GetByUserAndPeriodForUpdate(ctx context.Context, userID uuid.UUID, periodStart time.Time) (*Timesheet, error)
+	// GetForDateForUpdate bloquea y devuelve la planilla guardada del usuario que contiene date, de cualquier estado, o nil
+	GetForDateForUpdate(ctx context.Context, userID uuid.UUID, date time.Time) (*Timesheet, error)
+	ListByPeriod(ctx context.Context, agencyID uuid.UUID, periodStart time.Time, userIDs []uuid.UUID) ([]*Timesheet, error)
+	// IsLocked indica si date cae dentro de una planilla cerrada del usuario
+	IsLocked(ctx context.Context, userID uuid.UUID, date time.Time) (bool, error)
+}
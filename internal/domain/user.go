@@ -33,7 +33,8 @@ type User struct {
 	HomeLatitude     *float64
 	HomeLongitude    *float64
 	HomeRadiusMeters *int
-	ActivationCode   *string `gorm:"uniqueIndex"`
+	HireDate         *time.Time `gorm:"type:date"` // Base de la acumulación de permisos; sin ella se usa CreatedAt
+	ActivationCode   *string    `gorm:"uniqueIndex"`
 	CodeExpiry       *time.Time
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
@@ -61,8 +62,18 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// ServiceStart devuelve el día en que comenzó a trabajar: la fecha de contratación o, si no se registró, la de creación
+func (u *User) ServiceStart() time.Time {
+	start := u.CreatedAt
+	if u.HireDate != nil {
+		start = *u.HireDate
+	}
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 type UserFilter struct {
 	Status string
+	Role   Role
 	Search string
 	Page   int
 	Limit  int
@@ -71,6 +82,8 @@ type UserFilter struct {
 type UserRepo interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
+	// GetByIDForUpdate bloquea la fila (SELECT ... FOR UPDATE) hasta que termine la transacción del contexto
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByActivationCode(ctx context.Context, code string) (*User, error)
 	Update(ctx context.Context, user *User) error
@@ -12,14 +12,27 @@ type RegisterAgencyRequest struct {
 	Domain     string `json:"domain" binding:"required"`
 	Address    string `json:"address"`
 	Phone      string `json:"phone"`
+	TimeZone   string `json:"time_zone"` // IANA name, defaults to UTC
 	AdminEmail string `json:"admin_email" binding:"required,email"`
 	Password   string `json:"password" binding:"required,min=8"`
 }
 
 type UpdateAgencyRequest struct {
-	Name    *string `json:"name"`
-	Address *string `json:"address"`
-	Phone   *string `json:"phone"`
+	Name     *string `json:"name"`
+	Address  *string `json:"address"`
+	Phone    *string `json:"phone"`
+	TimeZone *string `json:"time_zone"`
+
+	WorkRoundingMinutes *int                 `json:"work_rounding_minutes" binding:"omitempty,min=0,max=60"`
+	WorkRoundingMode    *domain.RoundingMode `json:"work_rounding_mode" binding:"omitempty,oneof=nearest floor ceil"`
+	MinOvertimeMinutes  *int                 `json:"min_overtime_minutes" binding:"omitempty,min=0"`
+
+	RequireOnSiteLocation     *bool                      `json:"require_on_site_location"`
+	MaxLocationAccuracyMeters *int                       `json:"max_location_accuracy_meters" binding:"omitempty,min=0"` // 0 = accuracy not required
+	GeofenceStrictness        *domain.GeofenceStrictness `json:"geofence_strictness" binding:"omitempty,oneof=overlap contain"`
+
+	TimeBankEnabled    *bool `json:"time_bank_enabled"`                               // Bank overtime and early leaves as comp time
+	TimeBankExpiryDays *int  `json:"time_bank_expiry_days" binding:"omitempty,min=0"` // Unused credits expire after this many days. 0 = never
 }
 
 type AgencyResponse struct {
@@ -28,9 +41,21 @@ type AgencyResponse struct {
 	Domain    string    `json:"domain"`
 	Address   string    `json:"address"`
 	Phone     string    `json:"phone"`
+	TimeZone  string    `json:"time_zone"`
 	IsActive  bool      `json:"is_active"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	WorkRoundingMinutes int                 `json:"work_rounding_minutes"`
+	WorkRoundingMode    domain.RoundingMode `json:"work_rounding_mode"`
+	MinOvertimeMinutes  int                 `json:"min_overtime_minutes"`
+
+	RequireOnSiteLocation     bool                      `json:"require_on_site_location"`
+	MaxLocationAccuracyMeters int                       `json:"max_location_accuracy_meters"`
+	GeofenceStrictness        domain.GeofenceStrictness `json:"geofence_strictness"`
+
+	TimeBankEnabled    bool `json:"time_bank_enabled"`
+	TimeBankExpiryDays int  `json:"time_bank_expiry_days"`
 }
 
 func ToAgencyResponse(agency *domain.Agency) *AgencyResponse {
@@ -44,8 +69,20 @@ func ToAgencyResponse(agency *domain.Agency) *AgencyResponse {
 		Domain:    agency.Domain,
 		Address:   agency.Address,
 		Phone:     agency.Phone,
+		TimeZone:  agency.TimeZone,
 		IsActive:  agency.IsActive,
 		CreatedAt: agency.CreatedAt,
 		UpdatedAt: agency.UpdatedAt,
+
+		WorkRoundingMinutes: agency.WorkRoundingMinutes,
+		WorkRoundingMode:    agency.WorkRoundingMode,
+		MinOvertimeMinutes:  agency.MinOvertimeMinutes,
+
+		RequireOnSiteLocation:     agency.RequireOnSiteLocation,
+		MaxLocationAccuracyMeters: agency.MaxLocationAccuracyMeters,
+		GeofenceStrictness:        agency.GeofenceStrictness,
+
+		TimeBankEnabled:    agency.TimeBankEnabled,
+		TimeBankExpiryDays: agency.TimeBankExpiryDays,
 	}
 }
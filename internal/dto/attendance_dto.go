@@ -11,29 +11,98 @@ type MarkAttendanceRequest struct {
 	UserID        uuid.UUID               `json:"user_id"`
 	AgencyID      uuid.UUID               `json:"agency_id"`
 	Method        domain.AttendanceMethod `json:"method"`
-	Type          domain.AttendanceType   `json:"type"`
+	Type          domain.AttendanceType   `json:"type" binding:"required,oneof=in out break_start break_end"`
 	Notes         *string                 `json:"notes"`
 	IsRemote      *bool                   `json:"is_remote"`
 	Latitude      *float64                `json:"latitude"`
 	Longitude     *float64                `json:"longitude"`
+	Accuracy      *float64                `json:"accuracy" binding:"omitempty,min=0"` // Horizontal accuracy radius in meters
+	QRToken       *string                 `json:"qr_token"`                           // Required when method is "qr"
+	NFCTagUID     *string                 `json:"nfc_tag_uid"`                        // Required when method is "nfc"
 	RequesterRole domain.Role             `json:"-"`
 }
 
+type QRTokenResponse struct {
+	Token           string    `json:"token"`
+	RefreshAt       time.Time `json:"refresh_at"` // Time to fetch the next code
+	ExpiresAt       time.Time `json:"expires_at"`
+	RotationSeconds int       `json:"rotation_seconds"`
+}
+
+// AdminCreateAttendanceRequest registra la asistencia de un día pasado con horas explícitas
+type AdminCreateAttendanceRequest struct {
+	UserID       uuid.UUID  `json:"user_id" binding:"required"`
+	Date         string     `json:"date" binding:"required"` // Format: YYYY-MM-DD (shift start day)
+	CheckInTime  time.Time  `json:"check_in_time" binding:"required"`
+	CheckOutTime *time.Time `json:"check_out_time"`
+	Notes        *string    `json:"notes"`
+	Reason       string     `json:"reason" binding:"required"`
+}
+
+type AdminUpdateAttendanceRequest struct {
+	CheckInTime  *time.Time `json:"check_in_time"`
+	CheckOutTime *time.Time `json:"check_out_time"`
+	Notes        *string    `json:"notes"`
+	Reason       string     `json:"reason" binding:"required"`
+}
+
+type VoidAttendanceRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type ResolveFlagRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
 type AttendanceResponse struct {
 	ID                uuid.UUID                `json:"id"`
 	UserID            uuid.UUID                `json:"user_id"`
 	AgencyID          uuid.UUID                `json:"agency_id"`
-	CheckInTime       time.Time                `json:"check_in_time"`
+	CheckInTime       *time.Time               `json:"check_in_time"`
 	ScheduleEntryTime time.Time                `json:"schedule_entry_time"`
-	Status            domain.AttendanceStatus  `json:"status"`
+	EntryStatus       domain.AttendanceStatus  `json:"entry_status"`
 	CheckOutTime      *time.Time               `json:"check_out_time"`
+	ExitStatus        *domain.AttendanceStatus `json:"exit_status"`
 	ScheduleExitTime  time.Time                `json:"schedule_exit_time"`
 	Date              time.Time                `json:"date"`
 	MethodIn          domain.AttendanceMethod  `json:"method_in"`
 	MethodOut         *domain.AttendanceMethod `json:"method_out"`
 	Notes             *string                  `json:"notes"`
+	WorkedMinutes     int                      `json:"worked_minutes"`
+	ScheduledMinutes  int                      `json:"scheduled_minutes"`
+	OvertimeMinutes   int                      `json:"overtime_minutes"`
+	DeficitMinutes    int                      `json:"deficit_minutes"`
+	Punches           []PunchResponse          `json:"punches"`
+	NFCTagID          *uuid.UUID               `json:"nfc_tag_id"`
+	Site              *string                  `json:"site"`
+	SiteID            *uuid.UUID               `json:"site_id"`
+	VoidedAt          *time.Time               `json:"voided_at,omitempty"`
+	VoidReason        *string                  `json:"void_reason,omitempty"`
+	FraudScore        int                      `json:"fraud_score"`
+	FraudSignals      []domain.FraudSignal     `json:"fraud_signals"`
+	FlaggedForReview  bool                     `json:"flagged_for_review"`
 	Latitude          *float64                 `json:"latitude"`
 	Longitude         *float64                 `json:"longitude"`
+	Accuracy          *float64                 `json:"accuracy"`
+
+	// Flexible schedules: schedule entry/exit are the core time and only work inside the band counts
+	FlexBandStart   *time.Time `json:"flex_band_start,omitempty"`
+	FlexBandEnd     *time.Time `json:"flex_band_end,omitempty"`
+	RequiredMinutes int        `json:"required_minutes,omitempty"`
+}
+
+type PunchResponse struct {
+	ID           uuid.UUID               `json:"id"`
+	Type         domain.AttendanceType   `json:"type"`
+	Time         time.Time               `json:"time"`
+	Method       domain.AttendanceMethod `json:"method"`
+	Latitude     *float64                `json:"latitude"`
+	Longitude    *float64                `json:"longitude"`
+	Accuracy     *float64                `json:"accuracy"`
+	NFCTagID     *uuid.UUID              `json:"nfc_tag_id"`
+	Site         *string                 `json:"site"`
+	SiteID       *uuid.UUID              `json:"site_id"`
+	FraudSignals []domain.FraudSignal    `json:"fraud_signals"`
 }
 
 func ToAttendanceResponse(attendance *domain.Attendance) *AttendanceResponse {
@@ -41,28 +110,72 @@ func ToAttendanceResponse(attendance *domain.Attendance) *AttendanceResponse {
 		return nil
 	}
 
+	punches := []PunchResponse{}
+	for _, p := range attendance.Punches {
+		punches = append(punches, PunchResponse{
+			ID:           p.ID,
+			Type:         p.Type,
+			Time:         p.Time,
+			Method:       p.Method,
+			Latitude:     p.Latitude,
+			Longitude:    p.Longitude,
+			Accuracy:     p.Accuracy,
+			NFCTagID:     p.NFCTagID,
+			Site:         p.Site,
+			SiteID:       p.SiteID,
+			FraudSignals: p.FraudSignals,
+		})
+	}
+
 	return &AttendanceResponse{
 		ID:                attendance.ID,
 		UserID:            attendance.UserID,
 		AgencyID:          attendance.AgencyID,
 		CheckInTime:       attendance.CheckInTime,
 		ScheduleEntryTime: attendance.ScheduleEntryTime,
-		Status:            attendance.Status,
+		EntryStatus:       attendance.EntryStatus,
 		CheckOutTime:      attendance.CheckOutTime,
+		ExitStatus:        attendance.ExitStatus,
 		ScheduleExitTime:  attendance.ScheduleExitTime,
 		Date:              attendance.Date,
 		MethodIn:          attendance.MethodIn,
 		MethodOut:         attendance.MethodOut,
 		Notes:             attendance.Notes,
+		WorkedMinutes:     attendance.WorkedMinutes,
+		ScheduledMinutes:  attendance.ScheduledMinutes,
+		OvertimeMinutes:   attendance.OvertimeMinutes,
+		DeficitMinutes:    attendance.DeficitMinutes,
+		Punches:           punches,
+		NFCTagID:          attendance.NFCTagID,
+		Site:              attendance.Site,
+		SiteID:            attendance.SiteID,
+		VoidedAt:          attendance.VoidedAt,
+		VoidReason:        attendance.VoidReason,
+		FraudScore:        attendance.FraudScore,
+		FraudSignals:      attendance.FraudSignals,
+		FlaggedForReview:  attendance.FlaggedForReview,
 		Latitude:          attendance.Latitude,
 		Longitude:         attendance.Longitude,
+		Accuracy:          attendance.Accuracy,
+		FlexBandStart:     attendance.FlexBandStart,
+		FlexBandEnd:       attendance.FlexBandEnd,
+		RequiredMinutes:   attendance.RequiredMinutes,
 	}
 }
 
 type AttendanceListParams struct {
 	PaginationParams
-	UserID    string `form:"user_id" binding:"omitempty"`
-	StartDate string `form:"start_date" binding:"omitempty"` // Format: YYYY-MM-DD
-	EndDate   string `form:"end_date" binding:"omitempty"`   // Format: YYYY-MM-DD
-	Status    string `form:"status" binding:"omitempty"`
+	UserID      string `form:"user_id" binding:"omitempty"`
+	StartDate   string `form:"start_date" binding:"omitempty"` // Format: YYYY-MM-DD
+	EndDate     string `form:"end_date" binding:"omitempty"`   // Format: YYYY-MM-DD
+	Status      string `form:"status" binding:"omitempty"`     // Entry or exit status
+	EntryStatus string `form:"entry_status" binding:"omitempty,oneof=present late absent"`
+	ExitStatus  string `form:"exit_status" binding:"omitempty,oneof=on_time early short_hours absent"`
+}
+
+// AttendanceExportParams usa los filtros del listado; page y limit se ignoran porque se exporta todo
+type AttendanceExportParams struct {
+	AttendanceListParams
+	Format string `form:"format,default=csv" binding:"omitempty,oneof=csv xlsx"`
+	Lang   string `form:"lang" binding:"omitempty,oneof=es en"` // Idioma de los encabezados; si falta se usa Accept-Language
 }
@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateCorrectionRequest struct {
+	AttendanceID  *uuid.UUID             `json:"attendance_id"`
+	Date          string                 `json:"date"` // Format: YYYY-MM-DD. Required when attendance_id is empty
+	Type          domain.CorrectionType  `json:"type" binding:"required,oneof=missing_punch wrong_time"`
+	PunchType     *domain.AttendanceType `json:"punch_type" binding:"omitempty,oneof=in out break_start break_end"`
+	PunchID       *uuid.UUID             `json:"punch_id"`
+	RequestedTime time.Time              `json:"requested_time" binding:"required"`
+	Reason        string                 `json:"reason" binding:"required"`
+}
+
+type ReviewCorrectionRequest struct {
+	Note *string `json:"note"`
+}
+
+type CorrectionResponse struct {
+	ID            uuid.UUID               `json:"id"`
+	AgencyID      uuid.UUID               `json:"agency_id"`
+	UserID        uuid.UUID               `json:"user_id"`
+	AttendanceID  *uuid.UUID              `json:"attendance_id"`
+	Date          time.Time               `json:"date"`
+	Type          domain.CorrectionType   `json:"type"`
+	PunchType     *domain.AttendanceType  `json:"punch_type"`
+	PunchID       *uuid.UUID              `json:"punch_id"`
+	RequestedTime time.Time               `json:"requested_time"`
+	Reason        string                  `json:"reason"`
+	Status        domain.CorrectionStatus `json:"status"`
+	ReviewerID    *uuid.UUID              `json:"reviewer_id"`
+	ReviewNote    *string                 `json:"review_note"`
+	ReviewedAt    *time.Time              `json:"reviewed_at"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
+
+func ToCorrectionResponse(correction *domain.AttendanceCorrection) *CorrectionResponse {
+	if correction == nil {
+		return nil
+	}
+
+	return &CorrectionResponse{
+		ID:            correction.ID,
+		AgencyID:      correction.AgencyID,
+		UserID:        correction.UserID,
+		AttendanceID:  correction.AttendanceID,
+		Date:          correction.Date,
+		Type:          correction.Type,
+		PunchType:     correction.PunchType,
+		PunchID:       correction.PunchID,
+		RequestedTime: correction.RequestedTime,
+		Reason:        correction.Reason,
+		Status:        correction.Status,
+		ReviewerID:    correction.ReviewerID,
+		ReviewNote:    correction.ReviewNote,
+		ReviewedAt:    correction.ReviewedAt,
+		CreatedAt:     correction.CreatedAt,
+	}
+}
+
+type CorrectionListParams struct {
+	PaginationParams
+	UserID string `form:"user_id" binding:"omitempty"`
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+}
+
+type AttendanceRevisionResponse struct {
+	ID                   uuid.UUID                `json:"id"`
+	AttendanceID         uuid.UUID                `json:"attendance_id"`
+	ChangedByID          uuid.UUID                `json:"changed_by_id"`
+	Action               domain.RevisionAction    `json:"action"`
+	Reason               string                   `json:"reason"`
+	CorrectionID         *uuid.UUID               `json:"correction_id"`
+	LeaveRequestID       *uuid.UUID               `json:"leave_request_id"`
+	PreviousCheckInTime  *time.Time               `json:"previous_check_in_time"`
+	PreviousCheckOutTime *time.Time               `json:"previous_check_out_time"`
+	PreviousEntryStatus  domain.AttendanceStatus  `json:"previous_entry_status"`
+	PreviousExitStatus   *domain.AttendanceStatus `json:"previous_exit_status"`
+	PreviousNotes        *string                  `json:"previous_notes"`
+	NewCheckInTime       *time.Time               `json:"new_check_in_time"`
+	NewCheckOutTime      *time.Time               `json:"new_check_out_time"`
+	NewEntryStatus       domain.AttendanceStatus  `json:"new_entry_status"`
+	NewExitStatus        *domain.AttendanceStatus `json:"new_exit_status"`
+	NewNotes             *string                  `json:"new_notes"`
+	CreatedAt            time.Time                `json:"created_at"`
+}
+
+func ToAttendanceRevisionResponse(revision *domain.AttendanceRevision) *AttendanceRevisionResponse {
+	if revision == nil {
+		return nil
+	}
+
+	return &AttendanceRevisionResponse{
+		ID:                   revision.ID,
+		AttendanceID:         revision.AttendanceID,
+		ChangedByID:          revision.ChangedByID,
+		Action:               revision.Action,
+		Reason:               revision.Reason,
+		CorrectionID:         revision.CorrectionID,
+		LeaveRequestID:       revision.LeaveRequestID,
+		PreviousCheckInTime:  revision.PreviousCheckInTime,
+		PreviousCheckOutTime: revision.PreviousCheckOutTime,
+		PreviousEntryStatus:  revision.PreviousEntryStatus,
+		PreviousExitStatus:   revision.PreviousExitStatus,
+		PreviousNotes:        revision.PreviousNotes,
+		NewCheckInTime:       revision.NewCheckInTime,
+		NewCheckOutTime:      revision.NewCheckOutTime,
+		NewEntryStatus:       revision.NewEntryStatus,
+		NewExitStatus:        revision.NewExitStatus,
+		NewNotes:             revision.NewNotes,
+		CreatedAt:            revision.CreatedAt,
+	}
+}
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateHolidayRequest struct {
+	Date      string             `json:"date" binding:"required"` // Format: YYYY-MM-DD
+	Name      string             `json:"name" binding:"required"`
+	Type      domain.HolidayType `json:"type" binding:"omitempty,oneof=holiday closure"` // Defaults to holiday
+	Recurring bool               `json:"recurring"`                                      // Repeats every year on the same day and month
+}
+
+type HolidayResponse struct {
+	ID        uuid.UUID          `json:"id"`
+	Date      string             `json:"date"`
+	Name      string             `json:"name"`
+	Type      domain.HolidayType `json:"type"`
+	Recurring bool               `json:"recurring"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+func ToHolidayResponse(holiday *domain.Holiday) *HolidayResponse {
+	if holiday == nil {
+		return nil
+	}
+
+	return &HolidayResponse{
+		ID:        holiday.ID,
+		Date:      holiday.Date.Format("2006-01-02"),
+		Name:      holiday.Name,
+		Type:      holiday.Type,
+		Recurring: holiday.Recurring,
+		CreatedAt: holiday.CreatedAt,
+	}
+}
+
+type HolidayListParams struct {
+	PaginationParams
+	Year int `form:"year" binding:"omitempty,min=1900,max=9999"`
+}
+
+type HolidayImportResponse struct {
+	Imported int                `json:"imported"`
+	Skipped  int                `json:"skipped"` // Dates already in the calendar
+	Holidays []*HolidayResponse `json:"holidays"`
+}
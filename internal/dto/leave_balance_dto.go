@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LeaveBalanceParams struct {
+	UserID string `form:"user_id" binding:"omitempty"` // Admins only. Defaults to the caller
+	Date   string `form:"date" binding:"omitempty"`    // Format: YYYY-MM-DD. Projection date, defaults to today
+}
+
+type LeaveBalanceResponse struct {
+	LeaveType        *LeaveTypeResponse           `json:"leave_type"`
+	Balance          float64                      `json:"balance"`           // As of today
+	ProjectedBalance float64                      `json:"projected_balance"` // As of date, with accruals, grants, caps and expiries up to then
+	PendingDays      float64                      `json:"pending_days"`      // Requested in pending requests, not yet deducted
+	Date             string                       `json:"date"`
+	Projection       []*LeaveBalanceEntryResponse `json:"projection"` // Movements after today up to date; planned ones have no id
+}
+
+type LeaveBalanceLedgerParams struct {
+	PaginationParams
+	UserID      string `form:"user_id" binding:"omitempty"` // Admins only. Defaults to the caller
+	LeaveTypeID string `form:"leave_type_id" binding:"omitempty"`
+	Kind        string `form:"kind" binding:"omitempty,oneof=accrual grant deduction adjustment carry_over_cap expiry"`
+}
+
+type CreateLeaveAdjustmentRequest struct {
+	UserID      uuid.UUID `json:"user_id" binding:"required"`
+	LeaveTypeID uuid.UUID `json:"leave_type_id" binding:"required"`
+	Days        float64   `json:"days" binding:"required"` // Positive adds, negative subtracts
+	Date        string    `json:"date"`                    // Format: YYYY-MM-DD. Defaults to today
+	Note        string    `json:"note" binding:"required"`
+}
+
+type LeaveBalanceEntryResponse struct {
+	ID             *uuid.UUID            `json:"id"`
+	UserID         uuid.UUID             `json:"user_id"`
+	LeaveTypeID    uuid.UUID             `json:"leave_type_id"`
+	Date           string                `json:"date"`
+	Kind           domain.LeaveEntryKind `json:"kind"`
+	Days           float64               `json:"days"`
+	LeaveRequestID *uuid.UUID            `json:"leave_request_id"`
+	CreatedByID    *uuid.UUID            `json:"created_by_id"`
+	Note           *string               `json:"note"`
+	CreatedAt      *time.Time            `json:"created_at"`
+}
+
+func ToLeaveBalanceEntryResponse(entry *domain.LeaveBalanceEntry) *LeaveBalanceEntryResponse {
+	if entry == nil {
+		return nil
+	}
+
+	response := &LeaveBalanceEntryResponse{
+		UserID:         entry.UserID,
+		LeaveTypeID:    entry.LeaveTypeID,
+		Date:           entry.Date.Format("2006-01-02"),
+		Kind:           entry.Kind,
+		Days:           entry.Days,
+		LeaveRequestID: entry.LeaveRequestID,
+		CreatedByID:    entry.CreatedByID,
+		Note:           entry.Note,
+	}
+	// Los movimientos proyectados todavía no se guardaron
+	if entry.ID != uuid.Nil {
+		response.ID = &entry.ID
+		response.CreatedAt = &entry.CreatedAt
+	}
+	return response
+}
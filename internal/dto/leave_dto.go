@@ -0,0 +1,174 @@
+package dto
+
+import (
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateLeaveTypeRequest struct {
+	Name                  string              `json:"name" binding:"required"`
+	Paid                  *bool               `json:"paid"`          // Defaults to true
+	TrackBalance          bool                `json:"track_balance"` // Requests consume a per-user balance
+	AccrualType           domain.LeaveAccrual `json:"accrual_type" binding:"omitempty,oneof=none monthly anniversary"`
+	AccrualDays           float64             `json:"accrual_days" binding:"min=0"`                    // Per month (monthly) or per year (anniversary)
+	CarryOverCapDays      *float64            `json:"carry_over_cap_days" binding:"omitempty,min=0"`   // Max days kept on each hire anniversary. null = no cap
+	CarryOverExpiryMonths int                 `json:"carry_over_expiry_months" binding:"min=0,max=11"` // Carried days expire this many months into the new year. 0 = never
+	UsesTimeBank          bool                `json:"uses_time_bank"`                                  // Comp time: approved requests debit their scheduled minutes from the time bank
+}
+
+type UpdateLeaveTypeRequest struct {
+	Name                  *string              `json:"name" binding:"omitempty,min=1"`
+	Paid                  *bool                `json:"paid"`
+	Active                *bool                `json:"active"` // Inactive types accept no new requests
+	TrackBalance          *bool                `json:"track_balance"`
+	AccrualType           *domain.LeaveAccrual `json:"accrual_type" binding:"omitempty,oneof=none monthly anniversary"`
+	AccrualDays           *float64             `json:"accrual_days" binding:"omitempty,min=0"`
+	CarryOverCapDays      *float64             `json:"carry_over_cap_days" binding:"omitempty,min=0"`
+	RemoveCarryOverCap    bool                 `json:"remove_carry_over_cap"` // Carry the whole balance over
+	CarryOverExpiryMonths *int                 `json:"carry_over_expiry_months" binding:"omitempty,min=0,max=11"`
+	UsesTimeBank          *bool                `json:"uses_time_bank"`
+}
+
+type LeaveTypeResponse struct {
+	ID                    uuid.UUID           `json:"id"`
+	Name                  string              `json:"name"`
+	Paid                  bool                `json:"paid"`
+	Active                bool                `json:"active"`
+	TrackBalance          bool                `json:"track_balance"`
+	TrackedSince          *string             `json:"tracked_since"` // Format: YYYY-MM-DD. Automatic movements start on this date
+	AccrualType           domain.LeaveAccrual `json:"accrual_type"`
+	AccrualDays           float64             `json:"accrual_days"`
+	CarryOverCapDays      *float64            `json:"carry_over_cap_days"`
+	CarryOverExpiryMonths int                 `json:"carry_over_expiry_months"`
+	UsesTimeBank          bool                `json:"uses_time_bank"`
+	CreatedAt             time.Time           `json:"created_at"`
+}
+
+func ToLeaveTypeResponse(leaveType *domain.LeaveType) *LeaveTypeResponse {
+	if leaveType == nil {
+		return nil
+	}
+
+	var trackedSince *string
+	if leaveType.TrackedSince != nil {
+		date := leaveType.TrackedSince.Format("2006-01-02")
+		trackedSince = &date
+	}
+
+	return &LeaveTypeResponse{
+		ID:                    leaveType.ID,
+		Name:                  leaveType.Name,
+		Paid:                  leaveType.Paid,
+		Active:                leaveType.Active,
+		TrackBalance:          leaveType.TrackBalance,
+		TrackedSince:          trackedSince,
+		AccrualType:           leaveType.AccrualType,
+		AccrualDays:           leaveType.AccrualDays,
+		CarryOverCapDays:      leaveType.CarryOverCapDays,
+		CarryOverExpiryMonths: leaveType.CarryOverExpiryMonths,
+		UsesTimeBank:          leaveType.UsesTimeBank,
+		CreatedAt:             leaveType.CreatedAt,
+	}
+}
+
+type LeaveTypeListParams struct {
+	Active *bool `form:"active" binding:"omitempty"`
+}
+
+type CreateLeaveRequest struct {
+	LeaveTypeID uuid.UUID        `json:"leave_type_id" binding:"required"`
+	StartDate   string           `json:"start_date" binding:"required"`                        // Format: YYYY-MM-DD
+	EndDate     string           `json:"end_date"`                                             // Format: YYYY-MM-DD, inclusive. Defaults to start_date
+	HalfDay     domain.LeaveHalf `json:"half_day" binding:"omitempty,oneof=morning afternoon"` // Only for single-day requests. Empty = full day
+	Reason      string           `json:"reason" binding:"required"`
+}
+
+type ReviewLeaveRequest struct {
+	Note *string `json:"note"`
+}
+
+type LeaveRequestResponse struct {
+	ID         uuid.UUID          `json:"id"`
+	AgencyID   uuid.UUID          `json:"agency_id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	LeaveType  *LeaveTypeResponse `json:"leave_type"`
+	StartDate  string             `json:"start_date"`
+	EndDate    string             `json:"end_date"`
+	HalfDay    domain.LeaveHalf   `json:"half_day"`
+	Days       float64            `json:"days"`    // Working days covered according to the user's schedules
+	Minutes    int                `json:"minutes"` // Scheduled minutes on those days
+	Reason     string             `json:"reason"`
+	Status     domain.LeaveStatus `json:"status"`
+	ReviewerID *uuid.UUID         `json:"reviewer_id"`
+	ReviewNote *string            `json:"review_note"`
+	ReviewedAt *time.Time         `json:"reviewed_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+func ToLeaveRequestResponse(leave *domain.LeaveRequest) *LeaveRequestResponse {
+	if leave == nil {
+		return nil
+	}
+
+	return &LeaveRequestResponse{
+		ID:         leave.ID,
+		AgencyID:   leave.AgencyID,
+		UserID:     leave.UserID,
+		LeaveType:  ToLeaveTypeResponse(&leave.LeaveType),
+		StartDate:  leave.StartDate.Format("2006-01-02"),
+		EndDate:    leave.EndDate.Format("2006-01-02"),
+		HalfDay:    leave.HalfDay,
+		Days:       leave.Days,
+		Minutes:    leave.Minutes,
+		Reason:     leave.Reason,
+		Status:     leave.Status,
+		ReviewerID: leave.ReviewerID,
+		ReviewNote: leave.ReviewNote,
+		ReviewedAt: leave.ReviewedAt,
+		CreatedAt:  leave.CreatedAt,
+	}
+}
+
+type LeaveRequestListParams struct {
+	PaginationParams
+	UserID      string `form:"user_id" binding:"omitempty"`
+	LeaveTypeID string `form:"leave_type_id" binding:"omitempty"`
+	Status      string `form:"status" binding:"omitempty,oneof=pending approved rejected cancelled"`
+	StartDate   string `form:"start_date" binding:"omitempty"` // Format: YYYY-MM-DD
+	EndDate     string `form:"end_date" binding:"omitempty"`   // Format: YYYY-MM-DD
+}
+
+// ToHalfDayLeaveScheduleResponse recorta el horario del día a la mitad que se trabaja cuando hay un permiso
+// de medio día: la mañana libre mueve la entrada a la mitad del turno y la tarde libre adelanta la salida.
+// En jornadas flexibles también se exige la mitad de los minutos y el tiempo núcleo se ajusta a la mitad trabajada.
+func ToHalfDayLeaveScheduleResponse(response *ScheduleResponse, leave *domain.LeaveRequest) *ScheduleResponse {
+	length := domain.BandOffset(response.EntryTimeMinutes, response.ExitTimeMinutes)
+	if length == 0 {
+		length = 24 * 60
+	}
+	half := length / 2
+	mid := (response.EntryTimeMinutes + half) % (24 * 60)
+
+	if response.Type == domain.ScheduleFlexible {
+		coreStart := domain.BandOffset(response.EntryTimeMinutes, response.CoreStartMinutes)
+		coreEnd := domain.BandOffset(response.EntryTimeMinutes, response.CoreEndMinutes)
+		if leave.HalfDay == domain.LeaveMorning {
+			coreStart, coreEnd = max(coreStart, half), max(coreEnd, half)
+		} else {
+			coreStart, coreEnd = min(coreStart, half), min(coreEnd, half)
+		}
+		response.CoreStartMinutes = (response.EntryTimeMinutes + coreStart) % (24 * 60)
+		response.CoreEndMinutes = (response.EntryTimeMinutes + coreEnd) % (24 * 60)
+		response.RequiredMinutes /= 2
+	}
+	if leave.HalfDay == domain.LeaveMorning {
+		response.EntryTimeMinutes = mid
+	} else {
+		response.ExitTimeMinutes = mid
+	}
+	response.CrossesMidnight = response.ExitTimeMinutes <= response.EntryTimeMinutes
+	response.Leave = ToLeaveRequestResponse(leave)
+	return response
+}
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateNFCTagRequest struct {
+	UID    string     `json:"uid" binding:"required"` // e.g. "04:A2:3B:C1:5D:80"
+	Label  string     `json:"label" binding:"required"`
+	SiteID *uuid.UUID `json:"site_id"` // Site where the tag is installed
+}
+
+type UpdateNFCTagRequest struct {
+	Label  *string    `json:"label" binding:"omitempty,min=1"`
+	SiteID *uuid.UUID `json:"site_id"` // The nil UUID detaches the tag from its site
+	Active *bool      `json:"active"`
+}
+
+type NFCTagResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	UID       string     `json:"uid"`
+	Label     string     `json:"label"`
+	SiteID    *uuid.UUID `json:"site_id"`
+	Site      *string    `json:"site"` // Site name
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func ToNFCTagResponse(tag *domain.NFCTag) *NFCTagResponse {
+	if tag == nil {
+		return nil
+	}
+
+	res := &NFCTagResponse{
+		ID:        tag.ID,
+		UID:       tag.UID,
+		Label:     tag.Label,
+		SiteID:    tag.SiteID,
+		Active:    tag.Active,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+	if tag.Site != nil {
+		res.Site = &tag.Site.Name
+	}
+	return res
+}
+
+type NFCTagListParams struct {
+	PaginationParams
+	Active *bool `form:"active" binding:"omitempty"`
+}
+
+type NFCTagUsageParams struct {
+	PaginationParams
+	TagID     string `form:"tag_id" binding:"omitempty"`
+	UserID    string `form:"user_id" binding:"omitempty"`
+	StartDate string `form:"start_date" binding:"omitempty"` // Format: YYYY-MM-DD
+	EndDate   string `form:"end_date" binding:"omitempty"`   // Format: YYYY-MM-DD
+}
+
+type NFCTagUsageResponse struct {
+	PunchID      uuid.UUID             `json:"punch_id"`
+	AttendanceID uuid.UUID             `json:"attendance_id"`
+	UserID       uuid.UUID             `json:"user_id"`
+	NFCTagID     uuid.UUID             `json:"nfc_tag_id"`
+	SiteID       *uuid.UUID            `json:"site_id"`
+	Site         *string               `json:"site"` // Site name when the punch was registered
+	Type         domain.AttendanceType `json:"type"`
+	Time         time.Time             `json:"time"`
+}
+
+func ToNFCTagUsageResponse(usage *domain.NFCTagUsage) *NFCTagUsageResponse {
+	if usage == nil {
+		return nil
+	}
+
+	return &NFCTagUsageResponse{
+		PunchID:      usage.PunchID,
+		AttendanceID: usage.AttendanceID,
+		UserID:       usage.UserID,
+		NFCTagID:     usage.NFCTagID,
+		SiteID:       usage.SiteID,
+		Site:         usage.Site,
+		Type:         usage.Type,
+		Time:         usage.Time,
+	}
+}
@@ -2,81 +2,170 @@ package dto
 
 import (
 	"quickattendance-go/internal/domain"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type CreateScheduleRequest struct {
-	Name               string      `json:"name"`
-	DaysOfWeek         []int       `json:"days_of_week"`
-	EntryTimeMinutes   int         `json:"entry_time_minutes"`
-	ExitTimeMinutes    int         `json:"exit_time_minutes"`
-	GracePeriodMinutes int         `json:"grace_period_minutes"`
-	IsDefault          bool        `json:"is_default"`
-	AssignedUsersIDs   []uuid.UUID `json:"assigned_users_ids"`
+	Name                       string      `json:"name"`
+	DaysOfWeek                 []int       `json:"days_of_week" binding:"dive,min=0,max=6"` // 0 = Sunday
+	EntryTimeMinutes           int         `json:"entry_time_minutes" binding:"min=0,max=1439"`
+	ExitTimeMinutes            int         `json:"exit_time_minutes" binding:"min=0,max=1439"`
+	GracePeriodMinutes         int         `json:"grace_period_minutes"`
+	EarlyLeaveToleranceMinutes int         `json:"early_leave_tolerance_minutes" binding:"min=0"`
+	IsDefault                  bool        `json:"is_default"`
+	AssignedUsersIDs           []uuid.UUID `json:"assigned_users_ids"`
+	EffectiveFrom              string      `json:"effective_from"` // Format: YYYY-MM-DD. Empty applies to any date
+	// Flexible: entry/exit are the band where work counts, lateness is judged against the core time
+	Type             domain.ScheduleType `json:"type" binding:"omitempty,oneof=fixed flexible"` // Defaults to fixed
+	CoreStartMinutes int                 `json:"core_start_minutes" binding:"min=0,max=1439"`
+	CoreEndMinutes   int                 `json:"core_end_minutes" binding:"min=0,max=1439"`
+	RequiredMinutes  int                 `json:"required_minutes" binding:"min=0,max=1440"` // Minutes to work per day
 }
 
 type UpdateScheduleRequest struct {
-	Name               *string      `json:"name"`
-	DaysOfWeek         *[]int       `json:"days_of_week"`
-	EntryTimeMinutes   *int         `json:"entry_time_minutes"`
-	ExitTimeMinutes    *int         `json:"exit_time_minutes"`
-	GracePeriodMinutes *int         `json:"grace_period_minutes"`
-	IsDefault          *bool        `json:"is_default"`
-	AssignedUsersIDs   *[]uuid.UUID `json:"assigned_users_ids"`
+	Name                       *string      `json:"name"`
+	DaysOfWeek                 *[]int       `json:"days_of_week" binding:"omitempty,dive,min=0,max=6"`
+	EntryTimeMinutes           *int         `json:"entry_time_minutes" binding:"omitempty,min=0,max=1439"`
+	ExitTimeMinutes            *int         `json:"exit_time_minutes" binding:"omitempty,min=0,max=1439"`
+	GracePeriodMinutes         *int         `json:"grace_period_minutes"`
+	EarlyLeaveToleranceMinutes *int         `json:"early_leave_tolerance_minutes" binding:"omitempty,min=0"`
+	IsDefault                  *bool        `json:"is_default"`
+	AssignedUsersIDs           *[]uuid.UUID `json:"assigned_users_ids"`
+	// Fecha desde la que rigen los cambios de días, horas o usuarios (YYYY-MM-DD). Por defecto, hoy
+	EffectiveFrom *string `json:"effective_from"`
+
+	Type             *domain.ScheduleType `json:"type" binding:"omitempty,oneof=fixed flexible"`
+	CoreStartMinutes *int                 `json:"core_start_minutes" binding:"omitempty,min=0,max=1439"`
+	CoreEndMinutes   *int                 `json:"core_end_minutes" binding:"omitempty,min=0,max=1439"`
+	RequiredMinutes  *int                 `json:"required_minutes" binding:"omitempty,min=0,max=1440"`
 }
 
 type ScheduleResponse struct {
 	ID       uuid.UUID `json:"id"`
+	SeriesID uuid.UUID `json:"series_id"`
+	Version  int       `json:"version"`
 	AgencyID uuid.UUID `json:"agency_id"`
 	Name     string    `json:"name"`
 	// El cliente deberia recibir un arreglo de enteros para los dias de la semana
-	DaysOfWeek         []int          `json:"days_of_week"`
-	EntryTimeMinutes   int            `json:"entry_time_minutes"`
-	ExitTimeMinutes    int            `json:"exit_time_minutes"`
-	GracePeriodMinutes int            `json:"grace_period_minutes"`
-	IsDefault          bool           `json:"is_default"`
-	AssignedUsers      []UserResponse `json:"assigned_users"`
-	CreatedAt          time.Time      `json:"created_at"`
-	UpdatedAt          time.Time      `json:"updated_at"`
+	DaysOfWeek                 []int          `json:"days_of_week"`
+	EntryTimeMinutes           int            `json:"entry_time_minutes"`
+	ExitTimeMinutes            int            `json:"exit_time_minutes"`
+	CrossesMidnight            bool           `json:"crosses_midnight"`
+	GracePeriodMinutes         int            `json:"grace_period_minutes"`
+	EarlyLeaveToleranceMinutes int            `json:"early_leave_tolerance_minutes"`
+	IsDefault                  bool           `json:"is_default"`
+	AssignedUsers              []UserResponse `json:"assigned_users"`
+	EffectiveFrom              *string        `json:"effective_from"` // Format: YYYY-MM-DD, null = no start limit
+	EffectiveTo                *string        `json:"effective_to"`   // Format: YYYY-MM-DD, inclusive. null = open-ended
+	CreatedAt                  time.Time      `json:"created_at"`
+	UpdatedAt                  time.Time      `json:"updated_at"`
+
+	// Jornada flexible: entrada y salida son la banda, el atraso se evalúa contra el tiempo núcleo
+	Type             domain.ScheduleType `json:"type"`
+	CoreStartMinutes int                 `json:"core_start_minutes"`
+	CoreEndMinutes   int                 `json:"core_end_minutes"`
+	RequiredMinutes  int                 `json:"required_minutes"` // Minutes to work per day
+
+	// Excepción que reemplazó entrada, salida y tolerancia en la fecha consultada (solo en /schedules/applicable)
+	Override *ScheduleOverrideResponse `json:"override,omitempty"`
+	// Turno de la rotación asignada que define la fecha consultada (solo en /schedules/applicable)
+	Rotation *RotationDayResponse `json:"rotation,omitempty"`
+	// Permiso aprobado de medio día que recortó el turno de la fecha consultada (solo en /schedules/applicable)
+	Leave *LeaveRequestResponse `json:"leave,omitempty"`
 }
 
-func ToScheduleResponse(schedule *domain.Schedule) *ScheduleResponse {
-	if schedule == nil {
-		return nil
-	}
+// ScheduleRefResponse identifica una versión de horario dentro de otra respuesta
+type ScheduleRefResponse struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Version int       `json:"version"`
+}
+
+// ScheduleConflictResponse es un usuario con dos horarios que aplican en los mismos días
+type ScheduleConflictResponse struct {
+	User       UserResponse          `json:"user"`
+	Schedules  []ScheduleRefResponse `json:"schedules"` // Oldest first; it is the one applied meanwhile
+	DaysOfWeek []int                 `json:"days_of_week"`
+	From       string                `json:"from"` // First date both apply (from today on)
+	To         *string               `json:"to"`   // Last date both apply. null = open-ended
+}
 
-	days := []int{}
-	for str := range strings.SplitSeq(schedule.DaysOfWeek, ",") {
-		if str == "" {
-			continue
+func ToScheduleConflictResponse(user *domain.User, first *domain.Schedule, second *domain.Schedule, days []int, today time.Time) *ScheduleConflictResponse {
+	from := today
+	for _, s := range []*domain.Schedule{first, second} {
+		if s.EffectiveFrom != nil && s.EffectiveFrom.After(from) {
+			from = *s.EffectiveFrom
 		}
+	}
 
-		if val, err := strconv.Atoi(str); err == nil {
-			days = append(days, val)
+	var to *string
+	var end *time.Time
+	for _, s := range []*domain.Schedule{first, second} {
+		if s.EffectiveTo != nil && (end == nil || s.EffectiveTo.Before(*end)) {
+			end = s.EffectiveTo
 		}
 	}
+	if end != nil {
+		last := end.Format("2006-01-02")
+		to = &last
+	}
+
+	return &ScheduleConflictResponse{
+		User: *ToUserResponse(user),
+		Schedules: []ScheduleRefResponse{
+			{ID: first.ID, Name: first.Name, Version: first.Version},
+			{ID: second.ID, Name: second.Name, Version: second.Version},
+		},
+		DaysOfWeek: days,
+		From:       from.Format("2006-01-02"),
+		To:         to,
+	}
+}
+
+func ToScheduleResponse(schedule *domain.Schedule) *ScheduleResponse {
+	if schedule == nil {
+		return nil
+	}
 
 	users := []UserResponse{}
 	for _, user := range schedule.AssignedUsers {
 		users = append(users, *ToUserResponse(&user))
 	}
 
+	var effectiveFrom, effectiveTo *string
+	if schedule.EffectiveFrom != nil {
+		from := schedule.EffectiveFrom.Format("2006-01-02")
+		effectiveFrom = &from
+	}
+	if schedule.EffectiveTo != nil {
+		to := schedule.EffectiveTo.Format("2006-01-02")
+		effectiveTo = &to
+	}
+
 	return &ScheduleResponse{
-		ID:                 schedule.ID,
-		AgencyID:           schedule.AgencyID,
-		Name:               schedule.Name,
-		DaysOfWeek:         days,
-		EntryTimeMinutes:   schedule.EntryTimeMinutes,
-		ExitTimeMinutes:    schedule.ExitTimeMinutes,
-		GracePeriodMinutes: schedule.GracePeriodMinutes,
-		IsDefault:          schedule.IsDefault,
-		AssignedUsers:      users,
-		CreatedAt:          schedule.CreatedAt,
-		UpdatedAt:          schedule.UpdatedAt,
+		ID:                         schedule.ID,
+		SeriesID:                   schedule.SeriesID,
+		Version:                    schedule.Version,
+		AgencyID:                   schedule.AgencyID,
+		Name:                       schedule.Name,
+		DaysOfWeek:                 schedule.DaysOfWeek.Days(),
+		EntryTimeMinutes:           schedule.EntryTimeMinutes,
+		ExitTimeMinutes:            schedule.ExitTimeMinutes,
+		CrossesMidnight:            schedule.CrossesMidnight(),
+		GracePeriodMinutes:         schedule.GracePeriodMinutes,
+		EarlyLeaveToleranceMinutes: schedule.EarlyLeaveToleranceMinutes,
+		Type:                       schedule.Type,
+		CoreStartMinutes:           schedule.CoreStartMinutes,
+		CoreEndMinutes:             schedule.CoreEndMinutes,
+		RequiredMinutes:            schedule.RequiredMinutes,
+		IsDefault:                  schedule.IsDefault,
+		AssignedUsers:              users,
+		EffectiveFrom:              effectiveFrom,
+		EffectiveTo:                effectiveTo,
+		CreatedAt:                  schedule.CreatedAt,
+		UpdatedAt:                  schedule.UpdatedAt,
 	}
 }
 
@@ -90,3 +179,84 @@ type GetApplicableScheduleParams struct {
 	UserID string `form:"user_id" binding:"omitempty"`
 	Date   string `form:"date" binding:"required"` // Format: YYYY-MM-DD
 }
+
+type CreateScheduleOverrideRequest struct {
+	Name               string      `json:"name" binding:"required"`
+	StartDate          string      `json:"start_date" binding:"required"` // Format: YYYY-MM-DD
+	EndDate            string      `json:"end_date"`                      // Format: YYYY-MM-DD, inclusive. Defaults to start_date
+	EntryTimeMinutes   *int        `json:"entry_time_minutes" binding:"required,min=0,max=1439"`
+	ExitTimeMinutes    *int        `json:"exit_time_minutes" binding:"required,min=0,max=1439"`
+	GracePeriodMinutes int         `json:"grace_period_minutes" binding:"min=0"`
+	UserIDs            []uuid.UUID `json:"user_ids"` // Empty applies to the whole agency
+}
+
+type ScheduleOverrideResponse struct {
+	ID                 uuid.UUID   `json:"id"`
+	Name               string      `json:"name"`
+	StartDate          string      `json:"start_date"`
+	EndDate            string      `json:"end_date"`
+	EntryTimeMinutes   int         `json:"entry_time_minutes"`
+	ExitTimeMinutes    int         `json:"exit_time_minutes"`
+	CrossesMidnight    bool        `json:"crosses_midnight"`
+	GracePeriodMinutes int         `json:"grace_period_minutes"`
+	UserIDs            []uuid.UUID `json:"user_ids"` // Empty when it applies to the whole agency
+	CreatedByID        uuid.UUID   `json:"created_by_id"`
+	CreatedAt          time.Time   `json:"created_at"`
+}
+
+func ToScheduleOverrideResponse(override *domain.ScheduleOverride) *ScheduleOverrideResponse {
+	if override == nil {
+		return nil
+	}
+
+	userIDs := []uuid.UUID{}
+	for _, u := range override.Users {
+		userIDs = append(userIDs, u.ID)
+	}
+
+	return &ScheduleOverrideResponse{
+		ID:                 override.ID,
+		Name:               override.Name,
+		StartDate:          override.StartDate.Format("2006-01-02"),
+		EndDate:            override.EndDate.Format("2006-01-02"),
+		EntryTimeMinutes:   override.EntryTimeMinutes,
+		ExitTimeMinutes:    override.ExitTimeMinutes,
+		CrossesMidnight:    override.CrossesMidnight(),
+		GracePeriodMinutes: override.GracePeriodMinutes,
+		UserIDs:            userIDs,
+		CreatedByID:        override.CreatedByID,
+		CreatedAt:          override.CreatedAt,
+	}
+}
+
+// ToOverriddenScheduleResponse devuelve el horario del día (semanal o de rotación, si lo había) con la entrada,
+// salida y tolerancia de la excepción. Sin horario, la excepción define el turno de ese día por sí sola.
+func ToOverriddenScheduleResponse(response *ScheduleResponse, override *domain.ScheduleOverride) *ScheduleResponse {
+	if response == nil {
+		response = &ScheduleResponse{
+			AgencyID:      override.AgencyID,
+			Name:          override.Name,
+			DaysOfWeek:    []int{},
+			AssignedUsers: []UserResponse{},
+		}
+	}
+
+	// La excepción fija entrada y salida aunque el horario del día sea flexible
+	response.Type = domain.ScheduleFixed
+	response.CoreStartMinutes = 0
+	response.CoreEndMinutes = 0
+	response.RequiredMinutes = 0
+	response.EntryTimeMinutes = override.EntryTimeMinutes
+	response.ExitTimeMinutes = override.ExitTimeMinutes
+	response.CrossesMidnight = override.CrossesMidnight()
+	response.GracePeriodMinutes = override.GracePeriodMinutes
+	response.Override = ToScheduleOverrideResponse(override)
+	return response
+}
+
+type ScheduleOverrideListParams struct {
+	PaginationParams
+	UserID    string `form:"user_id" binding:"omitempty"`
+	StartDate string `form:"start_date" binding:"omitempty"` // Format: YYYY-MM-DD
+	EndDate   string `form:"end_date" binding:"omitempty"`   // Format: YYYY-MM-DD
+}
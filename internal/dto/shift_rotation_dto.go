@@ -0,0 +1,147 @@
+package dto
+
+import (
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RotationShiftRequest struct {
+	Name                       string `json:"name" binding:"required"`
+	Days                       int    `json:"days" binding:"required,min=1"` // Consecutive days of this shift in the cycle
+	Off                        bool   `json:"off"`                           // Rest days: times are ignored
+	EntryTimeMinutes           int    `json:"entry_time_minutes" binding:"min=0,max=1439"`
+	ExitTimeMinutes            int    `json:"exit_time_minutes" binding:"min=0,max=1439"`
+	GracePeriodMinutes         int    `json:"grace_period_minutes" binding:"min=0"`
+	EarlyLeaveToleranceMinutes int    `json:"early_leave_tolerance_minutes" binding:"min=0"`
+}
+
+type CreateShiftRotationRequest struct {
+	Name       string                 `json:"name" binding:"required"`
+	AnchorDate string                 `json:"anchor_date" binding:"required"` // Format: YYYY-MM-DD. First day of the cycle for offset 0
+	Shifts     []RotationShiftRequest `json:"shifts" binding:"required,min=1,dive"`
+}
+
+type RotationShiftResponse struct {
+	Name                       string `json:"name"`
+	Days                       int    `json:"days"`
+	Off                        bool   `json:"off"`
+	EntryTimeMinutes           int    `json:"entry_time_minutes"`
+	ExitTimeMinutes            int    `json:"exit_time_minutes"`
+	CrossesMidnight            bool   `json:"crosses_midnight"`
+	GracePeriodMinutes         int    `json:"grace_period_minutes"`
+	EarlyLeaveToleranceMinutes int    `json:"early_leave_tolerance_minutes"`
+}
+
+type ShiftRotationResponse struct {
+	ID         uuid.UUID               `json:"id"`
+	Name       string                  `json:"name"`
+	AnchorDate string                  `json:"anchor_date"`
+	CycleDays  int                     `json:"cycle_days"`
+	Shifts     []RotationShiftResponse `json:"shifts"`
+	CreatedAt  time.Time               `json:"created_at"`
+}
+
+func ToShiftRotationResponse(rotation *domain.ShiftRotation) *ShiftRotationResponse {
+	if rotation == nil {
+		return nil
+	}
+
+	shifts := make([]RotationShiftResponse, len(rotation.Shifts))
+	for i, s := range rotation.Shifts {
+		shifts[i] = RotationShiftResponse{
+			Name:                       s.Name,
+			Days:                       s.Days,
+			Off:                        s.Off,
+			EntryTimeMinutes:           s.EntryTimeMinutes,
+			ExitTimeMinutes:            s.ExitTimeMinutes,
+			CrossesMidnight:            s.CrossesMidnight(),
+			GracePeriodMinutes:         s.GracePeriodMinutes,
+			EarlyLeaveToleranceMinutes: s.EarlyLeaveToleranceMinutes,
+		}
+	}
+
+	return &ShiftRotationResponse{
+		ID:         rotation.ID,
+		Name:       rotation.Name,
+		AnchorDate: rotation.AnchorDate.Format("2006-01-02"),
+		CycleDays:  rotation.CycleDays,
+		Shifts:     shifts,
+		CreatedAt:  rotation.CreatedAt,
+	}
+}
+
+type ShiftRotationListParams struct {
+	PaginationParams
+}
+
+type AssignRotationRequest struct {
+	UserID     uuid.UUID `json:"user_id" binding:"required"`
+	OffsetDays int       `json:"offset_days" binding:"min=0"` // Days the user is ahead in the cycle
+	StartDate  string    `json:"start_date"`                  // Format: YYYY-MM-DD. Defaults to today
+}
+
+type RotationAssignmentResponse struct {
+	ID         uuid.UUID `json:"id"`
+	RotationID uuid.UUID `json:"rotation_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	OffsetDays int       `json:"offset_days"`
+	StartDate  string    `json:"start_date"`
+	EndDate    *string   `json:"end_date"` // Inclusive. null = open-ended
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func ToRotationAssignmentResponse(assignment *domain.RotationAssignment) *RotationAssignmentResponse {
+	if assignment == nil {
+		return nil
+	}
+
+	var endDate *string
+	if assignment.EndDate != nil {
+		end := assignment.EndDate.Format("2006-01-02")
+		endDate = &end
+	}
+
+	return &RotationAssignmentResponse{
+		ID:         assignment.ID,
+		RotationID: assignment.RotationID,
+		UserID:     assignment.UserID,
+		OffsetDays: assignment.OffsetDays,
+		StartDate:  assignment.StartDate.Format("2006-01-02"),
+		EndDate:    endDate,
+		CreatedAt:  assignment.CreatedAt,
+	}
+}
+
+// RotationDayResponse indica qué turno de la rotación define el día consultado
+type RotationDayResponse struct {
+	RotationID   uuid.UUID `json:"rotation_id"`
+	RotationName string    `json:"rotation_name"`
+	AssignmentID uuid.UUID `json:"assignment_id"`
+	ShiftName    string    `json:"shift_name"`
+	CycleDay     int       `json:"cycle_day"` // 1 = first day of the cycle
+}
+
+// ToRotationScheduleResponse arma el horario del día a partir del turno de la rotación
+func ToRotationScheduleResponse(assignment *domain.RotationAssignment, shift *domain.RotationShift, cycleDay int) *ScheduleResponse {
+	return &ScheduleResponse{
+		AgencyID:                   assignment.AgencyID,
+		Name:                       assignment.Rotation.Name + " - " + shift.Name,
+		DaysOfWeek:                 []int{},
+		EntryTimeMinutes:           shift.EntryTimeMinutes,
+		ExitTimeMinutes:            shift.ExitTimeMinutes,
+		CrossesMidnight:            shift.CrossesMidnight(),
+		GracePeriodMinutes:         shift.GracePeriodMinutes,
+		EarlyLeaveToleranceMinutes: shift.EarlyLeaveToleranceMinutes,
+		Type:                       domain.ScheduleFixed,
+		AssignedUsers:              []UserResponse{},
+		Rotation: &RotationDayResponse{
+			RotationID:   assignment.RotationID,
+			RotationName: assignment.Rotation.Name,
+			AssignmentID: assignment.ID,
+			ShiftName:    shift.Name,
+			CycleDay:     cycleDay + 1,
+		},
+	}
+}
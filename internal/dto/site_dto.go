@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GeoPointRequest es un vértice de una geocerca poligonal
+type GeoPointRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+}
+
+// CreateSiteRequest acepta un círculo (latitude, longitude y radius_meters) o un polígono.
+// En un polígono el centro es opcional: por defecto se usa el promedio de los vértices.
+type CreateSiteRequest struct {
+	Name         string            `json:"name" binding:"required"`
+	Latitude     *float64          `json:"latitude" binding:"required_without=Polygon,omitempty,min=-90,max=90"`
+	Longitude    *float64          `json:"longitude" binding:"required_without=Polygon,omitempty,min=-180,max=180"`
+	RadiusMeters int               `json:"radius_meters" binding:"required_without=Polygon,omitempty,min=1"`
+	Polygon      []GeoPointRequest `json:"polygon" binding:"omitempty,min=3,dive"`
+}
+
+// UpdateSiteRequest: enviar polygon vacío ([]) convierte la sede en un círculo (requiere radius_meters)
+type UpdateSiteRequest struct {
+	Name         *string           `json:"name" binding:"omitempty,min=1"`
+	Latitude     *float64          `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude    *float64          `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	RadiusMeters *int              `json:"radius_meters" binding:"omitempty,min=1"`
+	Polygon      []GeoPointRequest `json:"polygon" binding:"omitempty,dive"`
+	Active       *bool             `json:"active"`
+}
+
+type SiteResponse struct {
+	ID           uuid.UUID         `json:"id"`
+	Name         string            `json:"name"`
+	Latitude     float64           `json:"latitude"`
+	Longitude    float64           `json:"longitude"`
+	RadiusMeters int               `json:"radius_meters"`
+	Polygon      []domain.GeoPoint `json:"polygon"`
+	Active       bool              `json:"active"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+func ToGeoPoints(points []GeoPointRequest) []domain.GeoPoint {
+	result := make([]domain.GeoPoint, len(points))
+	for i, p := range points {
+		result[i] = domain.GeoPoint{Latitude: *p.Latitude, Longitude: *p.Longitude}
+	}
+	return result
+}
+
+func ToSiteResponse(site *domain.Site) *SiteResponse {
+	if site == nil {
+		return nil
+	}
+
+	return &SiteResponse{
+		ID:           site.ID,
+		Name:         site.Name,
+		Latitude:     site.Latitude,
+		Longitude:    site.Longitude,
+		RadiusMeters: site.RadiusMeters,
+		Polygon:      site.Polygon,
+		Active:       site.Active,
+		CreatedAt:    site.CreatedAt,
+		UpdatedAt:    site.UpdatedAt,
+	}
+}
+
+type SiteListParams struct {
+	PaginationParams
+	Active *bool `form:"active" binding:"omitempty"`
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TimeBankBalanceParams struct {
+	UserID string `form:"user_id" binding:"omitempty"` // Admins only. Defaults to the caller
+}
+
+type TimeBankExpirationResponse struct {
+	Date    string `json:"date"`    // Format: YYYY-MM-DD
+	Minutes int    `json:"minutes"` // Still unused credit that expires on date
+}
+
+type TimeBankBalanceResponse struct {
+	UserID         uuid.UUID                     `json:"user_id"`
+	BalanceMinutes int                           `json:"balance_minutes"` // Negative when more was debited than credited
+	PendingMinutes int                           `json:"pending_minutes"` // Requested as comp time in pending leave requests
+	Expirations    []*TimeBankExpirationResponse `json:"expirations"`     // Upcoming expiries, oldest first
+}
+
+type TimeBankLedgerParams struct {
+	PaginationParams
+	UserID    string `form:"user_id" binding:"omitempty"` // Admins only. Defaults to the caller
+	Kind      string `form:"kind" binding:"omitempty,oneof=overtime early_leave comp_time adjustment expiry"`
+	StartDate string `form:"start_date" binding:"omitempty"` // Format: YYYY-MM-DD
+	EndDate   string `form:"end_date" binding:"omitempty"`   // Format: YYYY-MM-DD
+}
+
+type CreateTimeBankAdjustmentRequest struct {
+	UserID  uuid.UUID `json:"user_id" binding:"required"`
+	Minutes int       `json:"minutes" binding:"required"` // Positive credits, negative debits
+	Date    string    `json:"date"`                       // Format: YYYY-MM-DD. Defaults to today
+	Note    string    `json:"note" binding:"required"`
+}
+
+type TimeBankEntryResponse struct {
+	ID             uuid.UUID                `json:"id"`
+	UserID         uuid.UUID                `json:"user_id"`
+	Date           string                   `json:"date"`
+	Kind           domain.TimeBankEntryKind `json:"kind"`
+	Minutes        int                      `json:"minutes"`
+	AttendanceID   *uuid.UUID               `json:"attendance_id"`
+	LeaveRequestID *uuid.UUID               `json:"leave_request_id"`
+	ExpiredEntryID *uuid.UUID               `json:"expired_entry_id"`
+	CreatedByID    *uuid.UUID               `json:"created_by_id"`
+	Note           *string                  `json:"note"`
+	CreatedAt      time.Time                `json:"created_at"`
+}
+
+func ToTimeBankEntryResponse(entry *domain.TimeBankEntry) *TimeBankEntryResponse {
+	if entry == nil {
+		return nil
+	}
+
+	return &TimeBankEntryResponse{
+		ID:             entry.ID,
+		UserID:         entry.UserID,
+		Date:           entry.Date.Format("2006-01-02"),
+		Kind:           entry.Kind,
+		Minutes:        entry.Minutes,
+		AttendanceID:   entry.AttendanceID,
+		LeaveRequestID: entry.LeaveRequestID,
+		ExpiredEntryID: entry.ExpiredEntryID,
+		CreatedByID:    entry.CreatedByID,
+		Note:           entry.Note,
+		CreatedAt:      entry.CreatedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TimesheetParams struct {
+	UserID string `form:"user_id" binding:"omitempty"` // Admins only. Defaults to the caller
+	Period string `form:"period" binding:"required"`   // Format: YYYY-MM
+}
+
+type TimesheetListParams struct {
+	PaginationParams
+	Period string `form:"period" binding:"required"` // Format: YYYY-MM
+}
+
+type AcknowledgeTimesheetRequest struct {
+	Period string `json:"period" binding:"required"` // Format: YYYY-MM
+}
+
+type TimesheetActionRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Period string    `json:"period" binding:"required"` // Format: YYYY-MM
+}
+
+type TimesheetResponse struct {
+	ID             *uuid.UUID             `json:"id"` // null until the employee acknowledges it
+	UserID         uuid.UUID              `json:"user_id"`
+	Period         string                 `json:"period"`
+	StartDate      string                 `json:"start_date"`
+	EndDate        string                 `json:"end_date"`
+	Status         domain.TimesheetStatus `json:"status"`
+	Days           []domain.TimesheetDay  `json:"days"`
+	Totals         domain.TimesheetTotals `json:"totals"`
+	Changed        bool                   `json:"changed"` // Acknowledged, but the attendance changed since then: it must be acknowledged again
+	AcknowledgedAt *time.Time             `json:"acknowledged_at"`
+	AcknowledgedIP *string                `json:"acknowledged_ip"`
+	LockedAt       *time.Time             `json:"locked_at"`
+	LockedByID     *uuid.UUID             `json:"locked_by_id"`
+}
+
+type TimesheetSummaryResponse struct {
+	UserID         uuid.UUID               `json:"user_id"`
+	FirstName      string                  `json:"first_name"`
+	LastName       *string                 `json:"last_name"`
+	Period         string                  `json:"period"`
+	Status         domain.TimesheetStatus  `json:"status"`
+	Totals         *domain.TimesheetTotals `json:"totals"` // As acknowledged; null while open
+	AcknowledgedAt *time.Time              `json:"acknowledged_at"`
+	LockedAt       *time.Time              `json:"locked_at"`
+}
+
+// ToTimesheetResponse arma la respuesta con el contenido guardado de la planilla
+func ToTimesheetResponse(timesheet *domain.Timesheet) *TimesheetResponse {
+	if timesheet == nil {
+		return nil
+	}
+
+	return &TimesheetResponse{
+		ID:             &timesheet.ID,
+		UserID:         timesheet.UserID,
+		Period:         timesheet.PeriodStart.Format("2006-01"),
+		StartDate:      timesheet.PeriodStart.Format("2006-01-02"),
+		EndDate:        timesheet.PeriodEnd.Format("2006-01-02"),
+		Status:         timesheet.Status,
+		Days:           timesheet.Days,
+		Totals:         timesheet.Totals,
+		AcknowledgedAt: timesheet.AcknowledgedAt,
+		AcknowledgedIP: timesheet.AcknowledgedIP,
+		LockedAt:       timesheet.LockedAt,
+		LockedByID:     timesheet.LockedByID,
+	}
+}
@@ -11,6 +11,7 @@ type InviteUserRequest struct {
 	Email     string `json:"email" binding:"required,email"`
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name"`
+	HireDate  string `json:"hire_date"` // Format: YYYY-MM-DD. Leave accrual starts here; defaults to the invitation date
 }
 
 type ActivateUserRequest struct {
@@ -36,6 +37,7 @@ type UpdateUserRequest struct {
 	HomeLatitude     *float64 `json:"home_latitude"`
 	HomeLongitude    *float64 `json:"home_longitude"`
 	HomeRadiusMeters *int     `json:"home_radius_meters"`
+	HireDate         *string  `json:"hire_date"` // Format: YYYY-MM-DD
 }
 
 type UserResponse struct {
@@ -48,6 +50,7 @@ type UserResponse struct {
 	HomeLatitude     *float64      `json:"home_latitude"`
 	HomeLongitude    *float64      `json:"home_longitude"`
 	HomeRadiusMeters *int          `json:"home_radius_meters"`
+	HireDate         *string       `json:"hire_date"` // Format: YYYY-MM-DD
 	CreatedAt        time.Time     `json:"created_at"`
 	UpdatedAt        time.Time     `json:"updated_at"`
 }
@@ -62,6 +65,12 @@ func ToUserResponse(user *domain.User) *UserResponse {
 		return nil
 	}
 
+	var hireDate *string
+	if user.HireDate != nil {
+		date := user.HireDate.Format("2006-01-02")
+		hireDate = &date
+	}
+
 	return &UserResponse{
 		ID:               user.ID,
 		FirstName:        user.FirstName,
@@ -72,6 +81,7 @@ func ToUserResponse(user *domain.User) *UserResponse {
 		HomeLatitude:     user.HomeLatitude,
 		HomeLongitude:    user.HomeLongitude,
 		HomeRadiusMeters: user.HomeRadiusMeters,
+		HireDate:         hireDate,
 		CreatedAt:        user.CreatedAt,
 		UpdatedAt:        user.UpdatedAt,
 	}
@@ -74,6 +74,19 @@ func (r *AgencyRepo) GetByDomain(ctx context.Context, domainName string) (*domai
 	return &agency, nil
 }
 
+func (r *AgencyRepo) ListActive(ctx context.Context) ([]*domain.Agency, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var agencies []*domain.Agency
+	if err := db.WithContext(ctx).Where("is_active = ?", true).Find(&agencies).Error; err != nil {
+		return nil, err
+	}
+	return agencies, nil
+}
+
 func (r *AgencyRepo) Update(ctx context.Context, agency *domain.Agency) error {
 	db, ok := ctx.Value("tx").(*gorm.DB)
 	if !ok {
@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"quickattendance-go/internal/domain"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AttendanceRepo struct {
@@ -22,7 +24,13 @@ func (r *AttendanceRepo) Create(ctx context.Context, attendance *domain.Attendan
 	if !ok {
 		db = r.db
 	}
-	return db.WithContext(ctx).Create(attendance).Error
+
+	err := db.WithContext(ctx).Create(attendance).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// idx_attendance_user_date: solo un registro por usuario y fecha
+		return domain.ErrAttendanceExists
+	}
+	return err
 }
 
 func (r *AttendanceRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Attendance, error) {
@@ -32,7 +40,7 @@ func (r *AttendanceRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Att
 	}
 
 	var attendance domain.Attendance
-	if err := db.WithContext(ctx).First(&attendance, id).Error; err != nil {
+	if err := db.WithContext(ctx).Preload("Punches", orderPunches).First(&attendance, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrAttendanceNotFound
 		}
@@ -41,28 +49,90 @@ func (r *AttendanceRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Att
 	return &attendance, nil
 }
 
-func (r *AttendanceRepo) GetTodayByUserID(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID) (*domain.Attendance, error) {
+func (r *AttendanceRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Attendance, error) {
 	db, ok := ctx.Value("tx").(*gorm.DB)
 	if !ok {
 		db = r.db
 	}
 
 	var attendance domain.Attendance
-	// Considerar zona horaria si fuera necesario, de momento UTC/Local del servidor
-	today := time.Now().Format("2006-01-02")
+	if err := db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Punches", orderPunches).First(&attendance, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrAttendanceNotFound
+		}
+		return nil, err
+	}
+	return &attendance, nil
+}
 
+// GetOpenByUserID busca la última asistencia sin checkout cuyo turno comenzó desde since.
+// Los turnos nocturnos comienzan el día anterior, por lo que no basta con buscar la fecha de hoy.
+func (r *AttendanceRepo) GetOpenByUserID(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, since time.Time) (*domain.Attendance, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var attendances []domain.Attendance
 	err := db.WithContext(ctx).
-		Where("agency_id = ? AND user_id = ? AND date = ?", agencyID, userID, today).
-		First(&attendance).Error
+		Preload("Punches", orderPunches).
+		Where("agency_id = ? AND user_id = ? AND date >= ?", agencyID, userID, since.Format("2006-01-02")).
+		Where("check_in_time IS NOT NULL AND check_out_time IS NULL AND voided_at IS NULL").
+		Order("date DESC").
+		Limit(1).
+		Find(&attendances).Error
 
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, nil
-		}
 		return nil, err
 	}
 
-	return &attendance, nil
+	if len(attendances) == 0 {
+		return nil, nil
+	}
+
+	return &attendances[0], nil
+}
+
+func (r *AttendanceRepo) GetByUserAndDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*domain.Attendance, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var attendances []domain.Attendance
+	err := db.WithContext(ctx).
+		Preload("Punches", orderPunches).
+		Where("agency_id = ? AND user_id = ? AND date = ? AND voided_at IS NULL", agencyID, userID, date.Format("2006-01-02")).
+		Limit(1).
+		Find(&attendances).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attendances) == 0 {
+		return nil, nil
+	}
+
+	return &attendances[0], nil
+}
+
+// ExistsByUserAndDate indica si hay algún registro para ese día, incluidos los anulados
+func (r *AttendanceRepo) ExistsByUserAndDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (bool, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var count int64
+	err := db.WithContext(ctx).
+		Model(&domain.Attendance{}).
+		Where("agency_id = ? AND user_id = ? AND date = ?", agencyID, userID, date.Format("2006-01-02")).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
 func (r *AttendanceRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.AttendanceFilter) ([]*domain.Attendance, error) {
@@ -72,7 +142,60 @@ func (r *AttendanceRepo) List(ctx context.Context, agencyID uuid.UUID, filter do
 	}
 
 	var attendances []*domain.Attendance
-	query := db.WithContext(ctx).Where("agency_id = ?", agencyID)
+	query := filterAttendances(db.WithContext(ctx).Preload("Punches", orderPunches), agencyID, filter)
+
+	if filter.Flagged != nil {
+		query = query.Order("date DESC")
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Find(&attendances).Error; err != nil {
+		return nil, err
+	}
+
+	return attendances, nil
+}
+
+// ListInBatches pagina por clave (fecha, id) en vez de usar offset, así cada lote cuesta lo mismo
+// aunque el filtro abarque decenas de miles de registros. Solo un lote queda en memoria a la vez.
+func (r *AttendanceRepo) ListInBatches(ctx context.Context, agencyID uuid.UUID, filter domain.AttendanceFilter, batchSize int, fn func([]*domain.Attendance) error) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var last *domain.Attendance
+	for {
+		var batch []*domain.Attendance
+		query := filterAttendances(db.WithContext(ctx).Preload("User").Preload("Punches", orderPunches), agencyID, filter)
+		if last != nil {
+			query = query.Where("(date, id) > (?, ?)", last.Date.Format("2006-01-02"), last.ID)
+		}
+
+		if err := query.Order("date ASC, id ASC").Limit(batchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		last = batch[len(batch)-1]
+	}
+}
+
+// filterAttendances aplica los filtros comunes de los listados; los registros anulados nunca se incluyen
+func filterAttendances(query *gorm.DB, agencyID uuid.UUID, filter domain.AttendanceFilter) *gorm.DB {
+	query = query.Where("agency_id = ? AND voided_at IS NULL", agencyID)
 
 	if filter.UserID != uuid.Nil {
 		query = query.Where("user_id = ?", filter.UserID)
@@ -87,20 +210,22 @@ func (r *AttendanceRepo) List(ctx context.Context, agencyID uuid.UUID, filter do
 	}
 
 	if filter.Status != "" {
-		query = query.Where("status = ?", filter.Status)
+		query = query.Where("entry_status = ? OR exit_status = ?", filter.Status, filter.Status)
 	}
 
-	// Pagination
-	if filter.Limit > 0 {
-		offset := (filter.Page - 1) * filter.Limit
-		query = query.Offset(offset).Limit(filter.Limit)
+	if filter.EntryStatus != "" {
+		query = query.Where("entry_status = ?", filter.EntryStatus)
 	}
 
-	if err := query.Find(&attendances).Error; err != nil {
-		return nil, err
+	if filter.ExitStatus != "" {
+		query = query.Where("exit_status = ?", filter.ExitStatus)
 	}
 
-	return attendances, nil
+	if filter.Flagged != nil {
+		query = query.Where("flagged_for_review = ?", *filter.Flagged)
+	}
+
+	return query
 }
 
 func (r *AttendanceRepo) Update(ctx context.Context, attendance *domain.Attendance) error {
@@ -111,6 +236,64 @@ func (r *AttendanceRepo) Update(ctx context.Context, attendance *domain.Attendan
 	return db.WithContext(ctx).Save(attendance).Error
 }
 
+func (r *AttendanceRepo) AddPunch(ctx context.Context, punch *domain.AttendancePunch) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Create(punch).Error
+}
+
+func (r *AttendanceRepo) UpdatePunch(ctx context.Context, punch *domain.AttendancePunch) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Save(punch).Error
+}
+
+func (r *AttendanceRepo) GetLastLocatedPunch(ctx context.Context, userID uuid.UUID, before time.Time) (*domain.AttendancePunch, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var punches []domain.AttendancePunch
+	err := db.WithContext(ctx).
+		Table("attendance_punches AS p").
+		Select("p.*").
+		Joins("JOIN attendances AS a ON a.id = p.attendance_id").
+		Where("a.user_id = ? AND a.voided_at IS NULL", userID).
+		Where("p.latitude IS NOT NULL AND p.longitude IS NOT NULL AND p.time < ?", before).
+		Order("p.time DESC").
+		Limit(1).
+		Find(&punches).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(punches) == 0 {
+		return nil, nil
+	}
+	return &punches[0], nil
+}
+
+func (r *AttendanceRepo) CountPunchesAtLocation(ctx context.Context, userID uuid.UUID, lat float64, lng float64, before time.Time) (int64, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var count int64
+	err := db.WithContext(ctx).
+		Table("attendance_punches AS p").
+		Joins("JOIN attendances AS a ON a.id = p.attendance_id").
+		Where("a.user_id = ? AND a.voided_at IS NULL", userID).
+		Where("p.latitude = ? AND p.longitude = ? AND p.time < ?", lat, lng, before).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *AttendanceRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	db, ok := ctx.Value("tx").(*gorm.DB)
 	if !ok {
@@ -118,3 +301,8 @@ func (r *AttendanceRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 	return db.WithContext(ctx).Delete(&domain.Attendance{}, id).Error
 }
+
+// orderPunches carga las marcas en orden cronológico
+func orderPunches(db *gorm.DB) *gorm.DB {
+	return db.Order("time ASC")
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CorrectionRepo struct {
+	db *gorm.DB
+}
+
+func NewCorrectionRepo(db *gorm.DB) *CorrectionRepo {
+	return &CorrectionRepo{db: db}
+}
+
+func (r *CorrectionRepo) Create(ctx context.Context, correction *domain.AttendanceCorrection) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Create(correction).Error
+}
+
+func (r *CorrectionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.AttendanceCorrection, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var correction domain.AttendanceCorrection
+	if err := db.WithContext(ctx).Preload("User").First(&correction, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrCorrectionNotFound
+		}
+		return nil, err
+	}
+	return &correction, nil
+}
+
+func (r *CorrectionRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.AttendanceCorrection, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var correction domain.AttendanceCorrection
+	if err := db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Preload("User").First(&correction, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrCorrectionNotFound
+		}
+		return nil, err
+	}
+	return &correction, nil
+}
+
+func (r *CorrectionRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.CorrectionFilter) ([]*domain.AttendanceCorrection, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var corrections []*domain.AttendanceCorrection
+	query := db.WithContext(ctx).Where("agency_id = ?", agencyID)
+
+	if filter.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("created_at DESC").Find(&corrections).Error; err != nil {
+		return nil, err
+	}
+
+	return corrections, nil
+}
+
+func (r *CorrectionRepo) Update(ctx context.Context, correction *domain.AttendanceCorrection) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Omit("User").Save(correction).Error
+}
+
+type AttendanceRevisionRepo struct {
+	db *gorm.DB
+}
+
+func NewAttendanceRevisionRepo(db *gorm.DB) *AttendanceRevisionRepo {
+	return &AttendanceRevisionRepo{db: db}
+}
+
+func (r *AttendanceRevisionRepo) Create(ctx context.Context, revision *domain.AttendanceRevision) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Create(revision).Error
+}
+
+func (r *AttendanceRevisionRepo) ListByAttendanceID(ctx context.Context, attendanceID uuid.UUID) ([]*domain.AttendanceRevision, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var revisions []*domain.AttendanceRevision
+	if err := db.WithContext(ctx).Where("attendance_id = ?", attendanceID).Order("created_at ASC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HolidayRepo struct {
+	db *gorm.DB
+}
+
+func NewHolidayRepo(db *gorm.DB) *HolidayRepo {
+	return &HolidayRepo{db: db}
+}
+
+func (r *HolidayRepo) Create(ctx context.Context, holiday *domain.Holiday) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).Create(holiday).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrHolidayExists
+	}
+	return err
+}
+
+func (r *HolidayRepo) CreateBatch(ctx context.Context, holidays []*domain.Holiday) ([]*domain.Holiday, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	if len(holidays) == 0 {
+		return nil, nil
+	}
+
+	// El índice único de agencia y fecha descarta las fechas que otra importación agregó mientras tanto
+	res := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(holidays, 100)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == int64(len(holidays)) {
+		return holidays, nil
+	}
+
+	ids := make([]uuid.UUID, len(holidays))
+	for i, h := range holidays {
+		ids[i] = h.ID
+	}
+	var created []*domain.Holiday
+	if err := db.WithContext(ctx).Where("id IN ?", ids).Order("date").Find(&created).Error; err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *HolidayRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Holiday, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var holiday domain.Holiday
+	if err := db.WithContext(ctx).First(&holiday, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrHolidayNotFound
+		}
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+func (r *HolidayRepo) FindByDate(ctx context.Context, agencyID uuid.UUID, date time.Time) (*domain.Holiday, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	day := date.Format("2006-01-02")
+
+	// Un feriado exacto tiene prioridad sobre uno recurrente del mismo día
+	var holidays []domain.Holiday
+	err := db.WithContext(ctx).
+		Where("agency_id = ?", agencyID).
+		Where("date = ? OR (recurring AND date <= ? AND EXTRACT(MONTH FROM date) = ? AND EXTRACT(DAY FROM date) = ?)",
+			day, day, int(date.Month()), date.Day()).
+		Order("recurring ASC").
+		Limit(1).
+		Find(&holidays).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(holidays) == 0 {
+		return nil, nil
+	}
+	return &holidays[0], nil
+}
+
+func (r *HolidayRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.HolidayFilter) ([]*domain.Holiday, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var holidays []*domain.Holiday
+	query := db.WithContext(ctx).Where("agency_id = ?", agencyID)
+
+	if filter.Year > 0 {
+		first := fmt.Sprintf("%04d-01-01", filter.Year)
+		last := fmt.Sprintf("%04d-12-31", filter.Year)
+		query = query.Where("(date BETWEEN ? AND ?) OR (recurring AND date <= ?)", first, last, last)
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("date ASC").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (r *HolidayRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	return db.WithContext(ctx).Delete(&domain.Holiday{}, id).Error
+}
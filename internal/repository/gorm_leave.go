@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LeaveTypeRepo struct {
+	db *gorm.DB
+}
+
+func NewLeaveTypeRepo(db *gorm.DB) *LeaveTypeRepo {
+	return &LeaveTypeRepo{db: db}
+}
+
+func (r *LeaveTypeRepo) Create(ctx context.Context, leaveType *domain.LeaveType) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).Create(leaveType).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrLeaveTypeExists
+	}
+	return err
+}
+
+func (r *LeaveTypeRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.LeaveType, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var leaveType domain.LeaveType
+	if err := db.WithContext(ctx).First(&leaveType, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrLeaveTypeNotFound
+		}
+		return nil, err
+	}
+	return &leaveType, nil
+}
+
+func (r *LeaveTypeRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.LeaveTypeFilter) ([]*domain.LeaveType, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var leaveTypes []*domain.LeaveType
+	query := db.WithContext(ctx).Where("agency_id = ?", agencyID)
+
+	if filter.Active != nil {
+		query = query.Where("active = ?", *filter.Active)
+	}
+
+	if err := query.Order("name ASC").Find(&leaveTypes).Error; err != nil {
+		return nil, err
+	}
+	return leaveTypes, nil
+}
+
+func (r *LeaveTypeRepo) Update(ctx context.Context, leaveType *domain.LeaveType) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).Save(leaveType).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrLeaveTypeExists
+	}
+	return err
+}
+
+type LeaveRequestRepo struct {
+	db *gorm.DB
+}
+
+func NewLeaveRequestRepo(db *gorm.DB) *LeaveRequestRepo {
+	return &LeaveRequestRepo{db: db}
+}
+
+func (r *LeaveRequestRepo) Create(ctx context.Context, leave *domain.LeaveRequest) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Omit("User", "LeaveType").Create(leave).Error
+}
+
+func (r *LeaveRequestRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.LeaveRequest, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var leave domain.LeaveRequest
+	if err := db.WithContext(ctx).Preload("User").Preload("LeaveType").First(&leave, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrLeaveRequestNotFound
+		}
+		return nil, err
+	}
+	return &leave, nil
+}
+
+func (r *LeaveRequestRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.LeaveRequest, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var leave domain.LeaveRequest
+	if err := db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Preload("User").Preload("LeaveType").First(&leave, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrLeaveRequestNotFound
+		}
+		return nil, err
+	}
+	return &leave, nil
+}
+
+func (r *LeaveRequestRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.LeaveRequestFilter) ([]*domain.LeaveRequest, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var leaves []*domain.LeaveRequest
+	query := db.WithContext(ctx).Preload("LeaveType").Where("agency_id = ?", agencyID)
+
+	if filter.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.LeaveTypeID != uuid.Nil {
+		query = query.Where("leave_type_id = ?", filter.LeaveTypeID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("end_date >= ?", filter.StartDate.Format("2006-01-02"))
+	}
+	if filter.EndDate != nil {
+		query = query.Where("start_date <= ?", filter.EndDate.Format("2006-01-02"))
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("start_date DESC").Find(&leaves).Error; err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+func (r *LeaveRequestRepo) Update(ctx context.Context, leave *domain.LeaveRequest) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Omit("User", "LeaveType").Save(leave).Error
+}
+
+func (r *LeaveRequestRepo) ListActiveOverlapping(ctx context.Context, userID uuid.UUID, start time.Time, end time.Time) ([]*domain.LeaveRequest, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var leaves []*domain.LeaveRequest
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND status IN ?", userID, []domain.LeaveStatus{domain.LeavePending, domain.LeaveApproved}).
+		Where("start_date <= ? AND end_date >= ?", end.Format("2006-01-02"), start.Format("2006-01-02")).
+		Find(&leaves).Error
+	if err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+func (r *LeaveRequestRepo) ListApprovedForDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) ([]*domain.LeaveRequest, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	day := date.Format("2006-01-02")
+
+	var leaves []*domain.LeaveRequest
+	err := db.WithContext(ctx).Preload("LeaveType").
+		Where("agency_id = ? AND user_id = ? AND status = ?", agencyID, userID, domain.LeaveApproved).
+		Where("start_date <= ? AND end_date >= ?", day, day).
+		Order("half_day ASC").
+		Find(&leaves).Error
+	if err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
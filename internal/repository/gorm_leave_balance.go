@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LeaveBalanceRepo struct {
+	db *gorm.DB
+}
+
+func NewLeaveBalanceRepo(db *gorm.DB) *LeaveBalanceRepo {
+	return &LeaveBalanceRepo{db: db}
+}
+
+func (r *LeaveBalanceRepo) Create(ctx context.Context, entry *domain.LeaveBalanceEntry) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *LeaveBalanceRepo) CreateBatch(ctx context.Context, entries []*domain.LeaveBalanceEntry) (int64, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	// El índice parcial de los movimientos automáticos descarta los que otra ejecución ya generó
+	res := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(entries, 100)
+	return res.RowsAffected, res.Error
+}
+
+func (r *LeaveBalanceRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.LeaveBalanceFilter) ([]*domain.LeaveBalanceEntry, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var entries []*domain.LeaveBalanceEntry
+	query := db.WithContext(ctx).Where("agency_id = ?", agencyID)
+
+	if filter.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.LeaveTypeID != uuid.Nil {
+		query = query.Where("leave_type_id = ?", filter.LeaveTypeID)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("date DESC, created_at DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *LeaveBalanceRepo) ListByUserAndType(ctx context.Context, userID uuid.UUID, leaveTypeID uuid.UUID) ([]*domain.LeaveBalanceEntry, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var entries []*domain.LeaveBalanceEntry
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND leave_type_id = ?", userID, leaveTypeID).
+		Order("date ASC, created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *LeaveBalanceRepo) LockByUserAndType(ctx context.Context, userID uuid.UUID, leaveTypeID uuid.UUID) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var ids []uuid.UUID
+	return db.WithContext(ctx).
+		Model(&domain.LeaveBalanceEntry{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND leave_type_id = ?", userID, leaveTypeID).
+		Pluck("id", &ids).Error
+}
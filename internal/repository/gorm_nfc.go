@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"quickattendance-go/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NFCTagRepo struct {
+	db *gorm.DB
+}
+
+func NewNFCTagRepo(db *gorm.DB) *NFCTagRepo {
+	return &NFCTagRepo{db: db}
+}
+
+func (r *NFCTagRepo) Create(ctx context.Context, tag *domain.NFCTag) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).Omit("Site").Create(tag).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrNFCTagExists
+	}
+	return err
+}
+
+func (r *NFCTagRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.NFCTag, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var tag domain.NFCTag
+	if err := db.WithContext(ctx).Preload("Site").First(&tag, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrNFCTagNotFound
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *NFCTagRepo) GetByUID(ctx context.Context, agencyID uuid.UUID, uid string) (*domain.NFCTag, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var tag domain.NFCTag
+	if err := db.WithContext(ctx).Preload("Site").Where("agency_id = ? AND uid = ?", agencyID, uid).First(&tag).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrNFCTagNotFound
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *NFCTagRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.NFCTagFilter) ([]*domain.NFCTag, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var tags []*domain.NFCTag
+	query := db.WithContext(ctx).Preload("Site").Where("agency_id = ?", agencyID)
+
+	if filter.Active != nil {
+		query = query.Where("active = ?", *filter.Active)
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("label ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *NFCTagRepo) Update(ctx context.Context, tag *domain.NFCTag) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Omit("Site").Save(tag).Error
+}
+
+func (r *NFCTagRepo) ListUsage(ctx context.Context, agencyID uuid.UUID, filter domain.NFCTagUsageFilter) ([]*domain.NFCTagUsage, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var usage []*domain.NFCTagUsage
+	query := db.WithContext(ctx).
+		Table("attendance_punches AS p").
+		Select("p.id AS punch_id, p.attendance_id, a.user_id, p.nfc_tag_id, p.site_id, p.site, p.type, p.time").
+		Joins("JOIN attendances AS a ON a.id = p.attendance_id").
+		Where("a.agency_id = ? AND a.voided_at IS NULL AND p.nfc_tag_id IS NOT NULL", agencyID)
+
+	if filter.TagID != uuid.Nil {
+		query = query.Where("p.nfc_tag_id = ?", filter.TagID)
+	}
+	if filter.UserID != uuid.Nil {
+		query = query.Where("a.user_id = ?", filter.UserID)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("p.time >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("p.time < ?", *filter.EndDate)
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("p.time DESC").Scan(&usage).Error; err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
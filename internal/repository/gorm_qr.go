@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type QRTokenUseRepo struct {
+	db *gorm.DB
+}
+
+func NewQRTokenUseRepo(db *gorm.DB) *QRTokenUseRepo {
+	return &QRTokenUseRepo{db: db}
+}
+
+func (r *QRTokenUseRepo) Create(ctx context.Context, use *domain.QRTokenUse) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(use).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domain.ErrQRTokenReplayed
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *QRTokenUseRepo) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	res := db.WithContext(ctx).Where("expires_at < ?", before).Delete(&domain.QRTokenUse{})
+	return res.RowsAffected, res.Error
+}
@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"quickattendance-go/internal/domain"
-	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -58,6 +57,9 @@ func (r *ScheduleRepo) GetByAgencyID(ctx context.Context, agencyID uuid.UUID, fi
 	if filter.IsDefault != nil {
 		query = query.Where("is_default = ?", *filter.IsDefault)
 	}
+	if filter.ActiveFrom != nil {
+		query = query.Where("(effective_to IS NULL OR effective_to >= ?)", filter.ActiveFrom.Format("2006-01-02"))
+	}
 
 	// Pagination
 	if filter.Limit > 0 {
@@ -78,8 +80,9 @@ func (r *ScheduleRepo) GetByName(ctx context.Context, agencyID uuid.UUID, name s
 		db = r.db
 	}
 
+	// Solo la última versión de cada serie; las series dadas de baja tienen todas sus versiones cerradas
 	var schedules []*domain.Schedule
-	if err := db.WithContext(ctx).Where("agency_id = ? AND name = ?", agencyID, name).Find(&schedules).Error; err != nil {
+	if err := db.WithContext(ctx).Where("agency_id = ? AND name = ? AND effective_to IS NULL", agencyID, name).Find(&schedules).Error; err != nil {
 		return nil, err
 	}
 
@@ -93,11 +96,10 @@ func (r *ScheduleRepo) GetByDate(ctx context.Context, agencyID uuid.UUID, date t
 		db = r.db
 	}
 
-	weekday := strconv.Itoa(int(date.Weekday()))
-
 	var schedules []*domain.Schedule
 	err := db.WithContext(ctx).
-		Where("agency_id = ? AND days_of_week LIKE ?", agencyID, "%"+weekday+"%").
+		Scopes(effectiveOn(date), onWeekday(date)).
+		Where("agency_id = ?", agencyID).
 		Find(&schedules).Error
 
 	if err != nil {
@@ -122,10 +124,11 @@ func (r *ScheduleRepo) Delete(ctx context.Context, id uuid.UUID) error {
 		db = r.db
 	}
 
-	return db.WithContext(ctx).Delete(&domain.Schedule{}, id).Error
+	return db.WithContext(ctx).Select("AssignedUsers").Delete(&domain.Schedule{ID: id}).Error
 }
 
-func (r *ScheduleRepo) GetDefault(ctx context.Context, agencyID uuid.UUID) (*domain.Schedule, error) {
+// GetDefault devuelve la versión del horario por defecto vigente en date
+func (r *ScheduleRepo) GetDefault(ctx context.Context, agencyID uuid.UUID, date time.Time) (*domain.Schedule, error) {
 	db, ok := ctx.Value("tx").(*gorm.DB)
 	if !ok {
 		db = r.db
@@ -133,6 +136,7 @@ func (r *ScheduleRepo) GetDefault(ctx context.Context, agencyID uuid.UUID) (*dom
 
 	var schedules []domain.Schedule
 	err := db.WithContext(ctx).
+		Scopes(effectiveOn(date)).
 		Where("agency_id = ? AND is_default = ?", agencyID, true).
 		Limit(1).
 		Find(&schedules).Error
@@ -148,7 +152,8 @@ func (r *ScheduleRepo) GetDefault(ctx context.Context, agencyID uuid.UUID) (*dom
 	return &schedules[0], nil
 }
 
-func (r *ScheduleRepo) GetUserScheduleByDay(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, weekday string) (*domain.Schedule, error) {
+// GetUserScheduleByDay busca la versión vigente en date de un horario asignado al usuario que incluya ese día de la semana
+func (r *ScheduleRepo) GetUserScheduleByDay(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*domain.Schedule, error) {
 	db, ok := ctx.Value("tx").(*gorm.DB)
 	if !ok {
 		db = r.db
@@ -156,9 +161,11 @@ func (r *ScheduleRepo) GetUserScheduleByDay(ctx context.Context, agencyID uuid.U
 
 	var schedule domain.Schedule
 	err := db.WithContext(ctx).
+		Scopes(effectiveOn(date), onWeekday(date)).
 		Joins("JOIN schedule_users ON schedule_users.schedule_id = schedules.id").
-		Where("schedules.agency_id = ? AND schedule_users.user_id = ? AND schedules.days_of_week LIKE ?",
-			agencyID, userID, "%"+weekday+"%").
+		Where("schedules.agency_id = ? AND schedule_users.user_id = ?", agencyID, userID).
+		// Las asignaciones cruzadas se rechazan al guardar; si quedó alguna de antes, gana el horario más antiguo
+		Order("schedules.created_at ASC").
 		First(&schedule).Error
 
 	if err != nil {
@@ -166,3 +173,61 @@ func (r *ScheduleRepo) GetUserScheduleByDay(ctx context.Context, agencyID uuid.U
 	}
 	return &schedule, nil
 }
+
+// GetVersions devuelve todas las versiones de una serie, de la más antigua a la más nueva
+func (r *ScheduleRepo) GetVersions(ctx context.Context, seriesID uuid.UUID) ([]*domain.Schedule, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var schedules []*domain.Schedule
+	err := db.WithContext(ctx).
+		Preload("AssignedUsers").
+		Where("series_id = ?", seriesID).
+		Order("version ASC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *ScheduleRepo) GetAssigned(ctx context.Context, agencyID uuid.UUID, userIDs []uuid.UUID, from time.Time) ([]*domain.Schedule, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx).
+		Preload("AssignedUsers").
+		Where("agency_id = ? AND (effective_to IS NULL OR effective_to >= ?)", agencyID, from.Format("2006-01-02"))
+
+	if len(userIDs) > 0 {
+		query = query.Where("id IN (SELECT schedule_id FROM schedule_users WHERE user_id IN ?)", userIDs)
+	} else {
+		query = query.Where("id IN (SELECT schedule_id FROM schedule_users)")
+	}
+
+	var schedules []*domain.Schedule
+	if err := query.Order("created_at ASC").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// effectiveOn filtra las versiones de horario vigentes en date
+func effectiveOn(date time.Time) func(db *gorm.DB) *gorm.DB {
+	day := date.Format("2006-01-02")
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(schedules.effective_from IS NULL OR schedules.effective_from <= ?) AND (schedules.effective_to IS NULL OR schedules.effective_to >= ?)", day, day)
+	}
+}
+
+// onWeekday filtra los horarios que incluyen el día de la semana de date en su máscara de días
+func onWeekday(date time.Time) func(db *gorm.DB) *gorm.DB {
+	bit := 1 << int(date.Weekday())
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("schedules.weekdays & ? <> 0", bit)
+	}
+}
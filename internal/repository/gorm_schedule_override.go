@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduleOverrideRepo struct {
+	db *gorm.DB
+}
+
+func NewScheduleOverrideRepo(db *gorm.DB) *ScheduleOverrideRepo {
+	return &ScheduleOverrideRepo{db: db}
+}
+
+func (r *ScheduleOverrideRepo) Create(ctx context.Context, override *domain.ScheduleOverride) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Create(override).Error
+}
+
+func (r *ScheduleOverrideRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScheduleOverride, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var override domain.ScheduleOverride
+	if err := db.WithContext(ctx).Preload("Users").First(&override, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrScheduleOverrideNotFound
+		}
+		return nil, err
+	}
+	return &override, nil
+}
+
+func (r *ScheduleOverrideRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.ScheduleOverrideFilter) ([]*domain.ScheduleOverride, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var overrides []*domain.ScheduleOverride
+	query := db.WithContext(ctx).Preload("Users").Where("agency_id = ?", agencyID)
+
+	if filter.UserID != uuid.Nil {
+		query = query.Where(`NOT EXISTS (SELECT 1 FROM schedule_override_users sou WHERE sou.schedule_override_id = schedule_overrides.id)
+			OR EXISTS (SELECT 1 FROM schedule_override_users sou WHERE sou.schedule_override_id = schedule_overrides.id AND sou.user_id = ?)`, filter.UserID)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("end_date >= ?", filter.StartDate.Format("2006-01-02"))
+	}
+	if filter.EndDate != nil {
+		query = query.Where("start_date <= ?", filter.EndDate.Format("2006-01-02"))
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("start_date ASC").Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+func (r *ScheduleOverrideRepo) FindForDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*domain.ScheduleOverride, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	day := date.Format("2006-01-02")
+
+	var overrides []domain.ScheduleOverride
+	err := db.WithContext(ctx).
+		Preload("Users").
+		Select("schedule_overrides.*").
+		Joins("LEFT JOIN schedule_override_users sou ON sou.schedule_override_id = schedule_overrides.id AND sou.user_id = ?", userID).
+		Where("schedule_overrides.agency_id = ? AND start_date <= ? AND end_date >= ?", agencyID, day, day).
+		Where(`sou.user_id IS NOT NULL
+			OR NOT EXISTS (SELECT 1 FROM schedule_override_users x WHERE x.schedule_override_id = schedule_overrides.id)`).
+		// Primero las del usuario, luego la de rango más corto y la más reciente
+		Order("sou.user_id IS NULL, end_date - start_date, schedule_overrides.created_at DESC").
+		Limit(1).
+		Find(&overrides).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+	return &overrides[0], nil
+}
+
+func (r *ScheduleOverrideRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	return db.WithContext(ctx).Select("Users").Delete(&domain.ScheduleOverride{ID: id}).Error
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShiftRotationRepo struct {
+	db *gorm.DB
+}
+
+func NewShiftRotationRepo(db *gorm.DB) *ShiftRotationRepo {
+	return &ShiftRotationRepo{db: db}
+}
+
+func (r *ShiftRotationRepo) Create(ctx context.Context, rotation *domain.ShiftRotation) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Create(rotation).Error
+}
+
+func (r *ShiftRotationRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ShiftRotation, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var rotation domain.ShiftRotation
+	if err := db.WithContext(ctx).Preload("Shifts", orderShifts).First(&rotation, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrShiftRotationNotFound
+		}
+		return nil, err
+	}
+	return &rotation, nil
+}
+
+func (r *ShiftRotationRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.ShiftRotationFilter) ([]*domain.ShiftRotation, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var rotations []*domain.ShiftRotation
+	query := db.WithContext(ctx).Preload("Shifts", orderShifts).Where("agency_id = ?", agencyID)
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("name ASC").Find(&rotations).Error; err != nil {
+		return nil, err
+	}
+	return rotations, nil
+}
+
+func (r *ShiftRotationRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	return db.WithContext(ctx).Select("Shifts").Delete(&domain.ShiftRotation{ID: id}).Error
+}
+
+func (r *ShiftRotationRepo) CreateAssignment(ctx context.Context, assignment *domain.RotationAssignment) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Omit("Rotation").Create(assignment).Error
+}
+
+func (r *ShiftRotationRepo) GetAssignment(ctx context.Context, id uuid.UUID) (*domain.RotationAssignment, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var assignment domain.RotationAssignment
+	if err := db.WithContext(ctx).First(&assignment, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrRotationAssignmentNotFound
+		}
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *ShiftRotationRepo) ListAssignments(ctx context.Context, rotationID uuid.UUID) ([]*domain.RotationAssignment, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var assignments []*domain.RotationAssignment
+	err := db.WithContext(ctx).
+		Where("rotation_id = ?", rotationID).
+		Order("start_date ASC").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+func (r *ShiftRotationRepo) ListUserAssignments(ctx context.Context, userID uuid.UUID, from time.Time) ([]*domain.RotationAssignment, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var assignments []*domain.RotationAssignment
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND (end_date IS NULL OR end_date >= ?)", userID, from.Format("2006-01-02")).
+		Order("start_date ASC").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+func (r *ShiftRotationRepo) CountAssignments(ctx context.Context, rotationID uuid.UUID) (int64, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var count int64
+	err := db.WithContext(ctx).Model(&domain.RotationAssignment{}).Where("rotation_id = ?", rotationID).Count(&count).Error
+	return count, err
+}
+
+func (r *ShiftRotationRepo) UpdateAssignment(ctx context.Context, assignment *domain.RotationAssignment) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Omit("Rotation").Save(assignment).Error
+}
+
+func (r *ShiftRotationRepo) DeleteAssignment(ctx context.Context, id uuid.UUID) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Delete(&domain.RotationAssignment{}, id).Error
+}
+
+func (r *ShiftRotationRepo) FindAssignment(ctx context.Context, userID uuid.UUID, date time.Time) (*domain.RotationAssignment, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	day := date.Format("2006-01-02")
+
+	var assignments []domain.RotationAssignment
+	err := db.WithContext(ctx).
+		Preload("Rotation.Shifts", orderShifts).
+		Where("user_id = ? AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)", userID, day, day).
+		Order("start_date DESC").
+		Limit(1).
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+	return &assignments[0], nil
+}
+
+// orderShifts carga los turnos de una rotación en el orden del ciclo
+func orderShifts(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
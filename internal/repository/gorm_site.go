@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"quickattendance-go/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SiteRepo struct {
+	db *gorm.DB
+}
+
+func NewSiteRepo(db *gorm.DB) *SiteRepo {
+	return &SiteRepo{db: db}
+}
+
+func (r *SiteRepo) Create(ctx context.Context, site *domain.Site) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).Create(site).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrSiteExists
+	}
+	return err
+}
+
+func (r *SiteRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Site, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var site domain.Site
+	if err := db.WithContext(ctx).First(&site, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrSiteNotFound
+		}
+		return nil, err
+	}
+	return &site, nil
+}
+
+func (r *SiteRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.SiteFilter) ([]*domain.Site, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var sites []*domain.Site
+	query := db.WithContext(ctx).Where("agency_id = ?", agencyID)
+
+	if filter.Active != nil {
+		query = query.Where("active = ?", *filter.Active)
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("name ASC").Find(&sites).Error; err != nil {
+		return nil, err
+	}
+	return sites, nil
+}
+
+func (r *SiteRepo) Update(ctx context.Context, site *domain.Site) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).Save(site).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrSiteExists
+	}
+	return err
+}
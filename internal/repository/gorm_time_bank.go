@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TimeBankRepo struct {
+	db *gorm.DB
+}
+
+func NewTimeBankRepo(db *gorm.DB) *TimeBankRepo {
+	return &TimeBankRepo{db: db}
+}
+
+func (r *TimeBankRepo) Create(ctx context.Context, entry *domain.TimeBankEntry) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *TimeBankRepo) CreateBatch(ctx context.Context, entries []*domain.TimeBankEntry) (int64, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	// El índice único de expired_entry_id descarta los vencimientos que otra ejecución ya registró
+	res := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(entries, 100)
+	return res.RowsAffected, res.Error
+}
+
+func (r *TimeBankRepo) List(ctx context.Context, agencyID uuid.UUID, filter domain.TimeBankFilter) ([]*domain.TimeBankEntry, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var entries []*domain.TimeBankEntry
+	query := db.WithContext(ctx).Where("agency_id = ?", agencyID)
+
+	if filter.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("date >= ?", filter.StartDate.Format("2006-01-02"))
+	}
+	if filter.EndDate != nil {
+		query = query.Where("date <= ?", filter.EndDate.Format("2006-01-02"))
+	}
+
+	// Pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	if err := query.Order("date DESC, created_at DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *TimeBankRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.TimeBankEntry, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var entries []*domain.TimeBankEntry
+	err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("date ASC, created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *TimeBankRepo) ListByAttendanceID(ctx context.Context, attendanceID uuid.UUID) ([]*domain.TimeBankEntry, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var entries []*domain.TimeBankEntry
+	if err := db.WithContext(ctx).Where("attendance_id = ?", attendanceID).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *TimeBankRepo) ListUserIDs(ctx context.Context, agencyID uuid.UUID) ([]uuid.UUID, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var ids []uuid.UUID
+	err := db.WithContext(ctx).Model(&domain.TimeBankEntry{}).
+		Where("agency_id = ?", agencyID).
+		Distinct().Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *TimeBankRepo) LockByUser(ctx context.Context, userID uuid.UUID) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var ids []uuid.UUID
+	return db.WithContext(ctx).
+		Model(&domain.TimeBankEntry{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		Pluck("id", &ids).Error
+}
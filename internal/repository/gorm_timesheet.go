@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TimesheetRepo struct {
+	db *gorm.DB
+}
+
+func NewTimesheetRepo(db *gorm.DB) *TimesheetRepo {
+	return &TimesheetRepo{db: db}
+}
+
+func (r *TimesheetRepo) Create(ctx context.Context, timesheet *domain.Timesheet) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Create(timesheet).Error
+}
+
+func (r *TimesheetRepo) Update(ctx context.Context, timesheet *domain.Timesheet) error {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+	return db.WithContext(ctx).Save(timesheet).Error
+}
+
+func (r *TimesheetRepo) GetByUserAndPeriod(ctx context.Context, userID uuid.UUID, periodStart time.Time) (*domain.Timesheet, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var timesheets []domain.Timesheet
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND period_start = ?", userID, periodStart.Format("2006-01-02")).
+		Limit(1).
+		Find(&timesheets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(timesheets) == 0 {
+		return nil, nil
+	}
+	return &timesheets[0], nil
+}
+
+func (r *TimesheetRepo) GetByUserAndPeriodForUpdate(ctx context.Context, userID uuid.UUID, periodStart time.Time) (*domain.Timesheet, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	var timesheets []domain.Timesheet
+	err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND period_start = ?", userID, periodStart.Format("2006-01-02")).
+		Limit(1).
+		Find(&timesheets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(timesheets) == 0 {
+		return nil, nil
+	}
+	return &timesheets[0], nil
+}
+
+func (r *TimesheetRepo) GetForDateForUpdate(ctx context.Context, userID uuid.UUID, date time.Time) (*domain.Timesheet, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	day := date.Format("2006-01-02")
+
+	var timesheets []domain.Timesheet
+	err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND period_start <= ? AND period_end >= ?", userID, day, day).
+		Limit(1).
+		Find(&timesheets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(timesheets) == 0 {
+		return nil, nil
+	}
+	return &timesheets[0], nil
+}
+
+func (r *TimesheetRepo) ListByPeriod(ctx context.Context, agencyID uuid.UUID, periodStart time.Time, userIDs []uuid.UUID) ([]*domain.Timesheet, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var timesheets []*domain.Timesheet
+	err := db.WithContext(ctx).
+		Where("agency_id = ? AND period_start = ? AND user_id IN ?", agencyID, periodStart.Format("2006-01-02"), userIDs).
+		Find(&timesheets).Error
+	if err != nil {
+		return nil, err
+	}
+	return timesheets, nil
+}
+
+func (r *TimesheetRepo) IsLocked(ctx context.Context, userID uuid.UUID, date time.Time) (bool, error) {
+	db, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		db = r.db
+	}
+
+	day := date.Format("2006-01-02")
+
+	var count int64
+	err := db.WithContext(ctx).Model(&domain.Timesheet{}).
+		Where("user_id = ? AND status = ?", userID, domain.TimesheetLocked).
+		Where("period_start <= ? AND period_end >= ?", day, day).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
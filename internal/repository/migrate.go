@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+	"quickattendance-go/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Migrate aplica los cambios de esquema que AutoMigrate no sabe resolver solo
+// (renombres, conversiones de datos) y luego ejecuta AutoMigrate sobre todos los modelos.
+// serverTimeZone es la zona IANA del servidor, con la que se evaluaban las agencias anteriores a time_zone.
+func Migrate(db *gorm.DB, serverTimeZone string) error {
+	m := db.Migrator()
+
+	// attendances.status se separó en entry_status / exit_status
+	if m.HasColumn(&domain.Attendance{}, "status") && !m.HasColumn(&domain.Attendance{}, "entry_status") {
+		if err := m.RenameColumn(&domain.Attendance{}, "status", "entry_status"); err != nil {
+			return err
+		}
+	}
+
+	backfillPunches := !m.HasTable(&domain.AttendancePunch{})
+	backfillTotals := !m.HasColumn(&domain.Attendance{}, "scheduled_minutes")
+	backfillSeries := !m.HasColumn(&domain.Schedule{}, "series_id")
+	backfillWeekdays := m.HasColumn(&domain.Schedule{}, "days_of_week")
+	backfillTimeZone := m.HasTable(&domain.Agency{}) && !m.HasColumn(&domain.Agency{}, "time_zone")
+	backfillTagSites := m.HasColumn(&domain.NFCTag{}, "site") && !m.HasColumn(&domain.NFCTag{}, "site_id")
+
+	// Sin la zona del servidor las agencias existentes pasarían a UTC y cambiarían sus fechas y atrasos
+	if backfillTimeZone && !domain.ValidTimeZone(serverTimeZone) {
+		var count int64
+		if err := db.Model(&domain.Agency{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("cannot add agencies.time_zone: server time zone %q is not an IANA name, set TZ (e.g. TZ=America/Santiago)", serverTimeZone)
+		}
+		backfillTimeZone = false
+	}
+
+	// El índice único (user_id, date) pasa a ignorar los registros anulados; AutoMigrate no modifica
+	// un índice que ya existe, así que se elimina para que lo recree con la condición
+	if !m.HasColumn(&domain.Attendance{}, "voided_at") && m.HasIndex(&domain.Attendance{}, "idx_attendance_user_date") {
+		if err := m.DropIndex(&domain.Attendance{}, "idx_attendance_user_date"); err != nil {
+			return err
+		}
+	}
+
+	if err := db.AutoMigrate(
+		&domain.Agency{},
+		&domain.User{},
+		&domain.Schedule{},
+		&domain.ScheduleOverride{},
+		&domain.Attendance{},
+		&domain.AttendancePunch{},
+		&domain.AttendanceCorrection{},
+		&domain.AttendanceRevision{},
+		&domain.QRTokenUse{},
+		&domain.NFCTag{},
+		&domain.Site{},
+		&domain.Holiday{},
+		&domain.ShiftRotation{},
+		&domain.RotationShift{},
+		&domain.RotationAssignment{},
+		&domain.LeaveType{},
+		&domain.LeaveRequest{},
+		&domain.LeaveBalanceEntry{},
+		&domain.TimeBankEntry{},
+		&domain.Timesheet{},
+	); err != nil {
+		return err
+	}
+
+	// Las asistencias anteriores al modelo de marcas solo tenían entrada/salida en la fila diaria
+	if backfillPunches {
+		if err := db.Exec(`
+			INSERT INTO attendance_punches (id, attendance_id, type, time, method, latitude, longitude, created_at)
+			SELECT gen_random_uuid(), id, 'in', check_in_time, method_in, latitude, longitude, NOW()
+			FROM attendances WHERE check_in_time IS NOT NULL`).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(`
+			INSERT INTO attendance_punches (id, attendance_id, type, time, method, created_at)
+			SELECT gen_random_uuid(), id, 'out', check_out_time, COALESCE(method_out, method_in), NOW()
+			FROM attendances WHERE check_in_time IS NOT NULL AND check_out_time IS NOT NULL`).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(`
+			UPDATE attendances SET worked_minutes = FLOOR(EXTRACT(EPOCH FROM (check_out_time - check_in_time)) / 60)
+			WHERE check_in_time IS NOT NULL AND check_out_time IS NOT NULL`).Error; err != nil {
+			return err
+		}
+	}
+
+	// Registros cerrados antes de persistir los totales: se calculan sin reglas de redondeo
+	if backfillTotals {
+		if err := db.Exec(`
+			UPDATE attendances SET scheduled_minutes = FLOOR(EXTRACT(EPOCH FROM (schedule_exit_time - schedule_entry_time)) / 60)
+			WHERE check_out_time IS NOT NULL`).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(`
+			UPDATE attendances SET
+				overtime_minutes = GREATEST(worked_minutes - scheduled_minutes, 0),
+				deficit_minutes = GREATEST(scheduled_minutes - worked_minutes, 0)
+			WHERE check_out_time IS NOT NULL`).Error; err != nil {
+			return err
+		}
+	}
+
+	// Los horarios anteriores al versionado pasan a ser la primera versión de su propia serie
+	if backfillSeries {
+		if err := db.Exec(`UPDATE schedules SET series_id = id WHERE series_id IS NULL`).Error; err != nil {
+			return err
+		}
+	}
+
+	// schedules.days_of_week ("1,2,3") se reemplazó por la máscara de bits schedules.weekdays
+	if backfillWeekdays {
+		if err := db.Exec(`
+			UPDATE schedules SET weekdays = (
+				SELECT COALESCE(SUM(DISTINCT 1 << btrim(d)::int), 0)
+				FROM unnest(string_to_array(days_of_week, ',')) AS d
+				WHERE btrim(d) ~ '^[0-6]$'
+			)`).Error; err != nil {
+			return err
+		}
+		if err := m.DropColumn(&domain.Schedule{}, "days_of_week"); err != nil {
+			return err
+		}
+	}
+
+	// nfc_tags.site (texto libre) se reemplazó por nfc_tags.site_id: se enlaza con la sede de igual nombre.
+	// La columna anterior solo se elimina si todas las etiquetas encontraron su sede.
+	if backfillTagSites {
+		if err := db.Exec(`
+			UPDATE nfc_tags AS t SET site_id = s.id
+			FROM sites AS s
+			WHERE s.agency_id = t.agency_id AND lower(s.name) = lower(btrim(t.site))`).Error; err != nil {
+			return err
+		}
+
+		var unmatched int64
+		if err := db.Table("nfc_tags").Where("site_id IS NULL AND btrim(COALESCE(site, '')) <> ''").Count(&unmatched).Error; err != nil {
+			return err
+		}
+		if unmatched > 0 {
+			slog.Warn("NFC tags whose site name matches no site keep it in nfc_tags.site; assign their site_id", "tags", unmatched)
+		} else if err := m.DropColumn(&domain.NFCTag{}, "site"); err != nil {
+			return err
+		}
+	}
+
+	// Las agencias existentes conservan la hora local del servidor con que se evaluaban hasta ahora
+	if backfillTimeZone {
+		if err := db.Exec(`UPDATE agencies SET time_zone = ?`, serverTimeZone).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
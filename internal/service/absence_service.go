@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"quickattendance-go/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AbsenceService struct {
+	agencyRepo     domain.AgencyRepo
+	userRepo       domain.UserRepo
+	attendanceRepo domain.AttendanceRepo
+	timesheetRepo  domain.TimesheetRepo
+	scheduleSvc    *ScheduleService
+}
+
+func NewAbsenceService(
+	agencyRepo domain.AgencyRepo,
+	userRepo domain.UserRepo,
+	attendanceRepo domain.AttendanceRepo,
+	timesheetRepo domain.TimesheetRepo,
+	scheduleSvc *ScheduleService,
+) *AbsenceService {
+	return &AbsenceService{
+		agencyRepo:     agencyRepo,
+		userRepo:       userRepo,
+		attendanceRepo: attendanceRepo,
+		timesheetRepo:  timesheetRepo,
+		scheduleSvc:    scheduleSvc,
+	}
+}
+
+// DetectRecentAbsences revisa ayer y hoy en la zona horaria de cada agencia: los turnos
+// de ayer pueden terminar después de la última ejecución del día anterior (o al día siguiente, si son nocturnos).
+func (s *AbsenceService) DetectRecentAbsences(ctx context.Context, now time.Time) (int, error) {
+	agencies, err := s.agencyRepo.ListActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, agency := range agencies {
+		today := startOfDay(now.In(agency.Location()))
+		for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+			n, err := s.detectAgencyAbsences(ctx, agency.ID, day, now)
+			created += n
+			if err != nil {
+				return created, err
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// BackfillAbsences registra las ausencias de cada día del rango [from, to], ambos inclusive.
+// from y to se toman como días calendario y se evalúan en la zona horaria de cada agencia.
+// Es idempotente: si ya existe un registro para el usuario en esa fecha no se crea otro.
+func (s *AbsenceService) BackfillAbsences(ctx context.Context, from time.Time, to time.Time, now time.Time) (int, error) {
+	if to.Before(from) {
+		return 0, domain.ErrInvalidDateRange
+	}
+
+	agencies, err := s.agencyRepo.ListActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, agency := range agencies {
+		loc := agency.Location()
+		last := dateIn(to, loc)
+		for day := dateIn(from, loc); !day.After(last); day = day.AddDate(0, 0, 1) {
+			n, err := s.detectAgencyAbsences(ctx, agency.ID, day, now)
+			created += n
+			if err != nil {
+				return created, err
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// detectAgencyAbsences registra una ausencia para cada usuario activo que tenía horario
+// en date (medianoche en la zona de la agencia), cuya hora de salida ya pasó respecto a now
+// y que no marcó entrada.
+func (s *AbsenceService) detectAgencyAbsences(ctx context.Context, agencyID uuid.UUID, date time.Time, now time.Time) (int, error) {
+	users, err := s.userRepo.ListByAgencyID(ctx, agencyID, domain.UserFilter{Status: string(domain.StatusActive)})
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, user := range users {
+		sched, err := s.scheduleSvc.GetApplicableSchedule(ctx, agencyID, user.ID, date)
+		if err != nil {
+			// Sin horario o feriado: no se esperaba que trabajara
+			if err == domain.ErrNoScheduleFound || errors.Is(err, domain.ErrNonWorkingDay) {
+				continue
+			}
+			return created, err
+		}
+
+		entryTime, exitTime := shiftBounds(date, sched.EntryTimeMinutes, sched.ExitTimeMinutes)
+
+		// El turno aún no termina, o el usuario no existía cuando empezó
+		if now.Before(exitTime) || user.CreatedAt.After(entryTime) {
+			continue
+		}
+
+		// Un registro anulado por un admin tampoco debe reemplazarse por una ausencia
+		exists, err := s.attendanceRepo.ExistsByUserAndDate(ctx, agencyID, user.ID, date)
+		if err != nil {
+			return created, err
+		}
+		if exists {
+			continue
+		}
+
+		// Un backfill no debe agregar ausencias a una planilla ya cerrada
+		locked, err := s.timesheetRepo.IsLocked(ctx, user.ID, date)
+		if err != nil {
+			return created, err
+		}
+		if locked {
+			continue
+		}
+
+		absent := domain.StatusAbsent
+		absence := &domain.Attendance{
+			UserID:      user.ID,
+			AgencyID:    agencyID,
+			Date:        date,
+			EntryStatus: domain.StatusAbsent,
+			ExitStatus:  &absent,
+			MethodIn:    domain.MethodSystem,
+		}
+		setSchedule(absence, date, sched)
+
+		if err := s.attendanceRepo.Create(ctx, absence); err != nil {
+			// Otra ejecución del job pudo haberlo creado entre la consulta y el insert
+			if err == domain.ErrAttendanceExists {
+				continue
+			}
+			return created, err
+		}
+
+		slog.Info("Absence registered", "agency_id", agencyID, "user_id", user.ID, "date", date.Format("2006-01-02"))
+		created++
+	}
+
+	return created, nil
+}
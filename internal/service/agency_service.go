@@ -26,6 +26,13 @@ func NewAgencyService(agencyRepo domain.AgencyRepo, userRepo domain.UserRepo, ha
 }
 
 func (s *AgencyService) Register(ctx context.Context, req *dto.RegisterAgencyRequest) (*dto.AgencyResponse, error) {
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	if !domain.ValidTimeZone(req.TimeZone) {
+		return nil, domain.ErrInvalidTimeZone
+	}
+
 	// 1. Validaciones previas de existencia
 	if _, err := s.agencyRepo.GetByName(ctx, req.Name); err == nil {
 		return nil, domain.ErrAgencyExists
@@ -40,10 +47,11 @@ func (s *AgencyService) Register(ctx context.Context, req *dto.RegisterAgencyReq
 	var agency *domain.Agency
 	err := s.txManager.WithinTransaction(ctx, func(tCtx context.Context) error {
 		agency = &domain.Agency{
-			Name:    req.Name,
-			Domain:  req.Domain,
-			Address: req.Address,
-			Phone:   req.Phone,
+			Name:     req.Name,
+			Domain:   req.Domain,
+			Address:  req.Address,
+			Phone:    req.Phone,
+			TimeZone: req.TimeZone,
 		}
 		if err := s.agencyRepo.Create(tCtx, agency); err != nil {
 			return err
@@ -89,6 +97,36 @@ func (s *AgencyService) Update(ctx context.Context, id uuid.UUID, req *dto.Updat
 	if req.Phone != nil {
 		agency.Phone = *req.Phone
 	}
+	if req.TimeZone != nil {
+		if !domain.ValidTimeZone(*req.TimeZone) {
+			return nil, domain.ErrInvalidTimeZone
+		}
+		agency.TimeZone = *req.TimeZone
+	}
+	if req.WorkRoundingMinutes != nil {
+		agency.WorkRoundingMinutes = *req.WorkRoundingMinutes
+	}
+	if req.WorkRoundingMode != nil {
+		agency.WorkRoundingMode = *req.WorkRoundingMode
+	}
+	if req.MinOvertimeMinutes != nil {
+		agency.MinOvertimeMinutes = *req.MinOvertimeMinutes
+	}
+	if req.RequireOnSiteLocation != nil {
+		agency.RequireOnSiteLocation = *req.RequireOnSiteLocation
+	}
+	if req.MaxLocationAccuracyMeters != nil {
+		agency.MaxLocationAccuracyMeters = *req.MaxLocationAccuracyMeters
+	}
+	if req.GeofenceStrictness != nil {
+		agency.GeofenceStrictness = *req.GeofenceStrictness
+	}
+	if req.TimeBankEnabled != nil {
+		agency.TimeBankEnabled = *req.TimeBankEnabled
+	}
+	if req.TimeBankExpiryDays != nil {
+		agency.TimeBankExpiryDays = *req.TimeBankExpiryDays
+	}
 
 	if err := s.agencyRepo.Update(ctx, agency); err != nil {
 		return nil, err
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Operaciones de administración sobre registros de asistencia ya existentes o de días pasados.
+// A diferencia de MarkAttendance no usan la hora actual: las marcas se ingresan con la hora indicada,
+// los estados se recalculan con el horario de esa fecha y cada cambio queda en el historial con su motivo.
+// Ninguna aplica a días de una planilla ya cerrada.
+
+// AdminCreate registra la asistencia de un usuario para una fecha y horas arbitrarias
+// (ej: el teléfono del empleado se quedó sin batería).
+func (s *AttendanceService) AdminCreate(ctx context.Context, agencyID uuid.UUID, adminID uuid.UUID, req *dto.AdminCreateAttendanceRequest) (*dto.AttendanceResponse, error) {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.AgencyID != agencyID {
+		return nil, domain.ErrUserNotFound
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", req.Date, agency.Location())
+	if err != nil {
+		return nil, domain.ErrInvalidAttendance
+	}
+
+	punches := []domain.AttendancePunch{{Type: domain.TypeIn, Time: req.CheckInTime, Method: domain.MethodManual}}
+	if req.CheckOutTime != nil {
+		punches = append(punches, domain.AttendancePunch{Type: domain.TypeOut, Time: *req.CheckOutTime, Method: domain.MethodManual})
+	}
+	if err := checkNotInFuture(punches); err != nil {
+		return nil, err
+	}
+
+	var attendance *domain.Attendance
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// Si ya hay registro (ej: una ausencia del job) se debe editar en lugar de crear otro
+		existing, err := s.attendanceRepo.GetByUserAndDate(txCtx, agencyID, user.ID, date)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return domain.ErrAttendanceExists
+		}
+
+		attendance, err = s.createForDate(txCtx, agency, user.ID, date, punches, req.Notes)
+		if err != nil {
+			return err
+		}
+
+		return s.logRevision(txCtx, attendance, &domain.AttendanceRevision{
+			ChangedByID: adminID,
+			Action:      domain.RevisionAdminCreated,
+			Reason:      req.Reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToAttendanceResponse(attendance), nil
+}
+
+// AdminUpdate cambia la hora de entrada (primera entrada), la de salida (última salida) y/o las notas.
+// Si el registro no tiene salida, check_out_time la agrega; si es una ausencia, check_in_time agrega la entrada.
+func (s *AttendanceService) AdminUpdate(ctx context.Context, agencyID uuid.UUID, adminID uuid.UUID, attendanceID uuid.UUID, req *dto.AdminUpdateAttendanceRequest) (*dto.AttendanceResponse, error) {
+	if req.CheckInTime == nil && req.CheckOutTime == nil && req.Notes == nil {
+		return nil, domain.ErrInvalidAttendance
+	}
+
+	var attendance *domain.Attendance
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		attendance, err = s.getEditable(txCtx, agencyID, attendanceID)
+		if err != nil {
+			return err
+		}
+
+		agency, err := s.agencyRepo.GetByID(txCtx, agencyID)
+		if err != nil {
+			return err
+		}
+
+		revision := &domain.AttendanceRevision{
+			ChangedByID: adminID,
+			Action:      domain.RevisionAdminEdited,
+			Reason:      req.Reason,
+		}
+
+		return s.reviseAttendance(txCtx, agency, attendance, revision, func() error {
+			if req.CheckInTime != nil {
+				if err := s.setPunchTime(txCtx, attendance, domain.TypeIn, *req.CheckInTime); err != nil {
+					return err
+				}
+			}
+			if req.CheckOutTime != nil {
+				if err := s.setPunchTime(txCtx, attendance, domain.TypeOut, *req.CheckOutTime); err != nil {
+					return err
+				}
+			}
+			if req.Notes != nil {
+				attendance.Notes = req.Notes
+			}
+			return checkNotInFuture(attendance.Punches)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToAttendanceResponse(attendance), nil
+}
+
+// Void anula un registro: se conserva con su historial pero deja de contar en listados,
+// y el día queda libre para registrar la asistencia de nuevo. Lo que aportó a la bolsa de horas se revierte.
+func (s *AttendanceService) Void(ctx context.Context, agencyID uuid.UUID, adminID uuid.UUID, attendanceID uuid.UUID, req *dto.VoidAttendanceRequest) (*dto.AttendanceResponse, error) {
+	var attendance *domain.Attendance
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		attendance, err = s.getEditable(txCtx, agencyID, attendanceID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkUnlocked(txCtx, attendance.UserID, attendance.Date); err != nil {
+			return err
+		}
+
+		revision := &domain.AttendanceRevision{
+			ChangedByID: adminID,
+			Action:      domain.RevisionVoided,
+			Reason:      req.Reason,
+		}
+		snapshotPrevious(revision, attendance)
+
+		now := time.Now()
+		attendance.VoidedAt = &now
+		attendance.VoidedByID = &adminID
+		attendance.VoidReason = &req.Reason
+
+		if err := s.attendanceRepo.Update(txCtx, attendance); err != nil {
+			return err
+		}
+
+		agency, err := s.agencyRepo.GetByID(txCtx, agencyID)
+		if err != nil {
+			return err
+		}
+		if err := s.timeBankSvc.syncAttendance(txCtx, agency, attendance); err != nil {
+			return err
+		}
+		return s.logRevision(txCtx, attendance, revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToAttendanceResponse(attendance), nil
+}
+
+// ResolveFlag quita la marca de revisión de un registro sospechoso una vez que un admin lo revisó.
+// Los indicios y el puntaje se conservan; el registro solo vuelve a marcarse si una marca posterior aporta un indicio nuevo.
+func (s *AttendanceService) ResolveFlag(ctx context.Context, agencyID uuid.UUID, adminID uuid.UUID, attendanceID uuid.UUID, req *dto.ResolveFlagRequest) (*dto.AttendanceResponse, error) {
+	var attendance *domain.Attendance
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		attendance, err = s.getEditable(txCtx, agencyID, attendanceID)
+		if err != nil {
+			return err
+		}
+		if !attendance.FlaggedForReview {
+			return domain.ErrAttendanceNotFlagged
+		}
+
+		revision := &domain.AttendanceRevision{
+			ChangedByID: adminID,
+			Action:      domain.RevisionFlagResolved,
+			Reason:      req.Reason,
+		}
+		snapshotPrevious(revision, attendance)
+
+		attendance.FlaggedForReview = false
+		if err := s.attendanceRepo.Update(txCtx, attendance); err != nil {
+			return err
+		}
+		return s.logRevision(txCtx, attendance, revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToAttendanceResponse(attendance), nil
+}
+
+func (s *AttendanceService) getEditable(ctx context.Context, agencyID uuid.UUID, attendanceID uuid.UUID) (*domain.Attendance, error) {
+	attendance, err := s.attendanceRepo.GetByID(ctx, attendanceID)
+	if err != nil {
+		return nil, err
+	}
+	if attendance.AgencyID != agencyID {
+		return nil, domain.ErrAttendanceNotFound
+	}
+	if attendance.VoidedAt != nil {
+		return nil, domain.ErrAttendanceVoided
+	}
+	return attendance, nil
+}
+
+// checkUnlocked rechaza el cambio si date pertenece a una planilla cerrada del usuario
+func (s *AttendanceService) checkUnlocked(ctx context.Context, userID uuid.UUID, date time.Time) error {
+	return s.timesheetSvc.checkUnlocked(ctx, userID, date)
+}
+
+// setPunchTime mueve la primera entrada o la última salida del registro a at.
+// Si esa marca no existe se agrega como marca manual.
+func (s *AttendanceService) setPunchTime(ctx context.Context, attendance *domain.Attendance, punchType domain.AttendanceType, at time.Time) error {
+	var punch *domain.AttendancePunch
+	if punchType == domain.TypeIn {
+		for i := range attendance.Punches {
+			if attendance.Punches[i].Type == domain.TypeIn {
+				punch = &attendance.Punches[i]
+				break
+			}
+		}
+	} else if n := len(attendance.Punches); n > 0 && attendance.Punches[n-1].Type == domain.TypeOut {
+		punch = &attendance.Punches[n-1]
+	}
+
+	if punch != nil {
+		punch.Time = at
+		return s.attendanceRepo.UpdatePunch(ctx, punch)
+	}
+
+	newPunch := domain.AttendancePunch{
+		AttendanceID: attendance.ID,
+		Type:         punchType,
+		Time:         at,
+		Method:       domain.MethodManual,
+	}
+	if err := s.attendanceRepo.AddPunch(ctx, &newPunch); err != nil {
+		return err
+	}
+	attendance.Punches = append(attendance.Punches, newPunch)
+	return nil
+}
+
+func checkNotInFuture(punches []domain.AttendancePunch) error {
+	now := time.Now()
+	for _, p := range punches {
+		if p.Time.After(now) {
+			return domain.ErrAttendanceInFuture
+		}
+	}
+	return nil
+}
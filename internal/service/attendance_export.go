@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Registros leídos por consulta al exportar: acota la memoria sin multiplicar las idas a la base
+const exportBatchSize = 500
+
+// TableWriter recibe una exportación fila por fila. Lo implementan los codificadores de cada formato (csv, xlsx).
+type TableWriter interface {
+	WriteHeader(labels []string) error
+	WriteRow(values []any) error
+}
+
+// Encabezados de la exportación de asistencias por idioma, en el orden de attendanceExportRow
+var attendanceExportLabels = map[string][]string{
+	"es": {
+		"Nombre", "Apellido", "Email", "Fecha", "Entrada", "Salida", "Método de entrada", "Método de salida",
+		"Estado de entrada", "Estado de salida", "Sede", "Minutos programados", "Minutos trabajados",
+		"Minutos de pausa", "Horas extra (min)", "Déficit (min)", "Atraso (min)", "Salida anticipada (min)",
+	},
+	"en": {
+		"First name", "Last name", "Email", "Date", "Check-in", "Check-out", "Entry method", "Exit method",
+		"Entry status", "Exit status", "Site", "Scheduled minutes", "Worked minutes",
+		"Break minutes", "Overtime (min)", "Deficit (min)", "Late (min)", "Early leave (min)",
+	},
+}
+
+// ExportLanguage normaliza el idioma pedido para los encabezados de una exportación; por defecto español
+func ExportLanguage(lang string) string {
+	if _, ok := attendanceExportLabels[lang]; ok {
+		return lang
+	}
+	return "es"
+}
+
+// ExportAttendances escribe en out los registros que cumplen los mismos filtros que el listado, ordenados por fecha.
+// La paginación se ignora: se exportan todos, leyéndolos de a lotes para no cargarlos en memoria.
+// Las horas quedan en la zona horaria de la agencia y los encabezados en el idioma lang (es, en).
+func (s *AttendanceService) ExportAttendances(ctx context.Context, agencyID uuid.UUID, params *dto.AttendanceListParams, lang string, out TableWriter) error {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return err
+	}
+
+	filter := attendanceFilter(agency, params, nil)
+	filter.Page, filter.Limit = 0, 0
+
+	if err := out.WriteHeader(attendanceExportLabels[ExportLanguage(lang)]); err != nil {
+		return err
+	}
+
+	loc := agency.Location()
+	return s.attendanceRepo.ListInBatches(ctx, agencyID, filter, exportBatchSize, func(batch []*domain.Attendance) error {
+		for _, attendance := range batch {
+			if err := out.WriteRow(attendanceExportRow(attendance, loc)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// attendanceExportRow arma una fila de la exportación; las celdas sin dato quedan en nil (vacías)
+func attendanceExportRow(attendance *domain.Attendance, loc *time.Location) []any {
+	var exitStatus, methodOut, site, checkIn, checkOut any
+	if attendance.ExitStatus != nil {
+		exitStatus = string(*attendance.ExitStatus)
+	}
+	if attendance.MethodOut != nil {
+		methodOut = string(*attendance.MethodOut)
+	}
+	if attendance.Site != nil {
+		site = *attendance.Site
+	}
+	if attendance.CheckInTime != nil {
+		checkIn = attendance.CheckInTime.In(loc).Format("2006-01-02 15:04")
+	}
+	if attendance.CheckOutTime != nil {
+		checkOut = attendance.CheckOutTime.In(loc).Format("2006-01-02 15:04")
+	}
+
+	var lastName any
+	if attendance.User.LastName != nil {
+		lastName = *attendance.User.LastName
+	}
+
+	return []any{
+		attendance.User.FirstName,
+		lastName,
+		attendance.User.Email,
+		attendance.Date.Format("2006-01-02"),
+		checkIn,
+		checkOut,
+		string(attendance.MethodIn),
+		methodOut,
+		string(attendance.EntryStatus),
+		exitStatus,
+		site,
+		attendance.ScheduledMinutes,
+		attendance.WorkedMinutes,
+		breakMinutes(attendance),
+		attendance.OvertimeMinutes,
+		attendance.DeficitMinutes,
+		lateMinutes(attendance),
+		earlyLeaveMinutes(attendance),
+	}
+}
+
+// breakMinutes devuelve el tiempo entre la primera entrada y la última salida que no se trabajó (pausas)
+func breakMinutes(attendance *domain.Attendance) int {
+	if attendance.CheckInTime == nil || attendance.CheckOutTime == nil {
+		return 0
+	}
+	elapsed := int(attendance.CheckOutTime.Sub(*attendance.CheckInTime) / time.Minute)
+	if paused := elapsed - workedMinutes(attendance.Punches); paused > 0 {
+		return paused
+	}
+	return 0
+}
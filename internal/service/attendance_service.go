@@ -4,7 +4,7 @@ import (
 	"context"
 	"quickattendance-go/internal/domain"
 	"quickattendance-go/internal/dto"
-	"quickattendance-go/pkg/utils"
+	"quickattendance-go/pkg/security"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,33 +12,53 @@ import (
 
 type AttendanceService struct {
 	attendanceRepo domain.AttendanceRepo
+	revisionRepo   domain.AttendanceRevisionRepo
+	qrUseRepo      domain.QRTokenUseRepo
+	nfcTagRepo     domain.NFCTagRepo
+	siteRepo       domain.SiteRepo
 	userRepo       domain.UserRepo
+	agencyRepo     domain.AgencyRepo
 	scheduleSvc    *ScheduleService
+	timeBankSvc    *TimeBankService
+	timesheetSvc   *TimesheetService
+	fraud          *FraudScorer
+	qr             *security.QRService
 	transactor     domain.Transactor
 }
 
 func NewAttendanceService(
 	attendanceRepo domain.AttendanceRepo,
+	revisionRepo domain.AttendanceRevisionRepo,
+	qrUseRepo domain.QRTokenUseRepo,
+	nfcTagRepo domain.NFCTagRepo,
+	siteRepo domain.SiteRepo,
 	userRepo domain.UserRepo,
+	agencyRepo domain.AgencyRepo,
 	scheduleSvc *ScheduleService,
+	timeBankSvc *TimeBankService,
+	timesheetSvc *TimesheetService,
+	fraud *FraudScorer,
+	qr *security.QRService,
 	transactor domain.Transactor,
 ) *AttendanceService {
 	return &AttendanceService{
 		attendanceRepo: attendanceRepo,
+		revisionRepo:   revisionRepo,
+		qrUseRepo:      qrUseRepo,
+		nfcTagRepo:     nfcTagRepo,
+		siteRepo:       siteRepo,
 		userRepo:       userRepo,
+		agencyRepo:     agencyRepo,
 		scheduleSvc:    scheduleSvc,
+		timeBankSvc:    timeBankSvc,
+		timesheetSvc:   timesheetSvc,
+		fraud:          fraud,
+		qr:             qr,
 		transactor:     transactor,
 	}
 }
 
 func (s *AttendanceService) MarkAttendance(ctx context.Context, req *dto.MarkAttendanceRequest) (*dto.AttendanceResponse, error) {
-	now := time.Now()
-
-	sched, err := s.scheduleSvc.GetApplicableSchedule(ctx, req.AgencyID, req.UserID, now)
-	if err != nil {
-		return nil, err
-	}
-
 	var response *dto.AttendanceResponse
 
 	user, err := s.userRepo.GetByID(ctx, req.UserID)
@@ -49,18 +69,56 @@ func (s *AttendanceService) MarkAttendance(ctx context.Context, req *dto.MarkAtt
 		return nil, err
 	}
 
-	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
-		existing, err := s.attendanceRepo.GetTodayByUserID(txCtx, req.AgencyID, req.UserID)
+	agency, err := s.agencyRepo.GetByID(ctx, req.AgencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Todas las fechas y horas del turno se calculan en la zona horaria de la agencia
+	loc := agency.Location()
+	now := time.Now().In(loc)
+
+	position := locationRequest{lat: req.Latitude, lng: req.Longitude, accuracy: req.Accuracy}
+	if err := checkAccuracy(agency, position); err != nil {
+		return nil, err
+	}
+
+	var qrClaims *security.QRClaims
+	if req.Method == domain.MethodQR {
+		qrClaims, err = s.verifyQRToken(req)
 		if err != nil {
-			return err
+			return nil, err
 		}
+	}
+
+	var tag *domain.NFCTag
+	if req.Method == domain.MethodNFC {
+		tag, err = s.resolveNFCTag(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
 
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
 		if req.Method == domain.MethodManual {
 			if req.RequesterRole != domain.RoleAdmin {
 				return domain.ErrManualNotAllowed
 			}
 		}
 
+		// Se registra dentro de la transacción: si la marca falla el token no queda consumido
+		if qrClaims != nil {
+			use := &domain.QRTokenUse{
+				AgencyID:  req.AgencyID,
+				UserID:    req.UserID,
+				Nonce:     qrClaims.ID,
+				ExpiresAt: qrClaims.ExpiresAt.Time,
+			}
+			if err := s.qrUseRepo.Create(txCtx, use); err != nil {
+				return err
+			}
+		}
+
 		if req.IsRemote != nil && *req.IsRemote {
 			if user.HomeLatitude == nil || user.HomeLongitude == nil || user.HomeRadiusMeters == nil {
 				return domain.ErrHomeLocationNotSet
@@ -69,59 +127,143 @@ func (s *AttendanceService) MarkAttendance(ctx context.Context, req *dto.MarkAtt
 				return domain.ErrInvalidAttendance
 			}
 
-			dist := utils.Haversine(*user.HomeLatitude, *user.HomeLongitude, *req.Latitude, *req.Longitude)
-			if dist > float64(*user.HomeRadiusMeters) {
-				return domain.ErrGeofenceViolation
+			if err := checkHomeFence(agency, user, position); err != nil {
+				return err
 			}
 		}
 
+		// Marcas presenciales: se registra la sede que contiene la ubicación
+		var site *domain.Site
+		if (req.IsRemote == nil || !*req.IsRemote) && req.Method != domain.MethodManual {
+			var err error
+			site, err = s.resolveSite(txCtx, agency, position)
+			if err != nil {
+				return err
+			}
+		}
+
+		punch := domain.AttendancePunch{
+			Type:      req.Type,
+			Time:      now,
+			Method:    req.Method,
+			Latitude:  req.Latitude,
+			Longitude: req.Longitude,
+			Accuracy:  req.Accuracy,
+		}
+		// La sede de la etiqueta prevalece sobre la geocerca: la marca se hizo donde está instalada
+		if tag != nil {
+			punch.NFCTagID = &tag.ID
+			if tag.Site != nil {
+				site = tag.Site
+			}
+		}
+		if site != nil {
+			punch.SiteID = &site.ID
+			punch.Site = &site.Name
+		}
+
+		// Los indicios de ubicación falsa no rechazan la marca: la dejan para revisión de un admin
+		signals, err := s.fraud.Assess(txCtx, req.UserID, &punch, startOfDay(now))
+		if err != nil {
+			return err
+		}
+		punch.FraudSignals = signals
+
 		if req.Type == domain.TypeIn {
-			if existing != nil {
-				return domain.ErrAttendanceExists
+			shiftDate, sched, err := s.resolveShift(txCtx, req.AgencyID, req.UserID, now)
+			if err != nil {
+				return err
 			}
 
-			entryTime := parseTimeMinutes(now, sched.EntryTimeMinutes)
-			exitTime := parseTimeMinutes(now, sched.ExitTimeMinutes)
+			existing, err := s.attendanceRepo.GetByUserAndDate(txCtx, req.AgencyID, req.UserID, shiftDate)
+			if err != nil {
+				return err
+			}
 
-			status := domain.StatusPresent
-			lateLimit := entryTime.Add(time.Duration(sched.GracePeriodMinutes) * time.Minute)
-			if now.After(lateLimit) {
-				status = domain.StatusLate
+			if existing == nil {
+				attendance := &domain.Attendance{
+					UserID:      req.UserID,
+					AgencyID:    req.AgencyID,
+					CheckInTime: &now,
+					Date:        shiftDate,
+					MethodIn:    req.Method,
+					Notes:       req.Notes,
+					Punches:     []domain.AttendancePunch{punch},
+					Latitude:    req.Latitude,
+					Longitude:   req.Longitude,
+					Accuracy:    req.Accuracy,
+					NFCTagID:    punch.NFCTagID,
+					Site:        punch.Site,
+					SiteID:      punch.SiteID,
+				}
+				setSchedule(attendance, shiftDate, sched)
+				attendance.EntryStatus = evaluateEntry(now, attendance.ScheduleEntryTime, sched.GracePeriodMinutes)
+				flagAttendance(attendance, punch.FraudSignals)
+
+				if err := s.checkUnlocked(txCtx, attendance.UserID, attendance.Date); err != nil {
+					return err
+				}
+				if err := s.attendanceRepo.Create(txCtx, attendance); err != nil {
+					return err
+				}
+				response = dto.ToAttendanceResponse(attendance)
+				return nil
 			}
 
-			attendance := &domain.Attendance{
-				UserID:            req.UserID,
-				AgencyID:          req.AgencyID,
-				CheckInTime:       now,
-				ScheduleEntryTime: entryTime,
-				ScheduleExitTime:  exitTime,
-				Date:              now,
-				Status:            status,
-				MethodIn:          req.Method,
-				Notes:             req.Notes,
-				Latitude:          req.Latitude,
-				Longitude:         req.Longitude,
+			// Turno partido: una nueva entrada después de una salida reabre el registro del día
+			if err := validatePunch(existing.Punches, req.Type, now); err != nil {
+				return err
 			}
 
-			if err := s.attendanceRepo.Create(txCtx, attendance); err != nil {
+			// Registro de ausencia generado por el sistema: la primera entrada real lo reemplaza
+			if existing.CheckInTime == nil {
+				existing.CheckInTime = &now
+				existing.EntryStatus = evaluateEntry(now, existing.ScheduleEntryTime, sched.GracePeriodMinutes)
+				existing.MethodIn = req.Method
+				existing.Latitude = req.Latitude
+				existing.Longitude = req.Longitude
+				existing.Accuracy = req.Accuracy
+				existing.NFCTagID = punch.NFCTagID
+				existing.Site = punch.Site
+				existing.SiteID = punch.SiteID
+			}
+			existing.CheckOutTime = nil
+			existing.ExitStatus = nil
+			existing.MethodOut = nil
+
+			if err := s.appendPunch(txCtx, agency, existing, punch); err != nil {
 				return err
 			}
-			response = dto.ToAttendanceResponse(attendance)
+			response = dto.ToAttendanceResponse(existing)
 			return nil
 		}
 
+		// Las demás marcas se asocian al turno abierto, que pudo comenzar ayer si es nocturno
+		existing, err := s.attendanceRepo.GetOpenByUserID(txCtx, req.AgencyID, req.UserID, startOfDay(now).AddDate(0, 0, -1))
+		if err != nil {
+			return err
+		}
 		if existing == nil {
 			return domain.ErrAttendanceNotFound
 		}
 
-		if existing.CheckOutTime != nil {
-			return domain.ErrAttendanceExists
+		if err := validatePunch(existing.Punches, req.Type, now); err != nil {
+			return err
 		}
 
-		existing.CheckOutTime = &now
-		existing.MethodOut = &req.Method
+		if req.Type == domain.TypeOut {
+			tolerance := 0
+			if sched, err := s.scheduleSvc.GetApplicableSchedule(txCtx, req.AgencyID, req.UserID, dateIn(existing.Date, loc)); err == nil {
+				tolerance = sched.EarlyLeaveToleranceMinutes
+			}
+			exitStatus := evaluateExit(now, existing.ScheduleExitTime, tolerance)
+
+			existing.CheckOutTime = &now
+			existing.ExitStatus = &exitStatus
+			existing.MethodOut = &req.Method
+		}
 
-		if err := s.attendanceRepo.Update(txCtx, existing); err != nil {
+		if err := s.appendPunch(txCtx, agency, existing, punch); err != nil {
 			return err
 		}
 		response = dto.ToAttendanceResponse(existing)
@@ -135,11 +277,294 @@ func (s *AttendanceService) MarkAttendance(ctx context.Context, req *dto.MarkAtt
 	return response, nil
 }
 
+// verifyQRToken valida que el token del código escaneado esté firmado, vigente y pertenezca a la agencia
+func (s *AttendanceService) verifyQRToken(req *dto.MarkAttendanceRequest) (*security.QRClaims, error) {
+	if req.QRToken == nil || *req.QRToken == "" {
+		return nil, domain.ErrQRTokenRequired
+	}
+
+	claims, err := s.qr.Verify(*req.QRToken)
+	if err != nil {
+		if err == security.ErrQRTokenExpired {
+			return nil, domain.ErrQRTokenExpired
+		}
+		return nil, domain.ErrQRTokenInvalid
+	}
+
+	if claims.AgencyID != req.AgencyID {
+		return nil, domain.ErrQRTokenInvalid
+	}
+	return claims, nil
+}
+
+// resolveNFCTag exige que la etiqueta leída esté registrada y activa en la agencia
+func (s *AttendanceService) resolveNFCTag(ctx context.Context, req *dto.MarkAttendanceRequest) (*domain.NFCTag, error) {
+	if req.NFCTagUID == nil || normalizeTagUID(*req.NFCTagUID) == "" {
+		return nil, domain.ErrNFCTagRequired
+	}
+
+	tag, err := s.nfcTagRepo.GetByUID(ctx, req.AgencyID, normalizeTagUID(*req.NFCTagUID))
+	if err != nil {
+		return nil, err
+	}
+	if !tag.Active {
+		return nil, domain.ErrNFCTagInactive
+	}
+	return tag, nil
+}
+
+// GetQRToken devuelve el token que debe mostrar el código QR de la agencia en este momento
+func (s *AttendanceService) GetQRToken(ctx context.Context, agencyID uuid.UUID) (*dto.QRTokenResponse, error) {
+	token, err := s.qr.Issue(agencyID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.QRTokenResponse{
+		Token:           token.Token,
+		RefreshAt:       token.RefreshAt,
+		ExpiresAt:       token.ExpiresAt,
+		RotationSeconds: int(s.qr.Rotation() / time.Second),
+	}, nil
+}
+
+// appendPunch guarda la marca en el registro diario y recalcula el tiempo trabajado
+func (s *AttendanceService) appendPunch(ctx context.Context, agency *domain.Agency, attendance *domain.Attendance, punch domain.AttendancePunch) error {
+	if err := s.checkUnlocked(ctx, attendance.UserID, attendance.Date); err != nil {
+		return err
+	}
+
+	punch.AttendanceID = attendance.ID
+	if err := s.attendanceRepo.AddPunch(ctx, &punch); err != nil {
+		return err
+	}
+
+	attendance.Punches = append(attendance.Punches, punch)
+	applyWorkTotals(agency, attendance)
+	flagAttendance(attendance, punch.FraudSignals)
+
+	if err := s.attendanceRepo.Update(ctx, attendance); err != nil {
+		return err
+	}
+	return s.timeBankSvc.syncAttendance(ctx, agency, attendance)
+}
+
+// GetHistory devuelve los cambios aplicados a una asistencia. Los empleados solo ven las propias.
+func (s *AttendanceService) GetHistory(ctx context.Context, agencyID uuid.UUID, attendanceID uuid.UUID, requesterID uuid.UUID, requesterRole domain.Role) ([]*dto.AttendanceRevisionResponse, error) {
+	attendance, err := s.attendanceRepo.GetByID(ctx, attendanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attendance.AgencyID != agencyID {
+		return nil, domain.ErrAttendanceNotFound
+	}
+	if requesterRole != domain.RoleAdmin && attendance.UserID != requesterID {
+		return nil, domain.ErrAttendanceNotFound
+	}
+
+	revisions, err := s.revisionRepo.ListByAttendanceID(ctx, attendanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.AttendanceRevisionResponse, len(revisions))
+	for i, r := range revisions {
+		responses[i] = dto.ToAttendanceRevisionResponse(r)
+	}
+	return responses, nil
+}
+
+// createForDate crea el registro de un día a partir de marcas ingresadas después del hecho
+// (corrección aprobada, registro manual de un admin). El horario se resuelve para esa fecha.
+func (s *AttendanceService) createForDate(ctx context.Context, agency *domain.Agency, userID uuid.UUID, date time.Time, punches []domain.AttendancePunch, notes *string) (*domain.Attendance, error) {
+	if err := s.checkUnlocked(ctx, userID, date); err != nil {
+		return nil, err
+	}
+
+	sched, err := s.scheduleSvc.GetApplicableSchedule(ctx, agency.ID, userID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	attendance := &domain.Attendance{
+		UserID:   userID,
+		AgencyID: agency.ID,
+		Date:     date,
+		Notes:    notes,
+		Punches:  punches,
+	}
+	setSchedule(attendance, date, sched)
+
+	if err := s.recalculate(ctx, agency, attendance); err != nil {
+		return nil, err
+	}
+	if err := checkShiftWindow(attendance, agency.Location()); err != nil {
+		return nil, err
+	}
+
+	if err := s.attendanceRepo.Create(ctx, attendance); err != nil {
+		return nil, err
+	}
+	if err := s.timeBankSvc.syncAttendance(ctx, agency, attendance); err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
+// reviseAttendance aplica mutate sobre un registro existente, recalcula los valores derivados
+// y guarda en el historial quién hizo el cambio, por qué y los valores anteriores y nuevos.
+// Los registros de una planilla cerrada no se modifican.
+func (s *AttendanceService) reviseAttendance(ctx context.Context, agency *domain.Agency, attendance *domain.Attendance, revision *domain.AttendanceRevision, mutate func() error) error {
+	if err := s.checkUnlocked(ctx, attendance.UserID, attendance.Date); err != nil {
+		return err
+	}
+
+	snapshotPrevious(revision, attendance)
+
+	if err := mutate(); err != nil {
+		return err
+	}
+
+	if err := s.recalculate(ctx, agency, attendance); err != nil {
+		return err
+	}
+	if err := checkShiftWindow(attendance, agency.Location()); err != nil {
+		return err
+	}
+
+	if err := s.attendanceRepo.Update(ctx, attendance); err != nil {
+		return err
+	}
+	if err := s.timeBankSvc.syncAttendance(ctx, agency, attendance); err != nil {
+		return err
+	}
+
+	return s.logRevision(ctx, attendance, revision)
+}
+
+// logRevision completa la revisión con los valores actuales del registro y la guarda
+func (s *AttendanceService) logRevision(ctx context.Context, attendance *domain.Attendance, revision *domain.AttendanceRevision) error {
+	revision.AttendanceID = attendance.ID
+	revision.AgencyID = attendance.AgencyID
+	revision.NewCheckInTime = attendance.CheckInTime
+	revision.NewCheckOutTime = attendance.CheckOutTime
+	revision.NewEntryStatus = attendance.EntryStatus
+	revision.NewExitStatus = attendance.ExitStatus
+	revision.NewNotes = attendance.Notes
+
+	return s.revisionRepo.Create(ctx, revision)
+}
+
+// snapshotPrevious copia en la revisión los valores del registro antes del cambio
+func snapshotPrevious(revision *domain.AttendanceRevision, attendance *domain.Attendance) {
+	revision.PreviousCheckInTime = attendance.CheckInTime
+	revision.PreviousCheckOutTime = attendance.CheckOutTime
+	revision.PreviousEntryStatus = attendance.EntryStatus
+	revision.PreviousExitStatus = attendance.ExitStatus
+	revision.PreviousNotes = attendance.Notes
+}
+
+// recalculate deriva entrada, salida, estados y totales a partir de las marcas del registro.
+// Se usa cuando las marcas cambian después de registradas, por lo que primero valida la secuencia completa.
+func (s *AttendanceService) recalculate(ctx context.Context, agency *domain.Agency, attendance *domain.Attendance) error {
+	sortPunches(attendance.Punches)
+	if err := validatePunchSequence(attendance.Punches); err != nil {
+		return err
+	}
+	if len(attendance.Punches) == 0 {
+		return domain.ErrInvalidAttendance
+	}
+
+	grace, tolerance := 0, 0
+	if sched, err := s.scheduleSvc.GetApplicableSchedule(ctx, attendance.AgencyID, attendance.UserID, dateIn(attendance.Date, agency.Location())); err == nil {
+		grace = sched.GracePeriodMinutes
+		tolerance = sched.EarlyLeaveToleranceMinutes
+	}
+
+	first := attendance.Punches[0]
+	checkIn := first.Time
+	attendance.CheckInTime = &checkIn
+	attendance.MethodIn = first.Method
+	attendance.EntryStatus = evaluateEntry(checkIn, attendance.ScheduleEntryTime, grace)
+
+	attendance.CheckOutTime = nil
+	attendance.ExitStatus = nil
+	attendance.MethodOut = nil
+
+	last := attendance.Punches[len(attendance.Punches)-1]
+	if last.Type == domain.TypeOut {
+		checkOut := last.Time
+		methodOut := last.Method
+		exitStatus := evaluateExit(checkOut, attendance.ScheduleExitTime, tolerance)
+		attendance.CheckOutTime = &checkOut
+		attendance.MethodOut = &methodOut
+		attendance.ExitStatus = &exitStatus
+	}
+
+	applyWorkTotals(agency, attendance)
+	return nil
+}
+
+// resolveShift determina a qué turno corresponde una entrada marcada en now.
+// Un turno nocturno que comenzó ayer y aún no termina tiene prioridad sobre el de hoy,
+// así una entrada atrasada después de medianoche se evalúa contra la hora de entrada de ayer.
+func (s *AttendanceService) resolveShift(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, now time.Time) (time.Time, *dto.ScheduleResponse, error) {
+	today := startOfDay(now)
+	yesterday := today.AddDate(0, 0, -1)
+
+	prev, err := s.scheduleSvc.GetApplicableSchedule(ctx, agencyID, userID, yesterday)
+	if err == nil && prev.CrossesMidnight {
+		_, prevExit := shiftBounds(yesterday, prev.EntryTimeMinutes, prev.ExitTimeMinutes)
+		if now.Before(prevExit) {
+			return yesterday, prev, nil
+		}
+	}
+
+	sched, err := s.scheduleSvc.GetApplicableSchedule(ctx, agencyID, userID, today)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return today, sched, nil
+}
+
 func (s *AttendanceService) GetAgencyAttendances(ctx context.Context, agencyID uuid.UUID, params *dto.AttendanceListParams) ([]*dto.AttendanceResponse, error) {
+	return s.listAttendances(ctx, agencyID, params, nil)
+}
+
+// GetSuspiciousAttendances lista los registros marcados para revisión por indicios de ubicación falsa, los más recientes primero
+func (s *AttendanceService) GetSuspiciousAttendances(ctx context.Context, agencyID uuid.UUID, params *dto.AttendanceListParams) ([]*dto.AttendanceResponse, error) {
+	flagged := true
+	return s.listAttendances(ctx, agencyID, params, &flagged)
+}
+
+func (s *AttendanceService) listAttendances(ctx context.Context, agencyID uuid.UUID, params *dto.AttendanceListParams, flagged *bool) ([]*dto.AttendanceResponse, error) {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	attendances, err := s.attendanceRepo.List(ctx, agencyID, attendanceFilter(agency, params, flagged))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.AttendanceResponse, len(attendances))
+	for i, a := range attendances {
+		responses[i] = dto.ToAttendanceResponse(a)
+	}
+	return responses, nil
+}
+
+// attendanceFilter traduce los parámetros de un listado al filtro del repositorio; los valores mal formados se ignoran
+func attendanceFilter(agency *domain.Agency, params *dto.AttendanceListParams, flagged *bool) domain.AttendanceFilter {
 	filter := domain.AttendanceFilter{
-		Page:   params.Page,
-		Limit:  params.Limit,
-		Status: domain.AttendanceStatus(params.Status),
+		Flagged:     flagged,
+		Page:        params.Page,
+		Limit:       params.Limit,
+		Status:      domain.AttendanceStatus(params.Status),
+		EntryStatus: domain.AttendanceStatus(params.EntryStatus),
+		ExitStatus:  domain.AttendanceStatus(params.ExitStatus),
 	}
 
 	if params.UserID != "" {
@@ -148,27 +573,109 @@ func (s *AttendanceService) GetAgencyAttendances(ctx context.Context, agencyID u
 		}
 	}
 
+	// La columna date guarda el día local de la agencia, así que los filtros se interpretan en esa zona
+	loc := agency.Location()
 	if params.StartDate != "" {
-		if t, err := time.Parse("2006-01-02", params.StartDate); err == nil {
+		if t, err := time.ParseInLocation("2006-01-02", params.StartDate, loc); err == nil {
 			filter.StartDate = &t
 		}
 	}
 	if params.EndDate != "" {
-		if t, err := time.Parse("2006-01-02", params.EndDate); err == nil {
+		if t, err := time.ParseInLocation("2006-01-02", params.EndDate, loc); err == nil {
 			filter.EndDate = &t
 		}
 	}
+	return filter
+}
 
-	attendances, err := s.attendanceRepo.List(ctx, agencyID, filter)
-	if err != nil {
-		return nil, err
+// evaluateEntry clasifica la entrada: atrasada si ocurre después de la hora de entrada más el periodo de gracia
+func evaluateEntry(checkIn time.Time, scheduledEntry time.Time, graceMinutes int) domain.AttendanceStatus {
+	lateLimit := scheduledEntry.Add(time.Duration(graceMinutes) * time.Minute)
+	if checkIn.After(lateLimit) {
+		return domain.StatusLate
 	}
+	return domain.StatusPresent
+}
 
-	responses := make([]*dto.AttendanceResponse, len(attendances))
-	for i, a := range attendances {
-		responses[i] = dto.ToAttendanceResponse(a)
+// evaluateExit clasifica la salida: anticipada si ocurre antes de la hora de salida menos la tolerancia
+func evaluateExit(checkOut time.Time, scheduledExit time.Time, toleranceMinutes int) domain.AttendanceStatus {
+	earlyLimit := scheduledExit.Add(-time.Duration(toleranceMinutes) * time.Minute)
+	if checkOut.Before(earlyLimit) {
+		return domain.StatusEarly
 	}
-	return responses, nil
+	return domain.StatusOnTime
+}
+
+// setSchedule copia en el registro las horas contra las que se evalúa el día. En una jornada flexible la
+// entrada y salida programadas son el tiempo núcleo y la banda se guarda aparte para acotar lo trabajado.
+func setSchedule(attendance *domain.Attendance, date time.Time, sched *dto.ScheduleResponse) {
+	entry, exit := shiftBounds(date, sched.EntryTimeMinutes, sched.ExitTimeMinutes)
+	attendance.ScheduleEntryTime = entry
+	attendance.ScheduleExitTime = exit
+	attendance.FlexBandStart = nil
+	attendance.FlexBandEnd = nil
+	attendance.RequiredMinutes = 0
+
+	if sched.Type != domain.ScheduleFlexible {
+		return
+	}
+
+	attendance.FlexBandStart = &entry
+	attendance.FlexBandEnd = &exit
+	attendance.ScheduleEntryTime = entry.Add(time.Duration(domain.BandOffset(sched.EntryTimeMinutes, sched.CoreStartMinutes)) * time.Minute)
+	attendance.ScheduleExitTime = entry.Add(time.Duration(domain.BandOffset(sched.EntryTimeMinutes, sched.CoreEndMinutes)) * time.Minute)
+	attendance.RequiredMinutes = sched.RequiredMinutes
+}
+
+// checkShiftWindow valida que las marcas ingresadas después del hecho pertenezcan al turno de la fecha del registro,
+// con el mismo criterio con que resolveShift asigna las marcas en vivo: la entrada cae entre la medianoche de la
+// fecha (en la zona de la agencia) y la medianoche siguiente, o la salida programada si el turno termina al otro día.
+// Ninguna marca puede estar a más de 24 horas de la entrada. Las marcas deben venir ordenadas.
+func checkShiftWindow(attendance *domain.Attendance, loc *time.Location) error {
+	if len(attendance.Punches) == 0 {
+		return nil
+	}
+
+	start := dateIn(attendance.Date, loc)
+	end := start.AddDate(0, 0, 1)
+	exit := attendance.ScheduleExitTime
+	if attendance.FlexBandEnd != nil {
+		exit = *attendance.FlexBandEnd
+	}
+	if exit.After(end) {
+		end = exit
+	}
+
+	first := attendance.Punches[0].Time
+	if first.Before(start) || !first.Before(end) {
+		return domain.ErrOutsideShiftWindow
+	}
+	for _, p := range attendance.Punches {
+		if p.Time.Sub(first) > 24*time.Hour {
+			return domain.ErrOutsideShiftWindow
+		}
+	}
+	return nil
+}
+
+// shiftBounds devuelve la entrada y salida programadas de un turno que comienza en date.
+// Si la salida es menor o igual a la entrada, el turno termina al día siguiente.
+func shiftBounds(date time.Time, entryMinutes int, exitMinutes int) (time.Time, time.Time) {
+	entry := parseTimeMinutes(date, entryMinutes)
+	exit := parseTimeMinutes(date, exitMinutes)
+	if exitMinutes <= entryMinutes {
+		exit = parseTimeMinutes(date.AddDate(0, 0, 1), exitMinutes)
+	}
+	return entry, exit
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// dateIn interpreta el día calendario de date (ej: una columna date leída como UTC) como medianoche en loc
+func dateIn(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
 }
 
 // Helper to set hours/minutes on a base date
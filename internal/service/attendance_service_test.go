@@ -0,0 +1,66 @@
+package service
+
+import (
+	"quickattendance-go/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestCheckShiftWindow(t *testing.T) {
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, 3, day, hour, min, 0, 0, santiago)
+	}
+	// The date column is read back as UTC midnight
+	date := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	dayShift := func(punches ...time.Time) *domain.Attendance {
+		a := &domain.Attendance{Date: date, ScheduleEntryTime: at(3, 9, 0), ScheduleExitTime: at(3, 18, 0)}
+		for i, p := range punches {
+			typ := domain.TypeIn
+			if i%2 == 1 {
+				typ = domain.TypeOut
+			}
+			a.Punches = append(a.Punches, domain.AttendancePunch{Type: typ, Time: p})
+		}
+		return a
+	}
+	nightShift := func(punches ...time.Time) *domain.Attendance {
+		a := dayShift(punches...)
+		a.ScheduleEntryTime, a.ScheduleExitTime = at(3, 22, 0), at(4, 6, 0)
+		return a
+	}
+
+	tests := []struct {
+		name       string
+		attendance *domain.Attendance
+		wantErr    bool
+	}{
+		{"day shift", dayShift(at(3, 9, 2), at(3, 18, 0)), false},
+		{"check-in only", dayShift(at(3, 9, 2)), false},
+		{"stayed past midnight", dayShift(at(3, 9, 0), at(4, 1, 30)), false},
+		{"check-in the day before", dayShift(at(2, 23, 0), at(3, 18, 0)), true},
+		{"check-in months later", dayShift(time.Date(2026, 7, 3, 9, 0, 0, 0, santiago)), true},
+		{"check-in the next day", dayShift(at(4, 9, 0), at(4, 18, 0)), true},
+		{"check-out over 24 hours later", dayShift(at(3, 9, 0), at(4, 9, 30)), true},
+		{"night shift", nightShift(at(3, 22, 0), at(4, 6, 0)), false},
+		{"late check-in after midnight of a night shift", nightShift(at(4, 0, 30), at(4, 6, 0)), false},
+		{"check-in after the night shift ended", nightShift(at(4, 7, 0), at(4, 9, 0)), true},
+		{"no punches", dayShift(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkShiftWindow(tt.attendance, santiago)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkShiftWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && err != domain.ErrOutsideShiftWindow {
+				t.Errorf("checkShiftWindow() error = %v, want ErrOutsideShiftWindow", err)
+			}
+		})
+	}
+}
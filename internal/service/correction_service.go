@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CorrectionService struct {
+	correctionRepo domain.CorrectionRepo
+	attendanceRepo domain.AttendanceRepo
+	userRepo       domain.UserRepo
+	agencyRepo     domain.AgencyRepo
+	attendanceSvc  *AttendanceService
+	notificator    domain.NotificationProvider
+	transactor     domain.Transactor
+}
+
+func NewCorrectionService(
+	correctionRepo domain.CorrectionRepo,
+	attendanceRepo domain.AttendanceRepo,
+	userRepo domain.UserRepo,
+	agencyRepo domain.AgencyRepo,
+	attendanceSvc *AttendanceService,
+	notificator domain.NotificationProvider,
+	transactor domain.Transactor,
+) *CorrectionService {
+	return &CorrectionService{
+		correctionRepo: correctionRepo,
+		attendanceRepo: attendanceRepo,
+		userRepo:       userRepo,
+		agencyRepo:     agencyRepo,
+		attendanceSvc:  attendanceSvc,
+		notificator:    notificator,
+		transactor:     transactor,
+	}
+}
+
+// Submit registra la solicitud de corrección de un empleado sobre su propia asistencia
+// y avisa a los administradores de la agencia.
+func (s *CorrectionService) Submit(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, req *dto.CreateCorrectionRequest) (*dto.CorrectionResponse, error) {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RequestedTime.After(time.Now()) {
+		return nil, domain.ErrCorrectionInTheFuture
+	}
+
+	correction := &domain.AttendanceCorrection{
+		AgencyID:      agencyID,
+		UserID:        userID,
+		Type:          req.Type,
+		RequestedTime: req.RequestedTime,
+		Reason:        req.Reason,
+		Status:        domain.CorrectionPending,
+	}
+
+	switch req.Type {
+	case domain.CorrectionMissingPunch:
+		if req.PunchType == nil {
+			return nil, domain.ErrInvalidCorrection
+		}
+		correction.PunchType = req.PunchType
+	case domain.CorrectionWrongTime:
+		// Para corregir una hora hay que indicar la marca y el registro al que pertenece
+		if req.PunchID == nil || req.AttendanceID == nil {
+			return nil, domain.ErrInvalidCorrection
+		}
+		correction.PunchID = req.PunchID
+	default:
+		return nil, domain.ErrInvalidCorrection
+	}
+
+	if req.AttendanceID != nil {
+		attendance, err := s.attendanceRepo.GetByID(ctx, *req.AttendanceID)
+		if err != nil {
+			return nil, err
+		}
+		if attendance.AgencyID != agencyID || attendance.UserID != userID || attendance.VoidedAt != nil {
+			return nil, domain.ErrAttendanceNotFound
+		}
+		if req.PunchID != nil && findPunch(attendance.Punches, *req.PunchID) == nil {
+			return nil, domain.ErrPunchNotFound
+		}
+		correction.AttendanceID = &attendance.ID
+		correction.Date = attendance.Date
+	} else {
+		if req.Date == "" {
+			return nil, domain.ErrInvalidCorrection
+		}
+		date, err := time.ParseInLocation("2006-01-02", req.Date, agency.Location())
+		if err != nil {
+			return nil, domain.ErrInvalidCorrection
+		}
+
+		// Si ese día ya tiene registro la corrección se asocia a él
+		attendance, err := s.attendanceRepo.GetByUserAndDate(ctx, agencyID, userID, date)
+		if err != nil {
+			return nil, err
+		}
+		if attendance != nil {
+			correction.AttendanceID = &attendance.ID
+		}
+		correction.Date = date
+	}
+
+	// Una planilla cerrada ya no admite cambios en sus días
+	if err := s.attendanceSvc.checkUnlocked(ctx, userID, correction.Date); err != nil {
+		return nil, err
+	}
+
+	if err := s.correctionRepo.Create(ctx, correction); err != nil {
+		return nil, err
+	}
+
+	admins, err := s.userRepo.ListByAgencyID(ctx, agencyID, domain.UserFilter{Status: string(domain.StatusActive), Role: domain.RoleAdmin})
+	if err != nil {
+		log.Printf("Error listing admins for correction %s: %v", correction.ID, err)
+	}
+	for _, admin := range admins {
+		subject := "Nueva solicitud de corrección de asistencia"
+		body := fmt.Sprintf("Hola %s, hay una nueva solicitud de corrección de asistencia para el %s pendiente de revisión. Motivo: %s", admin.FirstName, correction.Date.Format("2006-01-02"), correction.Reason)
+		s.notify(admin.Email, subject, body)
+	}
+
+	return dto.ToCorrectionResponse(correction), nil
+}
+
+func (s *CorrectionService) List(ctx context.Context, agencyID uuid.UUID, params *dto.CorrectionListParams) ([]*dto.CorrectionResponse, error) {
+	filter := domain.CorrectionFilter{
+		Status: domain.CorrectionStatus(params.Status),
+		Page:   params.Page,
+		Limit:  params.Limit,
+	}
+	if params.UserID != "" {
+		uid, err := uuid.Parse(params.UserID)
+		if err == nil {
+			filter.UserID = uid
+		}
+	}
+
+	corrections, err := s.correctionRepo.List(ctx, agencyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.CorrectionResponse, len(corrections))
+	for i, c := range corrections {
+		responses[i] = dto.ToCorrectionResponse(c)
+	}
+	return responses, nil
+}
+
+// Approve aplica la corrección sobre el registro del día (creándolo si faltaba la entrada),
+// recalcula estados y totales y deja el cambio en el historial de la asistencia.
+func (s *CorrectionService) Approve(ctx context.Context, agencyID uuid.UUID, reviewerID uuid.UUID, correctionID uuid.UUID, req *dto.ReviewCorrectionRequest) (*dto.CorrectionResponse, error) {
+	var correction *domain.AttendanceCorrection
+
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		correction, err = s.getPending(txCtx, agencyID, correctionID)
+		if err != nil {
+			return err
+		}
+
+		agency, err := s.agencyRepo.GetByID(txCtx, agencyID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.apply(txCtx, agency, reviewerID, correction); err != nil {
+			return err
+		}
+
+		s.markReviewed(correction, domain.CorrectionApproved, reviewerID, req.Note)
+		return s.correctionRepo.Update(txCtx, correction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyReviewed(correction)
+	return dto.ToCorrectionResponse(correction), nil
+}
+
+func (s *CorrectionService) Reject(ctx context.Context, agencyID uuid.UUID, reviewerID uuid.UUID, correctionID uuid.UUID, req *dto.ReviewCorrectionRequest) (*dto.CorrectionResponse, error) {
+	var correction *domain.AttendanceCorrection
+
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		correction, err = s.getPending(txCtx, agencyID, correctionID)
+		if err != nil {
+			return err
+		}
+
+		s.markReviewed(correction, domain.CorrectionRejected, reviewerID, req.Note)
+		return s.correctionRepo.Update(txCtx, correction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyReviewed(correction)
+	return dto.ToCorrectionResponse(correction), nil
+}
+
+// getPending bloquea la solicitud hasta el fin de la transacción: dos revisiones simultáneas se serializan
+// y la segunda la encuentra ya revisada
+func (s *CorrectionService) getPending(ctx context.Context, agencyID uuid.UUID, correctionID uuid.UUID) (*domain.AttendanceCorrection, error) {
+	correction, err := s.correctionRepo.GetByIDForUpdate(ctx, correctionID)
+	if err != nil {
+		return nil, err
+	}
+	if correction.AgencyID != agencyID {
+		return nil, domain.ErrCorrectionNotFound
+	}
+	if correction.Status != domain.CorrectionPending {
+		return nil, domain.ErrCorrectionNotPending
+	}
+	return correction, nil
+}
+
+// apply modifica las marcas del registro según la corrección
+func (s *CorrectionService) apply(ctx context.Context, agency *domain.Agency, reviewerID uuid.UUID, correction *domain.AttendanceCorrection) error {
+	var attendance *domain.Attendance
+	var err error
+	if correction.AttendanceID != nil {
+		attendance, err = s.attendanceRepo.GetByID(ctx, *correction.AttendanceID)
+	} else {
+		// Puede haberse creado un registro (ej: ausencia del job) después de enviar la solicitud
+		attendance, err = s.attendanceRepo.GetByUserAndDate(ctx, agency.ID, correction.UserID, dateIn(correction.Date, agency.Location()))
+	}
+	if err != nil {
+		return err
+	}
+	if attendance != nil && attendance.VoidedAt != nil {
+		return domain.ErrAttendanceVoided
+	}
+
+	revision := &domain.AttendanceRevision{
+		ChangedByID:  reviewerID,
+		Action:       domain.RevisionCorrectionApproved,
+		Reason:       correction.Reason,
+		CorrectionID: &correction.ID,
+	}
+
+	if attendance == nil {
+		// Sin registro solo se puede agregar la entrada olvidada
+		if correction.Type != domain.CorrectionMissingPunch || *correction.PunchType != domain.TypeIn {
+			return domain.ErrInvalidCorrection
+		}
+
+		punches := []domain.AttendancePunch{{
+			Type:   domain.TypeIn,
+			Time:   correction.RequestedTime,
+			Method: domain.MethodManual,
+		}}
+		attendance, err = s.attendanceSvc.createForDate(ctx, agency, correction.UserID, dateIn(correction.Date, agency.Location()), punches, nil)
+		if err != nil {
+			return err
+		}
+
+		correction.AttendanceID = &attendance.ID
+		return s.attendanceSvc.logRevision(ctx, attendance, revision)
+	}
+
+	correction.AttendanceID = &attendance.ID
+	return s.attendanceSvc.reviseAttendance(ctx, agency, attendance, revision, func() error {
+		switch correction.Type {
+		case domain.CorrectionMissingPunch:
+			punch := domain.AttendancePunch{
+				AttendanceID: attendance.ID,
+				Type:         *correction.PunchType,
+				Time:         correction.RequestedTime,
+				Method:       domain.MethodManual,
+			}
+			if err := s.attendanceRepo.AddPunch(ctx, &punch); err != nil {
+				return err
+			}
+			attendance.Punches = append(attendance.Punches, punch)
+		case domain.CorrectionWrongTime:
+			punch := findPunch(attendance.Punches, *correction.PunchID)
+			if punch == nil {
+				return domain.ErrPunchNotFound
+			}
+			punch.Time = correction.RequestedTime
+			if err := s.attendanceRepo.UpdatePunch(ctx, punch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *CorrectionService) markReviewed(correction *domain.AttendanceCorrection, status domain.CorrectionStatus, reviewerID uuid.UUID, note *string) {
+	now := time.Now()
+	correction.Status = status
+	correction.ReviewerID = &reviewerID
+	correction.ReviewNote = note
+	correction.ReviewedAt = &now
+}
+
+func (s *CorrectionService) notifyReviewed(correction *domain.AttendanceCorrection) {
+	result := "aprobada"
+	if correction.Status == domain.CorrectionRejected {
+		result = "rechazada"
+	}
+
+	subject := fmt.Sprintf("Tu solicitud de corrección fue %s", result)
+	body := fmt.Sprintf("Hola %s, tu solicitud de corrección de asistencia para el %s fue %s.", correction.User.FirstName, correction.Date.Format("2006-01-02"), result)
+	if correction.ReviewNote != nil {
+		body += fmt.Sprintf(" Nota: %s", *correction.ReviewNote)
+	}
+	s.notify(correction.User.Email, subject, body)
+}
+
+func (s *CorrectionService) notify(to string, subject string, body string) {
+	go func() {
+		err := s.notificator.PublishEmail(context.Background(), to, subject, body)
+		if err != nil {
+			log.Printf("Error sending email: %v", err)
+		}
+	}()
+}
+
+// findPunch devuelve un puntero a la marca dentro del slice para poder modificarla en su lugar
+func findPunch(punches []domain.AttendancePunch, id uuid.UUID) *domain.AttendancePunch {
+	for i := range punches {
+		if punches[i].ID == id {
+			return &punches[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"math"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/pkg/utils"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Sobre esta velocidad el desplazamiento entre dos marcas no es creíble (ni en auto ni en tren)
+	maxTravelSpeedKmh = 250.0
+	// Bajo esta distancia las diferencias se atribuyen al error normal del GPS
+	minTravelDistanceMeters = 1000.0
+	// Un GPS de teléfono no informa precisiones bajo ~1 m; las apps de ubicación falsa suelen informar 0
+	minPlausibleAccuracyMeters = 1.0
+	// Un fix real trae las coordenadas con toda la precisión del double; con 4 decimales o menos (~11 m)
+	// en ambos ejes fueron escritas a mano o generadas por una app de ubicación falsa
+	maxRoundedCoordinateDecimals = 4
+
+	// Puntaje desde el que un registro queda marcado para revisión
+	fraudFlagThreshold = 50
+	maxFraudScore      = 100
+)
+
+// fraudWeights: solo un viaje imposible basta para marcar el registro. Coordenadas repetidas
+// (quien marca siempre desde el mismo escritorio) o una precisión sospechosa (algunos equipos la redondean)
+// necesitan un segundo indicio.
+var fraudWeights = map[domain.FraudSignal]int{
+	domain.SignalImpossibleTravel:    60,
+	domain.SignalReusedCoordinates:   30,
+	domain.SignalSuspiciousPrecision: 30,
+}
+
+// FraudScorer evalúa si la ubicación de una marca es plausible comparándola con las marcas anteriores del usuario.
+type FraudScorer struct {
+	attendanceRepo domain.AttendanceRepo
+}
+
+func NewFraudScorer(attendanceRepo domain.AttendanceRepo) *FraudScorer {
+	return &FraudScorer{attendanceRepo: attendanceRepo}
+}
+
+// Assess devuelve los indicios de ubicación falsa de una marca con coordenadas.
+// dayStart es el inicio del día de la marca: solo las coordenadas de días anteriores cuentan como reutilizadas.
+func (f *FraudScorer) Assess(ctx context.Context, userID uuid.UUID, punch *domain.AttendancePunch, dayStart time.Time) ([]domain.FraudSignal, error) {
+	if punch.Latitude == nil || punch.Longitude == nil {
+		return nil, nil
+	}
+
+	var signals []domain.FraudSignal
+
+	previous, err := f.attendanceRepo.GetLastLocatedPunch(ctx, userID, punch.Time)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && impossibleTravel(previous, punch) {
+		signals = append(signals, domain.SignalImpossibleTravel)
+	}
+
+	reused, err := f.attendanceRepo.CountPunchesAtLocation(ctx, userID, *punch.Latitude, *punch.Longitude, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	if reused > 0 {
+		signals = append(signals, domain.SignalReusedCoordinates)
+	}
+
+	if suspiciousPrecision(punch) {
+		signals = append(signals, domain.SignalSuspiciousPrecision)
+	}
+
+	return signals, nil
+}
+
+// suspiciousPrecision detecta una precisión informada mejor que la de un GPS real o coordenadas redondeadas
+func suspiciousPrecision(punch *domain.AttendancePunch) bool {
+	if punch.Accuracy != nil && *punch.Accuracy < minPlausibleAccuracyMeters {
+		return true
+	}
+	return coordinateDecimals(*punch.Latitude) <= maxRoundedCoordinateDecimals &&
+		coordinateDecimals(*punch.Longitude) <= maxRoundedCoordinateDecimals
+}
+
+// coordinateDecimals cuenta los decimales de la representación más corta de v
+func coordinateDecimals(v float64) int {
+	_, decimals, _ := strings.Cut(strconv.FormatFloat(v, 'f', -1, 64), ".")
+	return len(decimals)
+}
+
+// impossibleTravel compara la velocidad necesaria para ir de una marca a la otra. El margen de precisión
+// de ambas ubicaciones se descuenta de la distancia para no castigar fixes imprecisos.
+func impossibleTravel(from *domain.AttendancePunch, to *domain.AttendancePunch) bool {
+	dist := utils.Haversine(*from.Latitude, *from.Longitude, *to.Latitude, *to.Longitude)
+	if from.Accuracy != nil {
+		dist -= *from.Accuracy
+	}
+	if to.Accuracy != nil {
+		dist -= *to.Accuracy
+	}
+	if dist < minTravelDistanceMeters {
+		return false
+	}
+
+	hours := math.Max(to.Time.Sub(from.Time).Hours(), time.Second.Hours())
+	return dist/1000/hours > maxTravelSpeedKmh
+}
+
+// flagAttendance acumula en el registro los indicios de una de sus marcas y lo marca para revisión
+// si el puntaje alcanza el umbral. Un registro marcado no se desmarca con marcas posteriores, y uno que
+// un admin ya resolvió solo vuelve a marcarse si aparece un indicio que no tenía.
+func flagAttendance(attendance *domain.Attendance, signals []domain.FraudSignal) {
+	added := false
+	for _, signal := range signals {
+		if !slices.Contains(attendance.FraudSignals, signal) {
+			attendance.FraudSignals = append(attendance.FraudSignals, signal)
+			added = true
+		}
+	}
+
+	score := 0
+	for _, signal := range attendance.FraudSignals {
+		score += fraudWeights[signal]
+	}
+	attendance.FraudScore = min(score, maxFraudScore)
+
+	if added && attendance.FraudScore >= fraudFlagThreshold {
+		attendance.FlaggedForReview = true
+	}
+}
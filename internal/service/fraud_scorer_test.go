@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeLocationRepo answers only the two queries the scorer makes
+type fakeLocationRepo struct {
+	domain.AttendanceRepo
+	last   *domain.AttendancePunch
+	reused int64
+}
+
+func (r *fakeLocationRepo) GetLastLocatedPunch(ctx context.Context, userID uuid.UUID, before time.Time) (*domain.AttendancePunch, error) {
+	return r.last, nil
+}
+
+func (r *fakeLocationRepo) CountPunchesAtLocation(ctx context.Context, userID uuid.UUID, lat float64, lng float64, before time.Time) (int64, error) {
+	return r.reused, nil
+}
+
+func locatedPunch(at time.Time, lat, lng, accuracy float64) *domain.AttendancePunch {
+	return &domain.AttendancePunch{Time: at, Latitude: &lat, Longitude: &lng, Accuracy: &accuracy}
+}
+
+func TestImpossibleTravel(t *testing.T) {
+	start := time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		to   *domain.AttendancePunch
+		want bool
+	}{
+		// Santiago to Valparaíso is about 100 km
+		{"same place", locatedPunch(start.Add(time.Minute), -33.448891, -70.669266, 10), false},
+		{"100 km in 10 minutes", locatedPunch(start.Add(10*time.Minute), -33.047238, -71.612688, 10), true},
+		{"100 km in 2 hours", locatedPunch(start.Add(2*time.Hour), -33.047238, -71.612688, 10), false},
+		{"jump within the accuracy", locatedPunch(start.Add(time.Second), -33.455, -70.669266, 500), false},
+		{"same instant far away", locatedPunch(start, -33.047238, -71.612688, 10), true},
+	}
+
+	from := locatedPunch(start, -33.448891, -70.669266, 10)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := impossibleTravel(from, tt.to); got != tt.want {
+				t.Errorf("impossibleTravel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuspiciousPrecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lng float64
+		accuracy float64
+		want     bool
+	}{
+		{"real fix", -33.44889123456789, -70.66926612345678, 8, false},
+		{"zero accuracy", -33.44889123456789, -70.66926612345678, 0, true},
+		{"sub-meter accuracy", -33.44889123456789, -70.66926612345678, 0.5, true},
+		{"rounded coordinates", -33.4489, -70.6693, 8, true},
+		{"integer coordinates", -33, -70, 8, true},
+		{"only one axis rounded", -33.4489, -70.66926612345678, 8, false},
+		{"five decimals", -33.44889, -70.66926, 8, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			punch := locatedPunch(time.Now(), tt.lat, tt.lng, tt.accuracy)
+			if got := suspiciousPrecision(punch); got != tt.want {
+				t.Errorf("suspiciousPrecision() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssess(t *testing.T) {
+	start := time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)
+	dayStart := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	far := locatedPunch(start.Add(-5*time.Minute), -33.047238, -71.612688, 10)
+
+	tests := []struct {
+		name  string
+		repo  *fakeLocationRepo
+		punch *domain.AttendancePunch
+		want  []domain.FraudSignal
+	}{
+		{"clean", &fakeLocationRepo{}, locatedPunch(start, -33.44889123456789, -70.66926612345678, 8), nil},
+		{"no coordinates", &fakeLocationRepo{last: far, reused: 3}, &domain.AttendancePunch{Time: start}, nil},
+		{
+			"every signal",
+			&fakeLocationRepo{last: far, reused: 1},
+			locatedPunch(start, -33.4489, -70.6693, 0),
+			[]domain.FraudSignal{domain.SignalImpossibleTravel, domain.SignalReusedCoordinates, domain.SignalSuspiciousPrecision},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFraudScorer(tt.repo).Assess(context.Background(), uuid.New(), tt.punch, dayStart)
+			if err != nil {
+				t.Fatalf("Assess() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Assess() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagAttendance(t *testing.T) {
+	tests := []struct {
+		name        string
+		attendance  domain.Attendance
+		signals     []domain.FraudSignal
+		wantScore   int
+		wantFlagged bool
+	}{
+		{"no signals", domain.Attendance{}, nil, 0, false},
+		{"reused coordinates alone", domain.Attendance{}, []domain.FraudSignal{domain.SignalReusedCoordinates}, 30, false},
+		{"suspicious precision alone", domain.Attendance{}, []domain.FraudSignal{domain.SignalSuspiciousPrecision}, 30, false},
+		{"impossible travel alone", domain.Attendance{}, []domain.FraudSignal{domain.SignalImpossibleTravel}, 60, true},
+		{
+			"reused coordinates and suspicious precision",
+			domain.Attendance{},
+			[]domain.FraudSignal{domain.SignalReusedCoordinates, domain.SignalSuspiciousPrecision},
+			60, true,
+		},
+		{
+			"second signal on a later punch",
+			domain.Attendance{FraudSignals: []domain.FraudSignal{domain.SignalReusedCoordinates}, FraudScore: 30},
+			[]domain.FraudSignal{domain.SignalSuspiciousPrecision},
+			60, true,
+		},
+		{
+			"repeated signal counts once",
+			domain.Attendance{FraudSignals: []domain.FraudSignal{domain.SignalReusedCoordinates}, FraudScore: 30},
+			[]domain.FraudSignal{domain.SignalReusedCoordinates},
+			30, false,
+		},
+		{
+			"score is capped",
+			domain.Attendance{},
+			[]domain.FraudSignal{domain.SignalImpossibleTravel, domain.SignalReusedCoordinates, domain.SignalSuspiciousPrecision},
+			100, true,
+		},
+		{
+			"flagged record stays flagged",
+			domain.Attendance{FraudSignals: []domain.FraudSignal{domain.SignalImpossibleTravel}, FraudScore: 60, FlaggedForReview: true},
+			nil,
+			60, true,
+		},
+		{
+			"resolved record without new signals",
+			domain.Attendance{FraudSignals: []domain.FraudSignal{domain.SignalImpossibleTravel}, FraudScore: 60},
+			[]domain.FraudSignal{domain.SignalImpossibleTravel},
+			60, false,
+		},
+		{
+			"resolved record with a new signal",
+			domain.Attendance{FraudSignals: []domain.FraudSignal{domain.SignalImpossibleTravel}, FraudScore: 60},
+			[]domain.FraudSignal{domain.SignalReusedCoordinates},
+			90, true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attendance := tt.attendance
+			flagAttendance(&attendance, tt.signals)
+			if attendance.FraudScore != tt.wantScore {
+				t.Errorf("FraudScore = %d, want %d", attendance.FraudScore, tt.wantScore)
+			}
+			if attendance.FlaggedForReview != tt.wantFlagged {
+				t.Errorf("FlaggedForReview = %v, want %v", attendance.FlaggedForReview, tt.wantFlagged)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"math"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/pkg/utils"
+)
+
+// locationRequest agrupa la ubicación informada por el dispositivo
+type locationRequest struct {
+	lat      *float64
+	lng      *float64
+	accuracy *float64
+}
+
+func (l locationRequest) radius() float64 {
+	if l.accuracy == nil {
+		return 0
+	}
+	return *l.accuracy
+}
+
+// checkAccuracy aplica el máximo de precisión de la agencia a una marca con ubicación.
+// Si la agencia no fija un máximo la precisión es opcional.
+func checkAccuracy(agency *domain.Agency, loc locationRequest) error {
+	if loc.lat == nil || loc.lng == nil || agency.MaxLocationAccuracyMeters <= 0 {
+		return nil
+	}
+	if loc.accuracy == nil {
+		return domain.ErrAccuracyRequired
+	}
+	if *loc.accuracy > float64(agency.MaxLocationAccuracyMeters) {
+		return domain.ErrLocationImprecise
+	}
+	return nil
+}
+
+// fenceGap devuelve cuántos metros del círculo de precisión quedan fuera de la geocerca según la estrictez.
+// signedDist es la distancia de la ubicación al borde: negativa si está dentro. Un resultado <= 0 es válido.
+func fenceGap(signedDist float64, accuracy float64, strictness domain.GeofenceStrictness) float64 {
+	if strictness == domain.GeofenceContain {
+		return signedDist + accuracy
+	}
+	return signedDist - accuracy
+}
+
+// siteDistance es la distancia con signo de la ubicación al borde de la geocerca de la sede
+func siteDistance(site *domain.Site, lat float64, lng float64) float64 {
+	if !site.IsPolygon() {
+		return utils.Haversine(site.Latitude, site.Longitude, lat, lng) - float64(site.RadiusMeters)
+	}
+
+	point := utils.Point{Lat: lat, Lng: lng}
+	polygon := make([]utils.Point, len(site.Polygon))
+	for i, v := range site.Polygon {
+		polygon[i] = utils.Point{Lat: v.Latitude, Lng: v.Longitude}
+	}
+
+	dist := utils.DistanceToPolygonEdge(point, polygon)
+	if utils.PointInPolygon(point, polygon) {
+		return -dist
+	}
+	return dist
+}
+
+// checkHomeFence valida una marca remota contra la geocerca circular del domicilio del usuario
+func checkHomeFence(agency *domain.Agency, user *domain.User, loc locationRequest) error {
+	dist := utils.Haversine(*user.HomeLatitude, *user.HomeLongitude, *loc.lat, *loc.lng) - float64(*user.HomeRadiusMeters)
+	if fenceGap(dist, loc.radius(), agency.GeofenceStrictness) > 0 {
+		return domain.ErrGeofenceViolation
+	}
+	return nil
+}
+
+// resolveSite busca la sede activa cuya geocerca contiene la ubicación de una marca presencial.
+// Sin coordenadas o fuera de toda sede solo es un error si la agencia exige marcar en sus sedes.
+func (s *AttendanceService) resolveSite(ctx context.Context, agency *domain.Agency, loc locationRequest) (*domain.Site, error) {
+	if loc.lat == nil || loc.lng == nil {
+		if agency.RequireOnSiteLocation {
+			return nil, domain.ErrLocationRequired
+		}
+		return nil, nil
+	}
+
+	active := true
+	sites, err := s.siteRepo.List(ctx, agency.ID, domain.SiteFilter{Active: &active})
+	if err != nil {
+		return nil, err
+	}
+	if len(sites) == 0 {
+		if agency.RequireOnSiteLocation {
+			return nil, domain.ErrNoSitesConfigured
+		}
+		return nil, nil
+	}
+
+	site, err := matchSite(sites, *loc.lat, *loc.lng, loc.radius(), agency.GeofenceStrictness)
+	if err != nil && !agency.RequireOnSiteLocation {
+		return nil, nil
+	}
+	return site, err
+}
+
+// matchSite devuelve la sede que acepta la ubicación (la que la contiene más holgadamente si hay varias).
+// Si ninguna la acepta devuelve *domain.OutsideSiteError con la sede más cercana y los metros que faltaron.
+func matchSite(sites []*domain.Site, lat float64, lng float64, accuracy float64, strictness domain.GeofenceStrictness) (*domain.Site, error) {
+	var best *domain.Site
+	bestGap := math.Inf(1)
+
+	for _, site := range sites {
+		gap := fenceGap(siteDistance(site, lat, lng), accuracy, strictness)
+		if gap < bestGap {
+			best, bestGap = site, gap
+		}
+	}
+
+	if bestGap <= 0 {
+		return best, nil
+	}
+	return nil, &domain.OutsideSiteError{
+		NearestSiteID:   best.ID,
+		NearestSiteName: best.Name,
+		DistanceMeters:  bestGap,
+	}
+}
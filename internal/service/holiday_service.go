@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"io"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/pkg/ical"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Un evento de varios días se expande a un feriado por día; más de un mes seguido no es un feriado
+	maxHolidayEventDays = 31
+	// Repeticiones que se expanden de un evento anual con COUNT o UNTIL
+	maxHolidayOccurrences = 50
+)
+
+type HolidayService struct {
+	holidayRepo domain.HolidayRepo
+	agencyRepo  domain.AgencyRepo
+	transactor  domain.Transactor
+}
+
+func NewHolidayService(holidayRepo domain.HolidayRepo, agencyRepo domain.AgencyRepo, transactor domain.Transactor) *HolidayService {
+	return &HolidayService{
+		holidayRepo: holidayRepo,
+		agencyRepo:  agencyRepo,
+		transactor:  transactor,
+	}
+}
+
+func (s *HolidayService) CreateHoliday(ctx context.Context, agencyID uuid.UUID, req *dto.CreateHolidayRequest) (*dto.HolidayResponse, error) {
+	// La columna es date: se guarda el día calendario tal cual, sin zona horaria
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, domain.ErrInvalidHoliday
+	}
+
+	holiday := &domain.Holiday{
+		AgencyID:  agencyID,
+		Date:      date,
+		Name:      req.Name,
+		Type:      req.Type,
+		Recurring: req.Recurring,
+	}
+	if holiday.Type == "" {
+		holiday.Type = domain.HolidayPublic
+	}
+
+	if err := s.holidayRepo.Create(ctx, holiday); err != nil {
+		return nil, err
+	}
+	return dto.ToHolidayResponse(holiday), nil
+}
+
+func (s *HolidayService) GetAgencyHolidays(ctx context.Context, agencyID uuid.UUID, params *dto.HolidayListParams) ([]*dto.HolidayResponse, error) {
+	holidays, err := s.holidayRepo.List(ctx, agencyID, domain.HolidayFilter{
+		Year:  params.Year,
+		Page:  params.Page,
+		Limit: params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.HolidayResponse, len(holidays))
+	for i, h := range holidays {
+		responses[i] = dto.ToHolidayResponse(h)
+	}
+	return responses, nil
+}
+
+func (s *HolidayService) DeleteHoliday(ctx context.Context, agencyID uuid.UUID, holidayID uuid.UUID) error {
+	holiday, err := s.holidayRepo.GetByID(ctx, holidayID)
+	if err != nil {
+		return err
+	}
+	if holiday.AgencyID != agencyID {
+		return domain.ErrHolidayNotFound
+	}
+	return s.holidayRepo.Delete(ctx, holidayID)
+}
+
+// ImportICS agrega al calendario los eventos de un archivo iCalendar. Los eventos con RRULE anual sin fin quedan
+// como feriados recurrentes, los acotados por COUNT o UNTIL se expanden año por año y los de varios días día por día.
+// Las fechas que ya están en el calendario se omiten, así que volver a importar el mismo archivo no duplica nada.
+func (s *HolidayService) ImportICS(ctx context.Context, agencyID uuid.UUID, holidayType domain.HolidayType, r io.Reader) (*dto.HolidayImportResponse, error) {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := ical.Parse(r, agency.Location())
+	if err != nil {
+		return nil, domain.ErrInvalidCalendar
+	}
+	if holidayType == "" {
+		holidayType = domain.HolidayPublic
+	}
+
+	existing, err := s.holidayRepo.List(ctx, agencyID, domain.HolidayFilter{})
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, h := range existing {
+		taken[h.Date.Format("2006-01-02")] = true
+	}
+
+	response := &dto.HolidayImportResponse{Holidays: []*dto.HolidayResponse{}}
+	var holidays []*domain.Holiday
+	for _, event := range events {
+		name := strings.TrimSpace(event.Summary)
+		if name == "" {
+			name = string(holidayType)
+		}
+
+		for _, start := range event.Occurrences(maxHolidayOccurrences) {
+			for _, day := range event.Days(start, maxHolidayEventDays) {
+				key := day.Format("2006-01-02")
+				if taken[key] {
+					response.Skipped++
+					continue
+				}
+				taken[key] = true
+
+				holidays = append(holidays, &domain.Holiday{
+					AgencyID:  agencyID,
+					Date:      day,
+					Name:      name,
+					Type:      holidayType,
+					Recurring: event.Recurring(),
+				})
+			}
+		}
+	}
+
+	var created []*domain.Holiday
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		created, err = s.holidayRepo.CreateBatch(txCtx, holidays)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Las fechas que otra importación agregó mientras tanto también cuentan como omitidas
+	response.Skipped += len(holidays) - len(created)
+	response.Imported = len(created)
+	for _, h := range created {
+		response.Holidays = append(response.Holidays, dto.ToHolidayResponse(h))
+	}
+	return response, nil
+}
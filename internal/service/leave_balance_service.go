@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"math"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Orden de los movimientos de un mismo día: primero la acumulación del mes que termina, luego el vencimiento
+// de lo arrastrado, el tope sobre el saldo del año que cierra y la asignación del año que empieza.
+// Los movimientos manuales van al final: un permiso que empieza en el aniversario ya usa el saldo nuevo.
+var leaveEntryRank = map[domain.LeaveEntryKind]int{
+	domain.LeaveEntryAccrual:      0,
+	domain.LeaveEntryExpiry:       1,
+	domain.LeaveEntryCarryOverCap: 2,
+	domain.LeaveEntryGrant:        3,
+	domain.LeaveEntryDeduction:    4,
+	domain.LeaveEntryAdjustment:   4,
+}
+
+// Una proyección más lejana no tiene sentido y obligaría a calcular décadas de movimientos
+const maxBalanceProjectionYears = 5
+
+type LeaveBalanceService struct {
+	balanceRepo   domain.LeaveBalanceRepo
+	leaveTypeRepo domain.LeaveTypeRepo
+	leaveRepo     domain.LeaveRequestRepo
+	userRepo      domain.UserRepo
+	agencyRepo    domain.AgencyRepo
+}
+
+func NewLeaveBalanceService(
+	balanceRepo domain.LeaveBalanceRepo,
+	leaveTypeRepo domain.LeaveTypeRepo,
+	leaveRepo domain.LeaveRequestRepo,
+	userRepo domain.UserRepo,
+	agencyRepo domain.AgencyRepo,
+) *LeaveBalanceService {
+	return &LeaveBalanceService{
+		balanceRepo:   balanceRepo,
+		leaveTypeRepo: leaveTypeRepo,
+		leaveRepo:     leaveRepo,
+		userRepo:      userRepo,
+		agencyRepo:    agencyRepo,
+	}
+}
+
+// AccrueAll guarda los movimientos automáticos vencidos hasta hoy (en la zona horaria de cada agencia)
+// para cada usuario activo y cada tipo de permiso con saldo. Es idempotente: los que ya existen se ignoran.
+func (s *LeaveBalanceService) AccrueAll(ctx context.Context, now time.Time) (int64, error) {
+	agencies, err := s.agencyRepo.ListActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var created int64
+	for _, agency := range agencies {
+		today := dateIn(now.In(agency.Location()), time.UTC)
+
+		leaveTypes, err := s.trackedTypes(ctx, agency.ID)
+		if err != nil {
+			return created, err
+		}
+		if len(leaveTypes) == 0 {
+			continue
+		}
+
+		users, err := s.userRepo.ListByAgencyID(ctx, agency.ID, domain.UserFilter{Status: string(domain.StatusActive)})
+		if err != nil {
+			return created, err
+		}
+
+		for _, user := range users {
+			for _, leaveType := range leaveTypes {
+				existing, err := s.entries(ctx, user.ID, leaveType.ID)
+				if err != nil {
+					return created, err
+				}
+
+				n, err := s.balanceRepo.CreateBatch(ctx, planLeaveEntries(leaveType, user, existing, today))
+				created += n
+				if err != nil {
+					return created, err
+				}
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// GetBalances devuelve el saldo de hoy y el proyectado a params.Date de cada tipo de permiso con saldo.
+// La proyección incluye los movimientos automáticos que todavía no se generaron y los permisos ya aprobados,
+// y llega a lo más maxBalanceProjectionYears años desde hoy.
+func (s *LeaveBalanceService) GetBalances(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, params *dto.LeaveBalanceParams) ([]*dto.LeaveBalanceResponse, error) {
+	user, err := s.getUser(ctx, agencyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	today := agencyToday(agency)
+
+	date := today
+	if params.Date != "" {
+		date, err = time.Parse("2006-01-02", params.Date)
+		if err != nil || date.After(today.AddDate(maxBalanceProjectionYears, 0, 0)) {
+			return nil, domain.ErrInvalidDateRange
+		}
+	}
+	until := date
+	if until.Before(today) {
+		until = today
+	}
+
+	leaveTypes, err := s.trackedTypes(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.LeaveBalanceResponse, 0, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		existing, err := s.entries(ctx, userID, leaveType.ID)
+		if err != nil {
+			return nil, err
+		}
+		all := append(existing, planLeaveEntries(leaveType, user, existing, until)...)
+		sortLeaveEntries(all)
+
+		pending, err := s.pendingDays(ctx, agencyID, userID, leaveType.ID, uuid.Nil)
+		if err != nil {
+			return nil, err
+		}
+
+		projection := []*dto.LeaveBalanceEntryResponse{}
+		for _, e := range all {
+			if e.Date.After(today) && !e.Date.After(date) {
+				projection = append(projection, dto.ToLeaveBalanceEntryResponse(e))
+			}
+		}
+
+		responses = append(responses, &dto.LeaveBalanceResponse{
+			LeaveType:        dto.ToLeaveTypeResponse(leaveType),
+			Balance:          balanceAt(all, today),
+			ProjectedBalance: balanceAt(all, date),
+			PendingDays:      pending,
+			Date:             date.Format("2006-01-02"),
+			Projection:       projection,
+		})
+	}
+	return responses, nil
+}
+
+// Ledger devuelve los movimientos guardados del usuario, del más reciente al más antiguo
+func (s *LeaveBalanceService) Ledger(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, params *dto.LeaveBalanceLedgerParams) ([]*dto.LeaveBalanceEntryResponse, error) {
+	if _, err := s.getUser(ctx, agencyID, userID); err != nil {
+		return nil, err
+	}
+
+	filter := domain.LeaveBalanceFilter{
+		UserID: userID,
+		Kind:   domain.LeaveEntryKind(params.Kind),
+		Page:   params.Page,
+		Limit:  params.Limit,
+	}
+	if params.LeaveTypeID != "" {
+		if id, err := uuid.Parse(params.LeaveTypeID); err == nil {
+			filter.LeaveTypeID = id
+		}
+	}
+
+	entries, err := s.balanceRepo.List(ctx, agencyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.LeaveBalanceEntryResponse, len(entries))
+	for i, e := range entries {
+		responses[i] = dto.ToLeaveBalanceEntryResponse(e)
+	}
+	return responses, nil
+}
+
+// Adjust registra un ajuste manual del saldo (ej: saldo inicial al activar el seguimiento, corrección de un error)
+func (s *LeaveBalanceService) Adjust(ctx context.Context, agencyID uuid.UUID, adminID uuid.UUID, req *dto.CreateLeaveAdjustmentRequest) (*dto.LeaveBalanceEntryResponse, error) {
+	leaveType, err := s.getTrackedType(ctx, agencyID, req.LeaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getUser(ctx, agencyID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	days := roundDays(req.Days)
+	note := strings.TrimSpace(req.Note)
+	if days == 0 || note == "" {
+		return nil, domain.ErrInvalidLeaveAdjustment
+	}
+
+	var date time.Time
+	if req.Date != "" {
+		date, err = time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, domain.ErrInvalidLeaveAdjustment
+		}
+	} else {
+		agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+		if err != nil {
+			return nil, err
+		}
+		date = agencyToday(agency)
+	}
+
+	entry := &domain.LeaveBalanceEntry{
+		AgencyID:    agencyID,
+		UserID:      req.UserID,
+		LeaveTypeID: leaveType.ID,
+		Date:        date,
+		Kind:        domain.LeaveEntryAdjustment,
+		Days:        days,
+		CreatedByID: &adminID,
+		Note:        &note,
+	}
+	if err := s.balanceRepo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveBalanceEntryResponse(entry), nil
+}
+
+// availableDays devuelve cuántos días puede usar el usuario desde date: el saldo proyectado a esa fecha,
+// menos lo ya descontado después (permisos aprobados más adelante) y lo comprometido en pending
+func (s *LeaveBalanceService) availableDays(ctx context.Context, leaveType *domain.LeaveType, user *domain.User, date time.Time, pending float64) (float64, error) {
+	existing, err := s.entries(ctx, user.ID, leaveType.ID)
+	if err != nil {
+		return 0, err
+	}
+	all := append(existing, planLeaveEntries(leaveType, user, existing, date)...)
+
+	available := balanceAt(all, date) - pending
+	for _, e := range existing {
+		if !e.Automatic() && e.Days < 0 && e.Date.After(date) {
+			available += e.Days
+		}
+	}
+	return roundDays(available), nil
+}
+
+// lockBalance bloquea los movimientos del usuario para el tipo hasta el fin de la transacción, así dos
+// solicitudes no comprometen el mismo saldo a la vez
+func (s *LeaveBalanceService) lockBalance(ctx context.Context, userID uuid.UUID, leaveTypeID uuid.UUID) error {
+	return s.balanceRepo.LockByUserAndType(ctx, userID, leaveTypeID)
+}
+
+// deduct descuenta del saldo los días de un permiso aprobado, en la fecha en que empieza
+func (s *LeaveBalanceService) deduct(ctx context.Context, leave *domain.LeaveRequest) error {
+	return s.balanceRepo.Create(ctx, &domain.LeaveBalanceEntry{
+		AgencyID:       leave.AgencyID,
+		UserID:         leave.UserID,
+		LeaveTypeID:    leave.LeaveTypeID,
+		Date:           leave.StartDate,
+		Kind:           domain.LeaveEntryDeduction,
+		Days:           -leave.Days,
+		LeaveRequestID: &leave.ID,
+	})
+}
+
+// pendingDays suma los días de las solicitudes pendientes del usuario para el tipo, sin contar exclude
+func (s *LeaveBalanceService) pendingDays(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, leaveTypeID uuid.UUID, exclude uuid.UUID) (float64, error) {
+	leaves, err := s.leaveRepo.List(ctx, agencyID, domain.LeaveRequestFilter{
+		UserID:      userID,
+		LeaveTypeID: leaveTypeID,
+		Status:      domain.LeavePending,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0.0
+	for _, l := range leaves {
+		if l.ID != exclude {
+			total += l.Days
+		}
+	}
+	return roundDays(total), nil
+}
+
+func (s *LeaveBalanceService) trackedTypes(ctx context.Context, agencyID uuid.UUID) ([]*domain.LeaveType, error) {
+	leaveTypes, err := s.leaveTypeRepo.List(ctx, agencyID, domain.LeaveTypeFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := make([]*domain.LeaveType, 0, len(leaveTypes))
+	for _, t := range leaveTypes {
+		if t.TrackBalance {
+			tracked = append(tracked, t)
+		}
+	}
+	return tracked, nil
+}
+
+func (s *LeaveBalanceService) getTrackedType(ctx context.Context, agencyID uuid.UUID, leaveTypeID uuid.UUID) (*domain.LeaveType, error) {
+	leaveType, err := s.leaveTypeRepo.GetByID(ctx, leaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if leaveType.AgencyID != agencyID {
+		return nil, domain.ErrLeaveTypeNotFound
+	}
+	if !leaveType.TrackBalance {
+		return nil, domain.ErrLeaveBalanceNotTracked
+	}
+	return leaveType, nil
+}
+
+func (s *LeaveBalanceService) getUser(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.AgencyID != agencyID {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// entries devuelve los movimientos guardados con las fechas normalizadas a medianoche UTC para compararlas
+func (s *LeaveBalanceService) entries(ctx context.Context, userID uuid.UUID, leaveTypeID uuid.UUID) ([]*domain.LeaveBalanceEntry, error) {
+	entries, err := s.balanceRepo.ListByUserAndType(ctx, userID, leaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		e.Date = dateIn(e.Date, time.UTC)
+	}
+	return entries, nil
+}
+
+// planLeaveEntries calcula los movimientos automáticos de la política de leaveType que faltan en existing
+// hasta until (inclusive). Todos caen en un "mes cumplido" desde la contratación:
+//   - monthly: AccrualDays al cumplirse cada mes.
+//   - anniversary: AccrualDays en cada aniversario.
+//   - En cada aniversario el saldo que supera CarryOverCapDays se descarta; el resto pasa al año nuevo.
+//   - Lo arrastrado que no se usó dentro de los primeros CarryOverExpiryMonths meses del año vence.
+//
+// No se generan movimientos antes de TrackedSince, pero los saldos anteriores sí cuentan para el tope.
+func planLeaveEntries(leaveType *domain.LeaveType, user *domain.User, existing []*domain.LeaveBalanceEntry, until time.Time) []*domain.LeaveBalanceEntry {
+	hire := user.ServiceStart()
+	from := hire
+	if leaveType.TrackedSince != nil && leaveType.TrackedSince.After(from) {
+		from = dateIn(*leaveType.TrackedSince, time.UTC)
+	}
+
+	all := append([]*domain.LeaveBalanceEntry{}, existing...)
+	var planned []*domain.LeaveBalanceEntry
+
+	exists := func(kind domain.LeaveEntryKind, date time.Time) bool {
+		for _, e := range all {
+			if e.Automatic() && e.Kind == kind && e.Date.Equal(date) {
+				return true
+			}
+		}
+		return false
+	}
+	// balanceBefore suma lo anterior a date y lo de date que va antes de kind
+	balanceBefore := func(date time.Time, kind domain.LeaveEntryKind) float64 {
+		total := 0.0
+		for _, e := range all {
+			if e.Date.Before(date) || (e.Date.Equal(date) && leaveEntryRank[e.Kind] < leaveEntryRank[kind]) {
+				total += e.Days
+			}
+		}
+		return roundDays(total)
+	}
+	add := func(kind domain.LeaveEntryKind, date time.Time, days float64) {
+		days = roundDays(days)
+		if days == 0 || date.Before(from) || exists(kind, date) {
+			return
+		}
+		entry := &domain.LeaveBalanceEntry{
+			AgencyID:    leaveType.AgencyID,
+			UserID:      user.ID,
+			LeaveTypeID: leaveType.ID,
+			Date:        date,
+			Kind:        kind,
+			Days:        days,
+		}
+		all = append(all, entry)
+		planned = append(planned, entry)
+	}
+
+	var yearStart time.Time
+	carried := 0.0
+	for m := 1; ; m++ {
+		date := addMonthsClamped(hire, m)
+		if date.After(until) {
+			break
+		}
+
+		if leaveType.AccrualType == domain.AccrualMonthly {
+			add(domain.LeaveEntryAccrual, date, leaveType.AccrualDays)
+		}
+
+		if leaveType.CarryOverExpiryMonths > 0 && m > 12 && m%12 == leaveType.CarryOverExpiryMonths {
+			// Lo que se usó desde el aniversario sale primero de lo arrastrado
+			remaining := carried
+			for _, e := range all {
+				if !e.Automatic() && e.Days < 0 && !e.Date.Before(yearStart) && e.Date.Before(date) {
+					remaining += e.Days
+				}
+			}
+			remaining = min(remaining, balanceBefore(date, domain.LeaveEntryExpiry))
+			if remaining > 0 {
+				add(domain.LeaveEntryExpiry, date, -remaining)
+			}
+		}
+
+		if m%12 == 0 {
+			if leaveType.CarryOverCapDays != nil {
+				if excess := balanceBefore(date, domain.LeaveEntryCarryOverCap) - *leaveType.CarryOverCapDays; excess > 0 {
+					add(domain.LeaveEntryCarryOverCap, date, -excess)
+				}
+			}
+			yearStart = date
+			carried = max(balanceBefore(date, domain.LeaveEntryGrant), 0)
+
+			if leaveType.AccrualType == domain.AccrualAnniversary {
+				add(domain.LeaveEntryGrant, date, leaveType.AccrualDays)
+			}
+		}
+	}
+
+	return planned
+}
+
+// addMonthsClamped suma months a date sin desbordar al mes siguiente: un 31 pasa al último día de los meses cortos
+func addMonthsClamped(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(date.Day(), lastDay), 0, 0, 0, 0, time.UTC)
+}
+
+// balanceAt suma los movimientos hasta date inclusive
+func balanceAt(entries []*domain.LeaveBalanceEntry, date time.Time) float64 {
+	total := 0.0
+	for _, e := range entries {
+		if !e.Date.After(date) {
+			total += e.Days
+		}
+	}
+	return roundDays(total)
+}
+
+func sortLeaveEntries(entries []*domain.LeaveBalanceEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return leaveEntryRank[entries[i].Kind] < leaveEntryRank[entries[j].Kind]
+	})
+}
+
+// roundDays redondea a centésimas para que las sumas de fracciones (ej: 1.25 por mes) no acumulen error
+func roundDays(days float64) float64 {
+	return math.Round(days*100) / 100
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"quickattendance-go/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPlanLeaveEntries(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	ptr := func(v float64) *float64 { return &v }
+	userID := uuid.New()
+	hired := func(date time.Time) *domain.User {
+		return &domain.User{ID: userID, HireDate: &date}
+	}
+	used := func(date time.Time, days float64) *domain.LeaveBalanceEntry {
+		id := uuid.New()
+		return &domain.LeaveBalanceEntry{UserID: userID, Date: date, Kind: domain.LeaveEntryDeduction, Days: -days, LeaveRequestID: &id}
+	}
+
+	tests := []struct {
+		name      string
+		leaveType domain.LeaveType
+		user      *domain.User
+		existing  []*domain.LeaveBalanceEntry
+		until     time.Time
+		want      []string
+	}{
+		{
+			name:      "monthly accrual clamps to the end of short months",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualMonthly, AccrualDays: 1.25},
+			user:      hired(day(2025, 1, 31)),
+			until:     day(2025, 4, 30),
+			want:      []string{"2025-02-28 accrual 1.25", "2025-03-31 accrual 1.25", "2025-04-30 accrual 1.25"},
+		},
+		{
+			name:      "nothing before the first month",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualMonthly, AccrualDays: 1.25},
+			user:      hired(day(2025, 1, 10)),
+			until:     day(2025, 2, 9),
+			want:      nil,
+		},
+		{
+			name:      "anniversary grants",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualAnniversary, AccrualDays: 15},
+			user:      hired(day(2024, 3, 10)),
+			until:     day(2026, 3, 10),
+			want:      []string{"2025-03-10 grant 15", "2026-03-10 grant 15"},
+		},
+		{
+			name:      "carry-over cap before the new grant",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualAnniversary, AccrualDays: 15, CarryOverCapDays: ptr(5)},
+			user:      hired(day(2024, 3, 10)),
+			until:     day(2026, 3, 10),
+			want:      []string{"2025-03-10 grant 15", "2026-03-10 carry_over_cap -10", "2026-03-10 grant 15"},
+		},
+		{
+			name:      "carry-over cap counts leave already used",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualAnniversary, AccrualDays: 15, CarryOverCapDays: ptr(5)},
+			user:      hired(day(2024, 3, 10)),
+			existing:  []*domain.LeaveBalanceEntry{used(day(2025, 8, 1), 12)},
+			until:     day(2026, 3, 10),
+			want:      []string{"2025-03-10 grant 15", "2026-03-10 grant 15"},
+		},
+		{
+			name:      "unused carry-over expires",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualAnniversary, AccrualDays: 15, CarryOverExpiryMonths: 3},
+			user:      hired(day(2024, 3, 10)),
+			until:     day(2026, 6, 10),
+			want:      []string{"2025-03-10 grant 15", "2026-03-10 grant 15", "2026-06-10 expiry -15"},
+		},
+		{
+			name:      "leave used after the anniversary comes out of the carry-over first",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualAnniversary, AccrualDays: 15, CarryOverExpiryMonths: 3},
+			user:      hired(day(2024, 3, 10)),
+			existing:  []*domain.LeaveBalanceEntry{used(day(2026, 4, 1), 4)},
+			until:     day(2026, 6, 10),
+			want:      []string{"2025-03-10 grant 15", "2026-03-10 grant 15", "2026-06-10 expiry -11"},
+		},
+		{
+			name:      "no entries before tracking started",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualMonthly, AccrualDays: 1, TrackedSince: func() *time.Time { d := day(2025, 4, 1); return &d }()},
+			user:      hired(day(2025, 1, 10)),
+			until:     day(2025, 6, 10),
+			want:      []string{"2025-04-10 accrual 1", "2025-05-10 accrual 1", "2025-06-10 accrual 1"},
+		},
+		{
+			name:      "existing automatic entries are not planned again",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualMonthly, AccrualDays: 1},
+			user:      hired(day(2025, 1, 10)),
+			existing:  []*domain.LeaveBalanceEntry{{UserID: userID, Date: day(2025, 2, 10), Kind: domain.LeaveEntryAccrual, Days: 1}},
+			until:     day(2025, 3, 10),
+			want:      []string{"2025-03-10 accrual 1"},
+		},
+		{
+			name:      "no accrual policy",
+			leaveType: domain.LeaveType{AccrualType: domain.AccrualNone},
+			user:      hired(day(2024, 3, 10)),
+			until:     day(2026, 3, 10),
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planned := planLeaveEntries(&tt.leaveType, tt.user, tt.existing, tt.until)
+
+			got := make([]string, len(planned))
+			for i, e := range planned {
+				got[i] = fmt.Sprintf("%s %s %g", e.Date.Format("2006-01-02"), e.Kind, e.Days)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("planLeaveEntries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddMonthsClamped(t *testing.T) {
+	tests := []struct {
+		date   string
+		months int
+		want   string
+	}{
+		{"2025-01-31", 1, "2025-02-28"},
+		{"2024-01-31", 1, "2024-02-29"},
+		{"2025-01-31", 2, "2025-03-31"},
+		{"2025-08-31", 1, "2025-09-30"},
+		{"2024-02-29", 12, "2025-02-28"},
+		{"2025-11-15", 3, "2026-02-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s+%d", tt.date, tt.months), func(t *testing.T) {
+			date, _ := time.Parse("2006-01-02", tt.date)
+			if got := addMonthsClamped(date, tt.months).Format("2006-01-02"); got != tt.want {
+				t.Errorf("addMonthsClamped() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,590 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Una solicitud cubre a lo más un año; periodos más largos se piden por separado
+const maxLeaveDays = 366
+
+type LeaveService struct {
+	leaveTypeRepo  domain.LeaveTypeRepo
+	leaveRepo      domain.LeaveRequestRepo
+	attendanceRepo domain.AttendanceRepo
+	userRepo       domain.UserRepo
+	agencyRepo     domain.AgencyRepo
+	attendanceSvc  *AttendanceService
+	scheduleSvc    *ScheduleService
+	balanceSvc     *LeaveBalanceService
+	timeBankSvc    *TimeBankService
+	notificator    domain.NotificationProvider
+	transactor     domain.Transactor
+}
+
+func NewLeaveService(
+	leaveTypeRepo domain.LeaveTypeRepo,
+	leaveRepo domain.LeaveRequestRepo,
+	attendanceRepo domain.AttendanceRepo,
+	userRepo domain.UserRepo,
+	agencyRepo domain.AgencyRepo,
+	attendanceSvc *AttendanceService,
+	scheduleSvc *ScheduleService,
+	balanceSvc *LeaveBalanceService,
+	timeBankSvc *TimeBankService,
+	notificator domain.NotificationProvider,
+	transactor domain.Transactor,
+) *LeaveService {
+	return &LeaveService{
+		leaveTypeRepo:  leaveTypeRepo,
+		leaveRepo:      leaveRepo,
+		attendanceRepo: attendanceRepo,
+		userRepo:       userRepo,
+		agencyRepo:     agencyRepo,
+		attendanceSvc:  attendanceSvc,
+		scheduleSvc:    scheduleSvc,
+		balanceSvc:     balanceSvc,
+		timeBankSvc:    timeBankSvc,
+		notificator:    notificator,
+		transactor:     transactor,
+	}
+}
+
+func (s *LeaveService) CreateLeaveType(ctx context.Context, agencyID uuid.UUID, req *dto.CreateLeaveTypeRequest) (*dto.LeaveTypeResponse, error) {
+	leaveType := &domain.LeaveType{
+		AgencyID:              agencyID,
+		Name:                  req.Name,
+		Paid:                  true,
+		Active:                true,
+		AccrualType:           domain.AccrualNone,
+		AccrualDays:           req.AccrualDays,
+		CarryOverCapDays:      req.CarryOverCapDays,
+		CarryOverExpiryMonths: req.CarryOverExpiryMonths,
+		UsesTimeBank:          req.UsesTimeBank,
+	}
+	if req.Paid != nil {
+		leaveType.Paid = *req.Paid
+	}
+	if req.AccrualType != "" {
+		leaveType.AccrualType = req.AccrualType
+	}
+	if req.TrackBalance {
+		if err := s.startTracking(ctx, leaveType); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.leaveTypeRepo.Create(ctx, leaveType); err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveTypeResponse(leaveType), nil
+}
+
+func (s *LeaveService) GetLeaveTypes(ctx context.Context, agencyID uuid.UUID, params *dto.LeaveTypeListParams) ([]*dto.LeaveTypeResponse, error) {
+	leaveTypes, err := s.leaveTypeRepo.List(ctx, agencyID, domain.LeaveTypeFilter{Active: params.Active})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.LeaveTypeResponse, len(leaveTypes))
+	for i, t := range leaveTypes {
+		responses[i] = dto.ToLeaveTypeResponse(t)
+	}
+	return responses, nil
+}
+
+func (s *LeaveService) UpdateLeaveType(ctx context.Context, agencyID uuid.UUID, leaveTypeID uuid.UUID, req *dto.UpdateLeaveTypeRequest) (*dto.LeaveTypeResponse, error) {
+	leaveType, err := s.leaveTypeRepo.GetByID(ctx, leaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if leaveType.AgencyID != agencyID {
+		return nil, domain.ErrLeaveTypeNotFound
+	}
+
+	if req.Name != nil {
+		leaveType.Name = *req.Name
+	}
+	if req.Paid != nil {
+		leaveType.Paid = *req.Paid
+	}
+	if req.Active != nil {
+		leaveType.Active = *req.Active
+	}
+	if req.AccrualType != nil {
+		leaveType.AccrualType = *req.AccrualType
+	}
+	if req.AccrualDays != nil {
+		leaveType.AccrualDays = *req.AccrualDays
+	}
+	if req.CarryOverCapDays != nil {
+		leaveType.CarryOverCapDays = req.CarryOverCapDays
+	}
+	if req.RemoveCarryOverCap {
+		leaveType.CarryOverCapDays = nil
+	}
+	if req.CarryOverExpiryMonths != nil {
+		leaveType.CarryOverExpiryMonths = *req.CarryOverExpiryMonths
+	}
+	if req.UsesTimeBank != nil {
+		leaveType.UsesTimeBank = *req.UsesTimeBank
+	}
+	if req.TrackBalance != nil && *req.TrackBalance != leaveType.TrackBalance {
+		if *req.TrackBalance {
+			if err := s.startTracking(ctx, leaveType); err != nil {
+				return nil, err
+			}
+		} else {
+			leaveType.TrackBalance = false
+		}
+	}
+
+	if err := s.leaveTypeRepo.Update(ctx, leaveType); err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveTypeResponse(leaveType), nil
+}
+
+// startTracking activa el saldo del tipo desde hoy: la política no genera movimientos de fechas anteriores,
+// así que el saldo inicial de cada empleado se carga con un ajuste
+func (s *LeaveService) startTracking(ctx context.Context, leaveType *domain.LeaveType) error {
+	agency, err := s.agencyRepo.GetByID(ctx, leaveType.AgencyID)
+	if err != nil {
+		return err
+	}
+	today := agencyToday(agency)
+	leaveType.TrackBalance = true
+	leaveType.TrackedSince = &today
+	return nil
+}
+
+// Submit registra la solicitud de permiso de un empleado y avisa a los administradores de la agencia.
+// No puede cruzarse con otra solicitud pendiente o aprobada, salvo dos medios días distintos de la misma fecha.
+// Si el tipo lleva saldo, los días hábiles que cubre no pueden superar el disponible menos lo ya pedido;
+// si es tiempo compensatorio, lo mismo con los minutos programados y la bolsa de horas.
+func (s *LeaveService) Submit(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, req *dto.CreateLeaveRequest) (*dto.LeaveRequestResponse, error) {
+	leaveType, err := s.leaveTypeRepo.GetByID(ctx, req.LeaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if leaveType.AgencyID != agencyID {
+		return nil, domain.ErrLeaveTypeNotFound
+	}
+	if !leaveType.Active {
+		return nil, domain.ErrLeaveTypeInactive
+	}
+
+	// Las columnas son date: se guardan los días calendario tal cual, sin zona horaria
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, domain.ErrInvalidLeaveRequest
+	}
+	end := start
+	if req.EndDate != "" {
+		end, err = time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return nil, domain.ErrInvalidLeaveRequest
+		}
+	}
+	if end.Before(start) || end.Sub(start) >= maxLeaveDays*24*time.Hour {
+		return nil, domain.ErrInvalidDateRange
+	}
+	if req.HalfDay != domain.LeaveFullDay && !end.Equal(start) {
+		return nil, domain.ErrInvalidLeaveRequest
+	}
+
+	// Las solicitudes del usuario se serializan bloqueando su fila: dos envíos simultáneos no pueden
+	// pasar ambos la validación de cruce ni la de saldo antes de que el otro se registre
+	var leave *domain.LeaveRequest
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		user, err := s.userRepo.GetByIDForUpdate(txCtx, userID)
+		if err != nil {
+			return err
+		}
+
+		existing, err := s.leaveRepo.ListActiveOverlapping(txCtx, userID, start, end)
+		if err != nil {
+			return err
+		}
+		for _, other := range existing {
+			if req.HalfDay == domain.LeaveFullDay || other.HalfDay == domain.LeaveFullDay || other.HalfDay == req.HalfDay {
+				return domain.ErrLeaveOverlap
+			}
+		}
+
+		agency, err := s.agencyRepo.GetByID(txCtx, agencyID)
+		if err != nil {
+			return err
+		}
+		days, minutes, err := s.countLeave(txCtx, agency, userID, start, end, req.HalfDay)
+		if err != nil {
+			return err
+		}
+		if leaveType.TrackBalance {
+			if err := s.balanceSvc.lockBalance(txCtx, userID, leaveType.ID); err != nil {
+				return err
+			}
+			pending, err := s.balanceSvc.pendingDays(txCtx, agencyID, userID, leaveType.ID, uuid.Nil)
+			if err != nil {
+				return err
+			}
+			available, err := s.balanceSvc.availableDays(txCtx, leaveType, user, start, pending)
+			if err != nil {
+				return err
+			}
+			if days > available {
+				return domain.ErrInsufficientLeaveBalance
+			}
+		}
+		if leaveType.UsesTimeBank {
+			if !agency.TimeBankEnabled {
+				return domain.ErrTimeBankDisabled
+			}
+			if err := s.timeBankSvc.lockBank(txCtx, userID); err != nil {
+				return err
+			}
+			pending, err := s.timeBankSvc.pendingMinutes(txCtx, agencyID, userID, uuid.Nil)
+			if err != nil {
+				return err
+			}
+			available, err := s.timeBankSvc.availableMinutes(txCtx, agency, userID, pending)
+			if err != nil {
+				return err
+			}
+			if minutes > available {
+				return domain.ErrInsufficientTimeBank
+			}
+		}
+
+		leave = &domain.LeaveRequest{
+			AgencyID:    agencyID,
+			UserID:      userID,
+			LeaveTypeID: leaveType.ID,
+			LeaveType:   *leaveType,
+			StartDate:   start,
+			EndDate:     end,
+			HalfDay:     req.HalfDay,
+			Days:        days,
+			Minutes:     minutes,
+			Reason:      req.Reason,
+			Status:      domain.LeavePending,
+		}
+		return s.leaveRepo.Create(txCtx, leave)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	admins, err := s.userRepo.ListByAgencyID(ctx, agencyID, domain.UserFilter{Status: string(domain.StatusActive), Role: domain.RoleAdmin})
+	if err != nil {
+		log.Printf("Error listing admins for leave request %s: %v", leave.ID, err)
+	}
+	for _, admin := range admins {
+		subject := "Nueva solicitud de permiso"
+		body := fmt.Sprintf("Hola %s, hay una nueva solicitud de %s %s pendiente de revisión. Motivo: %s", admin.FirstName, leaveType.Name, describeLeavePeriod(leave), leave.Reason)
+		s.notify(admin.Email, subject, body)
+	}
+
+	return dto.ToLeaveRequestResponse(leave), nil
+}
+
+func (s *LeaveService) List(ctx context.Context, agencyID uuid.UUID, params *dto.LeaveRequestListParams) ([]*dto.LeaveRequestResponse, error) {
+	filter := domain.LeaveRequestFilter{
+		Status: domain.LeaveStatus(params.Status),
+		Page:   params.Page,
+		Limit:  params.Limit,
+	}
+	if params.UserID != "" {
+		if id, err := uuid.Parse(params.UserID); err == nil {
+			filter.UserID = id
+		}
+	}
+	if params.LeaveTypeID != "" {
+		if id, err := uuid.Parse(params.LeaveTypeID); err == nil {
+			filter.LeaveTypeID = id
+		}
+	}
+	if params.StartDate != "" {
+		if t, err := time.Parse("2006-01-02", params.StartDate); err == nil {
+			filter.StartDate = &t
+		}
+	}
+	if params.EndDate != "" {
+		if t, err := time.Parse("2006-01-02", params.EndDate); err == nil {
+			filter.EndDate = &t
+		}
+	}
+
+	leaves, err := s.leaveRepo.List(ctx, agencyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.LeaveRequestResponse, len(leaves))
+	for i, l := range leaves {
+		responses[i] = dto.ToLeaveRequestResponse(l)
+	}
+	return responses, nil
+}
+
+// Approve aprueba el permiso: desde ese momento los días que cubre no se esperan trabajados.
+// Las ausencias que el job ya había registrado esos días se anulan y quedan en el historial de la asistencia.
+// Los días se recalculan con los horarios vigentes y, si el tipo lleva saldo, se descuentan en la fecha de inicio.
+// Un permiso compensatorio descuenta sus minutos de la bolsa de horas el día en que se aprueba.
+func (s *LeaveService) Approve(ctx context.Context, agencyID uuid.UUID, reviewerID uuid.UUID, leaveID uuid.UUID, req *dto.ReviewLeaveRequest) (*dto.LeaveRequestResponse, error) {
+	var leave *domain.LeaveRequest
+
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		leave, err = s.getPending(txCtx, agencyID, leaveID)
+		if err != nil {
+			return err
+		}
+		// Como en Submit, la fila del usuario serializa sus solicitudes aunque todavía no tenga movimientos
+		if _, err := s.userRepo.GetByIDForUpdate(txCtx, leave.UserID); err != nil {
+			return err
+		}
+
+		agency, err := s.agencyRepo.GetByID(txCtx, agencyID)
+		if err != nil {
+			return err
+		}
+		leave.Days, leave.Minutes, err = s.countLeave(txCtx, agency, leave.UserID, leave.StartDate, leave.EndDate, leave.HalfDay)
+		if err != nil {
+			return err
+		}
+		if leave.LeaveType.TrackBalance && leave.Days > 0 {
+			if err := s.balanceSvc.lockBalance(txCtx, leave.UserID, leave.LeaveTypeID); err != nil {
+				return err
+			}
+			available, err := s.balanceSvc.availableDays(txCtx, &leave.LeaveType, &leave.User, leave.StartDate, 0)
+			if err != nil {
+				return err
+			}
+			if leave.Days > available {
+				return domain.ErrInsufficientLeaveBalance
+			}
+			if err := s.balanceSvc.deduct(txCtx, leave); err != nil {
+				return err
+			}
+		}
+		if leave.LeaveType.UsesTimeBank && leave.Minutes > 0 {
+			if !agency.TimeBankEnabled {
+				return domain.ErrTimeBankDisabled
+			}
+			if err := s.timeBankSvc.lockBank(txCtx, leave.UserID); err != nil {
+				return err
+			}
+			available, err := s.timeBankSvc.availableMinutes(txCtx, agency, leave.UserID, 0)
+			if err != nil {
+				return err
+			}
+			if leave.Minutes > available {
+				return domain.ErrInsufficientTimeBank
+			}
+			if err := s.timeBankSvc.debitCompTime(txCtx, agency, leave); err != nil {
+				return err
+			}
+		}
+
+		if leave.HalfDay == domain.LeaveFullDay {
+			if err := s.voidAbsences(txCtx, reviewerID, leave); err != nil {
+				return err
+			}
+		}
+
+		s.markReviewed(leave, domain.LeaveApproved, reviewerID, req.Note)
+		return s.leaveRepo.Update(txCtx, leave)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyReviewed(leave)
+	return dto.ToLeaveRequestResponse(leave), nil
+}
+
+func (s *LeaveService) Reject(ctx context.Context, agencyID uuid.UUID, reviewerID uuid.UUID, leaveID uuid.UUID, req *dto.ReviewLeaveRequest) (*dto.LeaveRequestResponse, error) {
+	var leave *domain.LeaveRequest
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		leave, err = s.getPending(txCtx, agencyID, leaveID)
+		if err != nil {
+			return err
+		}
+
+		s.markReviewed(leave, domain.LeaveRejected, reviewerID, req.Note)
+		return s.leaveRepo.Update(txCtx, leave)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyReviewed(leave)
+	return dto.ToLeaveRequestResponse(leave), nil
+}
+
+// Cancel retira una solicitud propia que todavía no fue revisada
+func (s *LeaveService) Cancel(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, leaveID uuid.UUID) (*dto.LeaveRequestResponse, error) {
+	var leave *domain.LeaveRequest
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		leave, err = s.getPending(txCtx, agencyID, leaveID)
+		if err != nil {
+			return err
+		}
+		if leave.UserID != userID {
+			return domain.ErrLeaveRequestNotFound
+		}
+
+		leave.Status = domain.LeaveCancelled
+		return s.leaveRepo.Update(txCtx, leave)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveRequestResponse(leave), nil
+}
+
+// getPending bloquea la solicitud hasta el fin de la transacción: una aprobación, un rechazo o una
+// cancelación simultáneos se serializan y el segundo la encuentra ya revisada
+func (s *LeaveService) getPending(ctx context.Context, agencyID uuid.UUID, leaveID uuid.UUID) (*domain.LeaveRequest, error) {
+	leave, err := s.leaveRepo.GetByIDForUpdate(ctx, leaveID)
+	if err != nil {
+		return nil, err
+	}
+	if leave.AgencyID != agencyID {
+		return nil, domain.ErrLeaveRequestNotFound
+	}
+	if leave.Status != domain.LeavePending {
+		return nil, domain.ErrLeaveNotPending
+	}
+	return leave, nil
+}
+
+// countLeave cuenta los días del rango en que el usuario tiene turno y los minutos programados en ellos;
+// feriados, días libres y días sin horario no consumen permiso. Un medio día cuenta 0.5 y la mitad del turno.
+func (s *LeaveService) countLeave(ctx context.Context, agency *domain.Agency, userID uuid.UUID, start time.Time, end time.Time, half domain.LeaveHalf) (float64, int, error) {
+	loc := agency.Location()
+
+	days, minutes := 0, 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		sched, err := s.scheduleSvc.GetApplicableSchedule(ctx, agency.ID, userID, dateIn(day, loc))
+		if errors.Is(err, domain.ErrNoScheduleFound) || errors.Is(err, domain.ErrNonWorkingDay) {
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		days++
+
+		length := scheduledLength(sched)
+		// Si la otra mitad del día ya es permiso, el horario resuelto es la mitad que queda
+		if half != domain.LeaveFullDay && sched.Leave == nil {
+			length /= 2
+		}
+		minutes += length
+	}
+
+	if half != domain.LeaveFullDay {
+		return float64(days) / 2, minutes, nil
+	}
+	return float64(days), minutes, nil
+}
+
+// voidAbsences anula las ausencias generadas por el sistema en los días del permiso
+func (s *LeaveService) voidAbsences(ctx context.Context, reviewerID uuid.UUID, leave *domain.LeaveRequest) error {
+	absences, err := s.attendanceRepo.List(ctx, leave.AgencyID, domain.AttendanceFilter{
+		UserID:      leave.UserID,
+		StartDate:   &leave.StartDate,
+		EndDate:     &leave.EndDate,
+		EntryStatus: domain.StatusAbsent,
+	})
+	if err != nil {
+		return err
+	}
+
+	reason := fmt.Sprintf("Permiso aprobado: %s", leave.LeaveType.Name)
+	for _, absence := range absences {
+		if absence.MethodIn != domain.MethodSystem {
+			continue
+		}
+		if err := s.attendanceSvc.checkUnlocked(ctx, absence.UserID, absence.Date); err != nil {
+			return err
+		}
+
+		revision := &domain.AttendanceRevision{
+			ChangedByID:    reviewerID,
+			Action:         domain.RevisionLeaveApproved,
+			Reason:         reason,
+			LeaveRequestID: &leave.ID,
+		}
+		snapshotPrevious(revision, absence)
+
+		now := time.Now()
+		absence.VoidedAt = &now
+		absence.VoidedByID = &reviewerID
+		absence.VoidReason = &reason
+
+		if err := s.attendanceRepo.Update(ctx, absence); err != nil {
+			return err
+		}
+		if err := s.attendanceSvc.logRevision(ctx, absence, revision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *LeaveService) markReviewed(leave *domain.LeaveRequest, status domain.LeaveStatus, reviewerID uuid.UUID, note *string) {
+	now := time.Now()
+	leave.Status = status
+	leave.ReviewerID = &reviewerID
+	leave.ReviewNote = note
+	leave.ReviewedAt = &now
+}
+
+func (s *LeaveService) notifyReviewed(leave *domain.LeaveRequest) {
+	result := "aprobada"
+	if leave.Status == domain.LeaveRejected {
+		result = "rechazada"
+	}
+
+	subject := fmt.Sprintf("Tu solicitud de permiso fue %s", result)
+	body := fmt.Sprintf("Hola %s, tu solicitud de %s %s fue %s.", leave.User.FirstName, leave.LeaveType.Name, describeLeavePeriod(leave), result)
+	if leave.ReviewNote != nil {
+		body += fmt.Sprintf(" Nota: %s", *leave.ReviewNote)
+	}
+	s.notify(leave.User.Email, subject, body)
+}
+
+func (s *LeaveService) notify(to string, subject string, body string) {
+	go func() {
+		err := s.notificator.PublishEmail(context.Background(), to, subject, body)
+		if err != nil {
+			log.Printf("Error sending email: %v", err)
+		}
+	}()
+}
+
+// describeLeavePeriod arma el texto del periodo para los correos (ej: "del 2026-01-05 al 2026-01-09")
+func describeLeavePeriod(leave *domain.LeaveRequest) string {
+	start := leave.StartDate.Format("2006-01-02")
+	switch {
+	case leave.HalfDay == domain.LeaveMorning:
+		return fmt.Sprintf("para la mañana del %s", start)
+	case leave.HalfDay == domain.LeaveAfternoon:
+		return fmt.Sprintf("para la tarde del %s", start)
+	case leave.EndDate.Equal(leave.StartDate):
+		return fmt.Sprintf("para el %s", start)
+	default:
+		return fmt.Sprintf("del %s al %s", start, leave.EndDate.Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NFCTagService struct {
+	nfcTagRepo domain.NFCTagRepo
+	siteRepo   domain.SiteRepo
+	agencyRepo domain.AgencyRepo
+}
+
+func NewNFCTagService(nfcTagRepo domain.NFCTagRepo, siteRepo domain.SiteRepo, agencyRepo domain.AgencyRepo) *NFCTagService {
+	return &NFCTagService{
+		nfcTagRepo: nfcTagRepo,
+		siteRepo:   siteRepo,
+		agencyRepo: agencyRepo,
+	}
+}
+
+func (s *NFCTagService) CreateTag(ctx context.Context, agencyID uuid.UUID, req *dto.CreateNFCTagRequest) (*dto.NFCTagResponse, error) {
+	uid := normalizeTagUID(req.UID)
+	if uid == "" {
+		return nil, domain.ErrNFCTagRequired
+	}
+
+	tag := &domain.NFCTag{
+		AgencyID: agencyID,
+		UID:      uid,
+		Label:    req.Label,
+		Active:   true,
+	}
+	if req.SiteID != nil {
+		if err := s.setSite(ctx, agencyID, tag, *req.SiteID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.nfcTagRepo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+	return dto.ToNFCTagResponse(tag), nil
+}
+
+func (s *NFCTagService) GetAgencyTags(ctx context.Context, agencyID uuid.UUID, params *dto.NFCTagListParams) ([]*dto.NFCTagResponse, error) {
+	tags, err := s.nfcTagRepo.List(ctx, agencyID, domain.NFCTagFilter{
+		Active: params.Active,
+		Page:   params.Page,
+		Limit:  params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.NFCTagResponse, len(tags))
+	for i, t := range tags {
+		responses[i] = dto.ToNFCTagResponse(t)
+	}
+	return responses, nil
+}
+
+// UpdateTag cambia nombre, sede o estado. Las etiquetas no se eliminan para no perder el historial de marcas.
+func (s *NFCTagService) UpdateTag(ctx context.Context, agencyID uuid.UUID, tagID uuid.UUID, req *dto.UpdateNFCTagRequest) (*dto.NFCTagResponse, error) {
+	tag, err := s.nfcTagRepo.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.AgencyID != agencyID {
+		return nil, domain.ErrNFCTagNotFound
+	}
+
+	if req.Label != nil {
+		tag.Label = *req.Label
+	}
+	if req.SiteID != nil {
+		if err := s.setSite(ctx, agencyID, tag, *req.SiteID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Active != nil {
+		tag.Active = *req.Active
+	}
+
+	if err := s.nfcTagRepo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	return dto.ToNFCTagResponse(tag), nil
+}
+
+// GetTagUsage lista las marcas hechas con etiquetas NFC. Las fechas se interpretan en la zona de la agencia.
+func (s *NFCTagService) GetTagUsage(ctx context.Context, agencyID uuid.UUID, params *dto.NFCTagUsageParams) ([]*dto.NFCTagUsageResponse, error) {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	loc := agency.Location()
+
+	filter := domain.NFCTagUsageFilter{
+		Page:  params.Page,
+		Limit: params.Limit,
+	}
+
+	if params.TagID != "" {
+		if id, err := uuid.Parse(params.TagID); err == nil {
+			filter.TagID = id
+		}
+	}
+	if params.UserID != "" {
+		if id, err := uuid.Parse(params.UserID); err == nil {
+			filter.UserID = id
+		}
+	}
+	if params.StartDate != "" {
+		if start, err := time.ParseInLocation("2006-01-02", params.StartDate, loc); err == nil {
+			filter.StartDate = &start
+		}
+	}
+	if params.EndDate != "" {
+		if end, err := time.ParseInLocation("2006-01-02", params.EndDate, loc); err == nil {
+			// Incluye el día completo
+			end = end.AddDate(0, 0, 1)
+			filter.EndDate = &end
+		}
+	}
+
+	usage, err := s.nfcTagRepo.ListUsage(ctx, agencyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.NFCTagUsageResponse, len(usage))
+	for i, u := range usage {
+		responses[i] = dto.ToNFCTagUsageResponse(u)
+	}
+	return responses, nil
+}
+
+// setSite asigna la sede donde está instalada la etiqueta; uuid.Nil la desasigna
+func (s *NFCTagService) setSite(ctx context.Context, agencyID uuid.UUID, tag *domain.NFCTag, siteID uuid.UUID) error {
+	if siteID == uuid.Nil {
+		tag.SiteID = nil
+		tag.Site = nil
+		return nil
+	}
+
+	site, err := s.siteRepo.GetByID(ctx, siteID)
+	if err != nil {
+		return err
+	}
+	if site.AgencyID != agencyID {
+		return domain.ErrSiteNotFound
+	}
+	tag.SiteID = &site.ID
+	tag.Site = site
+	return nil
+}
+
+// normalizeTagUID deja el UID en hexadecimal en mayúsculas sin separadores,
+// ya que cada lector lo reporta con un formato distinto (04:a2:3b, 04-A2-3B, 04A23B)
+func normalizeTagUID(uid string) string {
+	return strings.ToUpper(strings.NewReplacer(":", "", "-", "", " ", "").Replace(strings.TrimSpace(uid)))
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"quickattendance-go/internal/domain"
+	"slices"
+	"time"
+)
+
+// allowedPunches define qué marca puede seguir a la última registrada en el día.
+// Después de una salida se permite otra entrada (turno partido).
+var allowedPunches = map[domain.AttendanceType][]domain.AttendanceType{
+	"":                    {domain.TypeIn},
+	domain.TypeIn:         {domain.TypeBreakStart, domain.TypeOut},
+	domain.TypeBreakStart: {domain.TypeBreakEnd},
+	domain.TypeBreakEnd:   {domain.TypeBreakStart, domain.TypeOut},
+	domain.TypeOut:        {domain.TypeIn},
+}
+
+// validatePunch verifica que la nueva marca respete la secuencia y no sea anterior a la última
+func validatePunch(punches []domain.AttendancePunch, next domain.AttendanceType, at time.Time) error {
+	var last domain.AttendanceType
+	if len(punches) > 0 {
+		lastPunch := punches[len(punches)-1]
+		if at.Before(lastPunch.Time) {
+			return domain.ErrInvalidPunchOrder
+		}
+		last = lastPunch.Type
+	}
+
+	if !slices.Contains(allowedPunches[last], next) {
+		return domain.ErrInvalidPunchOrder
+	}
+	return nil
+}
+
+// validatePunchSequence valida una lista completa de marcas ya ordenadas por hora
+func validatePunchSequence(punches []domain.AttendancePunch) error {
+	for i, p := range punches {
+		if err := validatePunch(punches[:i], p.Type, p.Time); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortPunches ordena las marcas cronológicamente (una corrección puede dejar una marca fuera de orden en el slice)
+func sortPunches(punches []domain.AttendancePunch) {
+	slices.SortStableFunc(punches, func(a, b domain.AttendancePunch) int {
+		return a.Time.Compare(b.Time)
+	})
+}
+
+// workedMinutes suma los intervalos trabajados: entrada (o fin de pausa) hasta salida (o inicio de pausa).
+// Un intervalo todavía abierto no se cuenta.
+func workedMinutes(punches []domain.AttendancePunch) int {
+	var worked time.Duration
+	var start *time.Time
+
+	for _, p := range punches {
+		switch p.Type {
+		case domain.TypeIn, domain.TypeBreakEnd:
+			t := p.Time
+			start = &t
+		case domain.TypeOut, domain.TypeBreakStart:
+			if start != nil {
+				worked += p.Time.Sub(*start)
+				start = nil
+			}
+		}
+	}
+
+	return int(worked / time.Minute)
+}
+
+// workedMinutesBetween suma los intervalos trabajados como workedMinutes, pero solo la parte
+// comprendida entre from y to (ej: la banda de una jornada flexible)
+func workedMinutesBetween(punches []domain.AttendancePunch, from time.Time, to time.Time) int {
+	var worked time.Duration
+	var start *time.Time
+
+	for _, p := range punches {
+		switch p.Type {
+		case domain.TypeIn, domain.TypeBreakEnd:
+			t := p.Time
+			start = &t
+		case domain.TypeOut, domain.TypeBreakStart:
+			if start != nil {
+				begin, end := *start, p.Time
+				if begin.Before(from) {
+					begin = from
+				}
+				if end.After(to) {
+					end = to
+				}
+				if end.After(begin) {
+					worked += end.Sub(begin)
+				}
+				start = nil
+			}
+		}
+	}
+
+	return int(worked / time.Minute)
+}
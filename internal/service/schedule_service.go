@@ -4,8 +4,6 @@ import (
 	"context"
 	"quickattendance-go/internal/domain"
 	"quickattendance-go/internal/dto"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,65 +11,134 @@ import (
 
 type ScheduleService struct {
 	scheduleRepo domain.ScheduleRepo
+	overrideRepo domain.ScheduleOverrideRepo
+	holidayRepo  domain.HolidayRepo
+	rotationRepo domain.ShiftRotationRepo
+	leaveRepo    domain.LeaveRequestRepo
 	userRepo     domain.UserRepo
+	agencyRepo   domain.AgencyRepo
 	transactor   domain.Transactor
 }
 
-func NewScheduleService(scheduleRepo domain.ScheduleRepo, userRepo domain.UserRepo, transactor domain.Transactor) *ScheduleService {
+func NewScheduleService(
+	scheduleRepo domain.ScheduleRepo,
+	overrideRepo domain.ScheduleOverrideRepo,
+	holidayRepo domain.HolidayRepo,
+	rotationRepo domain.ShiftRotationRepo,
+	leaveRepo domain.LeaveRequestRepo,
+	userRepo domain.UserRepo,
+	agencyRepo domain.AgencyRepo,
+	transactor domain.Transactor,
+) *ScheduleService {
 	return &ScheduleService{
 		scheduleRepo: scheduleRepo,
+		overrideRepo: overrideRepo,
+		holidayRepo:  holidayRepo,
+		rotationRepo: rotationRepo,
+		leaveRepo:    leaveRepo,
 		userRepo:     userRepo,
+		agencyRepo:   agencyRepo,
 		transactor:   transactor,
 	}
 }
 
 func (s *ScheduleService) CreateSchedule(ctx context.Context, agencyID uuid.UUID, req *dto.CreateScheduleRequest) (*dto.ScheduleResponse, error) {
-	defaultSchedule, err := s.scheduleRepo.GetDefault(ctx, agencyID)
+	today, err := s.today(ctx, agencyID)
 	if err != nil {
 		return nil, err
 	}
 
-	if req.IsDefault && defaultSchedule != nil {
-		return nil, domain.ErrDefaultScheduleAlreadyExists
-	}
-
-	repeated, err := s.scheduleRepo.GetByName(ctx, agencyID, req.Name)
+	days, err := domain.NewWeekdaySet(req.DaysOfWeek)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(repeated) > 0 {
-		return nil, domain.ErrScheduleNameAlreadyExists
+	schedule := &domain.Schedule{
+		Name:                       req.Name,
+		DaysOfWeek:                 days,
+		EntryTimeMinutes:           req.EntryTimeMinutes,
+		ExitTimeMinutes:            req.ExitTimeMinutes,
+		GracePeriodMinutes:         req.GracePeriodMinutes,
+		EarlyLeaveToleranceMinutes: req.EarlyLeaveToleranceMinutes,
+		IsDefault:                  req.IsDefault,
+		AgencyID:                   agencyID,
+		Version:                    1,
+		Type:                       domain.ScheduleFixed,
 	}
 
-	var daysStr []string
-	for _, day := range req.DaysOfWeek {
-		daysStr = append(daysStr, strconv.Itoa(day))
+	if req.Type == domain.ScheduleFlexible {
+		schedule.Type = domain.ScheduleFlexible
+		schedule.CoreStartMinutes = req.CoreStartMinutes
+		schedule.CoreEndMinutes = req.CoreEndMinutes
+		schedule.RequiredMinutes = req.RequiredMinutes
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, err
 	}
 
-	schedule := &domain.Schedule{
-		Name:               req.Name,
-		DaysOfWeek:         strings.Join(daysStr, ","),
-		EntryTimeMinutes:   req.EntryTimeMinutes,
-		ExitTimeMinutes:    req.ExitTimeMinutes,
-		GracePeriodMinutes: req.GracePeriodMinutes,
-		IsDefault:          req.IsDefault,
-		AgencyID:           agencyID,
+	if req.EffectiveFrom != "" {
+		from, err := time.Parse("2006-01-02", req.EffectiveFrom)
+		if err != nil {
+			return nil, domain.ErrInvalidEffectiveDate
+		}
+		schedule.EffectiveFrom = &from
 	}
 
-	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+	// Las validaciones contra los demás horarios y la creación van en la misma transacción, como en UpdateSchedule
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		defaultSchedule, err := s.scheduleRepo.GetDefault(txCtx, agencyID, today)
+		if err != nil {
+			return err
+		}
+		if req.IsDefault && defaultSchedule != nil {
+			return domain.ErrDefaultScheduleAlreadyExists
+		}
+
+		repeated, err := s.scheduleRepo.GetByName(txCtx, agencyID, req.Name)
+		if err != nil {
+			return err
+		}
+		if len(repeated) > 0 {
+			return domain.ErrScheduleNameAlreadyExists
+		}
+
+		for _, id := range req.AssignedUsersIDs {
+			u, err := s.userRepo.GetByID(txCtx, id)
+			if err != nil {
+				return err
+			}
+			if u.AgencyID != agencyID {
+				return domain.ErrUserNotFound
+			}
+			schedule.AssignedUsers = append(schedule.AssignedUsers, *u)
+		}
+		if err := s.checkAssignments(txCtx, schedule, today); err != nil {
+			return err
+		}
+
+		return s.scheduleRepo.Create(txCtx, schedule)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return dto.ToScheduleResponse(schedule), nil
 }
 
+// GetAgencySchedules lista las versiones vigentes y las programadas; las que ya terminaron
+// se consultan con GetScheduleVersions
 func (s *ScheduleService) GetAgencySchedules(ctx context.Context, agencyID uuid.UUID, params *dto.ScheduleListParams) ([]*dto.ScheduleResponse, error) {
+	today, err := s.today(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := domain.ScheduleFilter{
-		Name:      params.Name,
-		IsDefault: params.IsDefault,
-		Page:      params.Page,
-		Limit:     params.Limit,
+		Name:       params.Name,
+		IsDefault:  params.IsDefault,
+		ActiveFrom: &today,
+		Page:       params.Page,
+		Limit:      params.Limit,
 	}
 
 	schedules, err := s.scheduleRepo.GetByAgencyID(ctx, agencyID, filter)
@@ -100,9 +167,40 @@ func (s *ScheduleService) GetSchedule(ctx context.Context, scheduleID uuid.UUID,
 	return dto.ToScheduleResponse(schedule), nil
 }
 
+// GetScheduleVersions devuelve el historial de versiones de la serie a la que pertenece scheduleID
+func (s *ScheduleService) GetScheduleVersions(ctx context.Context, scheduleID uuid.UUID, agencyID uuid.UUID) ([]*dto.ScheduleResponse, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.AgencyID != agencyID {
+		return nil, domain.ErrScheduleNotFound
+	}
+
+	versions, err := s.scheduleRepo.GetVersions(ctx, schedule.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ScheduleResponse, len(versions))
+	for i, v := range versions {
+		responses[i] = dto.ToScheduleResponse(v)
+	}
+	return responses, nil
+}
+
+// UpdateSchedule aplica el nombre y el horario por defecto a todas las versiones de la serie. Los cambios
+// de días, horas o usuarios crean una versión nueva desde req.EffectiveFrom (hoy por defecto) y cierran la
+// anterior el día previo, así las fechas pasadas se siguen evaluando con las reglas que tenían.
 func (s *ScheduleService) UpdateSchedule(ctx context.Context, req *dto.UpdateScheduleRequest, scheduleID uuid.UUID, agencyID uuid.UUID) (*dto.ScheduleResponse, error) {
+	today, err := s.today(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
 	var response *dto.ScheduleResponse
-	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
 		schedule, err := s.scheduleRepo.GetByID(txCtx, scheduleID)
 		if err != nil {
 			return err
@@ -111,43 +209,125 @@ func (s *ScheduleService) UpdateSchedule(ctx context.Context, req *dto.UpdateSch
 			return domain.ErrScheduleNotFound
 		}
 
+		versions, err := s.scheduleRepo.GetVersions(txCtx, schedule.SeriesID)
+		if err != nil {
+			return err
+		}
+
 		if req.Name != nil {
 			repeated, _ := s.scheduleRepo.GetByName(txCtx, agencyID, *req.Name)
 			for _, r := range repeated {
-				if r.ID != scheduleID {
+				if r.SeriesID != schedule.SeriesID {
 					return domain.ErrScheduleNameAlreadyExists
 				}
 			}
-			schedule.Name = *req.Name
 		}
 
-		if req.IsDefault != nil && *req.IsDefault && !schedule.IsDefault {
-			currentDefault, _ := s.scheduleRepo.GetDefault(txCtx, agencyID)
+		makeDefault := req.IsDefault != nil && *req.IsDefault && !schedule.IsDefault
+		if makeDefault {
+			currentDefault, _ := s.scheduleRepo.GetDefault(txCtx, agencyID, today)
 			if currentDefault != nil {
-				currentDefault.IsDefault = false
-				if err := s.scheduleRepo.Update(txCtx, currentDefault); err != nil {
+				previous, err := s.scheduleRepo.GetVersions(txCtx, currentDefault.SeriesID)
+				if err != nil {
+					return err
+				}
+				for _, v := range previous {
+					v.IsDefault = false
+					if err := s.scheduleRepo.Update(txCtx, v); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		if req.Name != nil || makeDefault {
+			for _, v := range versions {
+				if req.Name != nil {
+					v.Name = *req.Name
+				}
+				if makeDefault {
+					v.IsDefault = true
+				}
+				if err := s.scheduleRepo.Update(txCtx, v); err != nil {
 					return err
 				}
 			}
-			schedule.IsDefault = true
 		}
 
+		latest := versions[len(versions)-1]
+		changesRules := req.DaysOfWeek != nil || req.EntryTimeMinutes != nil || req.ExitTimeMinutes != nil ||
+			req.GracePeriodMinutes != nil || req.EarlyLeaveToleranceMinutes != nil || req.AssignedUsersIDs != nil ||
+			req.Type != nil || req.CoreStartMinutes != nil || req.CoreEndMinutes != nil || req.RequiredMinutes != nil
+		if !changesRules {
+			for _, v := range versions {
+				if v.ID == scheduleID {
+					response = dto.ToScheduleResponse(v)
+				}
+			}
+			return nil
+		}
+
+		// Una serie dada de baja tiene su última versión cerrada y ya no admite cambios
+		if latest.ID != scheduleID || latest.EffectiveTo != nil {
+			return domain.ErrScheduleVersionNotCurrent
+		}
+
+		from := today
+		if req.EffectiveFrom != nil {
+			from, err = time.Parse("2006-01-02", *req.EffectiveFrom)
+			if err != nil {
+				return domain.ErrInvalidEffectiveDate
+			}
+		}
+		// No se reescribe el pasado ni se intercala una versión antes de la vigente
+		if from.Before(today) || (latest.EffectiveFrom != nil && from.Before(*latest.EffectiveFrom)) {
+			return domain.ErrInvalidEffectiveDate
+		}
+
+		next := *latest
+		next.ID = uuid.Nil
+		next.Version = latest.Version + 1
+		next.EffectiveFrom = &from
+		next.EffectiveTo = nil
+		next.CreatedAt = time.Time{}
+		next.UpdatedAt = time.Time{}
+
 		if req.DaysOfWeek != nil {
-			var days []string
-			for _, d := range *req.DaysOfWeek {
-				days = append(days, strconv.Itoa(d))
+			next.DaysOfWeek, err = domain.NewWeekdaySet(*req.DaysOfWeek)
+			if err != nil {
+				return err
 			}
-			schedule.DaysOfWeek = strings.Join(days, ",")
 		}
 
 		if req.EntryTimeMinutes != nil {
-			schedule.EntryTimeMinutes = *req.EntryTimeMinutes
+			next.EntryTimeMinutes = *req.EntryTimeMinutes
 		}
 		if req.ExitTimeMinutes != nil {
-			schedule.ExitTimeMinutes = *req.ExitTimeMinutes
+			next.ExitTimeMinutes = *req.ExitTimeMinutes
 		}
 		if req.GracePeriodMinutes != nil {
-			schedule.GracePeriodMinutes = *req.GracePeriodMinutes
+			next.GracePeriodMinutes = *req.GracePeriodMinutes
+		}
+		if req.EarlyLeaveToleranceMinutes != nil {
+			next.EarlyLeaveToleranceMinutes = *req.EarlyLeaveToleranceMinutes
+		}
+		if req.Type != nil {
+			next.Type = *req.Type
+		}
+		if req.CoreStartMinutes != nil {
+			next.CoreStartMinutes = *req.CoreStartMinutes
+		}
+		if req.CoreEndMinutes != nil {
+			next.CoreEndMinutes = *req.CoreEndMinutes
+		}
+		if req.RequiredMinutes != nil {
+			next.RequiredMinutes = *req.RequiredMinutes
+		}
+		if !next.IsFlexible() {
+			next.CoreStartMinutes, next.CoreEndMinutes, next.RequiredMinutes = 0, 0, 0
+		}
+		if err := next.Validate(); err != nil {
+			return err
 		}
 
 		if req.AssignedUsersIDs != nil {
@@ -163,14 +343,31 @@ func (s *ScheduleService) UpdateSchedule(ctx context.Context, req *dto.UpdateSch
 				}
 				users = append(users, *u)
 			}
-			schedule.AssignedUsers = users
+			next.AssignedUsers = users
+		}
+		if err := s.checkAssignments(txCtx, &next, today); err != nil {
+			return err
 		}
 
-		if err := s.scheduleRepo.Update(txCtx, schedule); err != nil {
+		if latest.EffectiveFrom != nil && from.Equal(*latest.EffectiveFrom) {
+			// La versión vigente empieza el mismo día: no rigió ninguna otra fecha, se reemplaza
+			next.Version = latest.Version
+			if err := s.scheduleRepo.Delete(txCtx, latest.ID); err != nil {
+				return err
+			}
+		} else {
+			end := from.AddDate(0, 0, -1)
+			latest.EffectiveTo = &end
+			if err := s.scheduleRepo.Update(txCtx, latest); err != nil {
+				return err
+			}
+		}
+
+		if err := s.scheduleRepo.Create(txCtx, &next); err != nil {
 			return err
 		}
 
-		response = dto.ToScheduleResponse(schedule)
+		response = dto.ToScheduleResponse(&next)
 		return nil
 	})
 
@@ -181,6 +378,8 @@ func (s *ScheduleService) UpdateSchedule(ctx context.Context, req *dto.UpdateSch
 	return response, nil
 }
 
+// DeleteSchedule da de baja la serie desde hoy: las versiones que aún no rigieron se eliminan y las demás
+// se cierran ayer, para que las fechas pasadas conserven su horario
 func (s *ScheduleService) DeleteSchedule(ctx context.Context, scheduleID uuid.UUID, agencyID uuid.UUID) error {
 	schedule, err := s.scheduleRepo.GetByID(ctx, scheduleID)
 	if err != nil {
@@ -195,27 +394,312 @@ func (s *ScheduleService) DeleteSchedule(ctx context.Context, scheduleID uuid.UU
 		return domain.ErrDeleteDefaultSchedule
 	}
 
-	if err := s.scheduleRepo.Delete(ctx, scheduleID); err != nil {
+	today, err := s.today(ctx, agencyID)
+	if err != nil {
 		return err
 	}
+	yesterday := today.AddDate(0, 0, -1)
+
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		versions, err := s.scheduleRepo.GetVersions(txCtx, schedule.SeriesID)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range versions {
+			if v.EffectiveFrom != nil && !v.EffectiveFrom.Before(today) {
+				if err := s.scheduleRepo.Delete(txCtx, v.ID); err != nil {
+					return err
+				}
+				continue
+			}
+			if v.EffectiveTo == nil || v.EffectiveTo.After(yesterday) {
+				v.EffectiveTo = &yesterday
+				if err := s.scheduleRepo.Update(txCtx, v); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// checkAssignments verifica que ningún usuario asignado a schedule tenga otro horario vigente desde today
+// en los mismos días de la semana: el horario de ese día sería ambiguo. Las demás versiones de la misma
+// serie no cuentan, se cierran al crear la nueva.
+func (s *ScheduleService) checkAssignments(ctx context.Context, schedule *domain.Schedule, today time.Time) error {
+	if len(schedule.AssignedUsers) == 0 {
+		return nil
+	}
 
+	assigned := make(map[uuid.UUID]bool, len(schedule.AssignedUsers))
+	userIDs := make([]uuid.UUID, 0, len(schedule.AssignedUsers))
+	for _, u := range schedule.AssignedUsers {
+		assigned[u.ID] = true
+		userIDs = append(userIDs, u.ID)
+	}
+
+	others, err := s.scheduleRepo.GetAssigned(ctx, schedule.AgencyID, userIDs, today)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range others {
+		if other.SeriesID == schedule.SeriesID {
+			continue
+		}
+		days := schedule.Overlaps(other)
+		if len(days) == 0 {
+			continue
+		}
+		for _, u := range other.AssignedUsers {
+			if assigned[u.ID] {
+				return &domain.ScheduleConflictError{UserID: u.ID, Schedule: other, Weekdays: days}
+			}
+		}
+	}
 	return nil
 }
 
+// ListConflicts lista los usuarios que tienen dos horarios vigentes desde hoy en un mismo día de la semana,
+// asignados antes de que se validaran las asignaciones
+func (s *ScheduleService) ListConflicts(ctx context.Context, agencyID uuid.UUID) ([]*dto.ScheduleConflictResponse, error) {
+	today, err := s.today(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.scheduleRepo.GetAssigned(ctx, agencyID, nil, today)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []*dto.ScheduleConflictResponse{}
+	for i, first := range schedules {
+		for _, second := range schedules[i+1:] {
+			if first.SeriesID == second.SeriesID {
+				continue
+			}
+			days := first.Overlaps(second)
+			if len(days) == 0 {
+				continue
+			}
+
+			inSecond := make(map[uuid.UUID]bool, len(second.AssignedUsers))
+			for _, u := range second.AssignedUsers {
+				inSecond[u.ID] = true
+			}
+			for _, u := range first.AssignedUsers {
+				if inSecond[u.ID] {
+					conflicts = append(conflicts, dto.ToScheduleConflictResponse(&u, first, second, days, today))
+				}
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// today devuelve la fecha actual de la agencia a medianoche UTC, como se leen las columnas date
+func (s *ScheduleService) today(ctx context.Context, agencyID uuid.UUID) (time.Time, error) {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return agencyToday(agency), nil
+}
+
+// agencyToday devuelve la fecha actual de la agencia a medianoche UTC, como se leen las columnas date
+func agencyToday(agency *domain.Agency) time.Time {
+	return dateIn(time.Now().In(agency.Location()), time.UTC)
+}
+
+// GetApplicableSchedule resuelve el horario de un usuario para date, en este orden:
+//  1. Un permiso aprobado de día completo: no se espera trabajo y devuelve *domain.OnLeaveError.
+//  2. Una excepción de fecha asignada al usuario reemplaza entrada, salida y tolerancia de atraso, aun en feriados
+//     y días libres.
+//  3. En los feriados y cierres del calendario no se espera trabajo: devuelve *domain.NonWorkingDayError.
+//     Una excepción de toda la agencia solo reemplaza el horario de los días que el usuario ya trabaja (4 y 5).
+//  4. El turno de la rotación asignada al usuario en date; sus días libres no tienen horario.
+//  5. La versión vigente en date del horario semanal asignado al usuario o, si no tiene, del de la agencia.
+//
+// Un permiso aprobado de medio día recorta el horario resuelto a la mitad que se trabaja.
 func (s *ScheduleService) GetApplicableSchedule(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*dto.ScheduleResponse, error) {
-	weekday := strconv.Itoa(int(date.Weekday()))
+	leaves, err := s.leaveRepo.ListApprovedForDate(ctx, agencyID, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	// Dos medios días aprobados para la misma fecha cubren el día completo
+	if len(leaves) > 1 || (len(leaves) == 1 && leaves[0].HalfDay == domain.LeaveFullDay) {
+		return nil, &domain.OnLeaveError{Leave: leaves[0]}
+	}
+
+	sched, err := s.scheduleForDate(ctx, agencyID, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	if len(leaves) == 1 {
+		return dto.ToHalfDayLeaveScheduleResponse(sched, leaves[0]), nil
+	}
+	return sched, nil
+}
+
+// scheduleForDate resuelve el horario de date sin considerar permisos
+func (s *ScheduleService) scheduleForDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*dto.ScheduleResponse, error) {
+	override, err := s.overrideRepo.FindForDate(ctx, agencyID, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	// Solo una excepción del usuario agrega un día de trabajo. El horario del día aporta el resto
+	// de la configuración (ej: tolerancia de salida), si existe.
+	if override != nil && len(override.Users) > 0 {
+		regular, _ := s.regularSchedule(ctx, agencyID, userID, date)
+		return dto.ToOverriddenScheduleResponse(regular, override), nil
+	}
+
+	holiday, err := s.holidayRepo.FindByDate(ctx, agencyID, date)
+	if err != nil {
+		return nil, err
+	}
+	if holiday != nil {
+		return nil, &domain.NonWorkingDayError{Holiday: holiday}
+	}
+
+	regular, err := s.regularSchedule(ctx, agencyID, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil {
+		return dto.ToOverriddenScheduleResponse(regular, override), nil
+	}
+	return regular, nil
+}
+
+// regularSchedule resuelve el horario de date sin excepciones ni feriados: la rotación asignada tiene
+// prioridad sobre el horario semanal
+func (s *ScheduleService) regularSchedule(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*dto.ScheduleResponse, error) {
+	assignment, err := s.rotationRepo.FindAssignment(ctx, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	if assignment != nil && assignment.AgencyID == agencyID {
+		shift, cycleDay := assignment.Rotation.ShiftAt(date, assignment.OffsetDays)
+		if shift == nil || shift.Off {
+			return nil, domain.ErrNoScheduleFound
+		}
+		return dto.ToRotationScheduleResponse(assignment, shift, cycleDay), nil
+	}
 
-	userSchedule, _ := s.scheduleRepo.GetUserScheduleByDay(ctx, agencyID, userID, weekday)
+	weekly, err := s.weeklySchedule(ctx, agencyID, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToScheduleResponse(weekly), nil
+}
+
+func (s *ScheduleService) weeklySchedule(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*domain.Schedule, error) {
+	userSchedule, _ := s.scheduleRepo.GetUserScheduleByDay(ctx, agencyID, userID, date)
 	if userSchedule != nil {
-		return dto.ToScheduleResponse(userSchedule), nil
+		return userSchedule, nil
 	}
 
-	defaultSchedule, _ := s.scheduleRepo.GetDefault(ctx, agencyID)
+	defaultSchedule, _ := s.scheduleRepo.GetDefault(ctx, agencyID, date)
 	if defaultSchedule != nil {
-		if strings.Contains(defaultSchedule.DaysOfWeek, weekday) {
-			return dto.ToScheduleResponse(defaultSchedule), nil
+		if defaultSchedule.DaysOfWeek.Has(date.Weekday()) {
+			return defaultSchedule, nil
 		}
 	}
 
 	return nil, domain.ErrNoScheduleFound
 }
+
+// Una excepción cubre días puntuales o semanas; para cambios más largos corresponde un horario
+const maxOverrideDays = 366
+
+// CreateOverride registra una excepción de horario para toda la agencia o para los usuarios indicados
+func (s *ScheduleService) CreateOverride(ctx context.Context, agencyID uuid.UUID, adminID uuid.UUID, req *dto.CreateScheduleOverrideRequest) (*dto.ScheduleOverrideResponse, error) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, domain.ErrInvalidScheduleOverride
+	}
+	end := start
+	if req.EndDate != "" {
+		end, err = time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return nil, domain.ErrInvalidScheduleOverride
+		}
+	}
+	if end.Before(start) || end.Sub(start) > maxOverrideDays*24*time.Hour {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	override := &domain.ScheduleOverride{
+		AgencyID:           agencyID,
+		Name:               req.Name,
+		StartDate:          start,
+		EndDate:            end,
+		EntryTimeMinutes:   *req.EntryTimeMinutes,
+		ExitTimeMinutes:    *req.ExitTimeMinutes,
+		GracePeriodMinutes: req.GracePeriodMinutes,
+		CreatedByID:        adminID,
+	}
+
+	for _, id := range req.UserIDs {
+		u, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if u.AgencyID != agencyID {
+			return nil, domain.ErrUserNotFound
+		}
+		override.Users = append(override.Users, *u)
+	}
+
+	if err := s.overrideRepo.Create(ctx, override); err != nil {
+		return nil, err
+	}
+	return dto.ToScheduleOverrideResponse(override), nil
+}
+
+func (s *ScheduleService) ListOverrides(ctx context.Context, agencyID uuid.UUID, params *dto.ScheduleOverrideListParams) ([]*dto.ScheduleOverrideResponse, error) {
+	filter := domain.ScheduleOverrideFilter{
+		Page:  params.Page,
+		Limit: params.Limit,
+	}
+	if params.UserID != "" {
+		if id, err := uuid.Parse(params.UserID); err == nil {
+			filter.UserID = id
+		}
+	}
+	if params.StartDate != "" {
+		if t, err := time.Parse("2006-01-02", params.StartDate); err == nil {
+			filter.StartDate = &t
+		}
+	}
+	if params.EndDate != "" {
+		if t, err := time.Parse("2006-01-02", params.EndDate); err == nil {
+			filter.EndDate = &t
+		}
+	}
+
+	overrides, err := s.overrideRepo.List(ctx, agencyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ScheduleOverrideResponse, len(overrides))
+	for i, o := range overrides {
+		responses[i] = dto.ToScheduleOverrideResponse(o)
+	}
+	return responses, nil
+}
+
+func (s *ScheduleService) DeleteOverride(ctx context.Context, agencyID uuid.UUID, overrideID uuid.UUID) error {
+	override, err := s.overrideRepo.GetByID(ctx, overrideID)
+	if err != nil {
+		return err
+	}
+	if override.AgencyID != agencyID {
+		return domain.ErrScheduleOverrideNotFound
+	}
+	return s.overrideRepo.Delete(ctx, overrideID)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"quickattendance-go/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeScheduleRepo struct {
+	domain.ScheduleRepo
+	defaultSchedule *domain.Schedule
+}
+
+func (r *fakeScheduleRepo) GetUserScheduleByDay(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*domain.Schedule, error) {
+	return nil, domain.ErrNoScheduleFound
+}
+
+func (r *fakeScheduleRepo) GetDefault(ctx context.Context, agencyID uuid.UUID, date time.Time) (*domain.Schedule, error) {
+	return r.defaultSchedule, nil
+}
+
+type fakeOverrideRepo struct {
+	domain.ScheduleOverrideRepo
+	override *domain.ScheduleOverride
+}
+
+func (r *fakeOverrideRepo) FindForDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) (*domain.ScheduleOverride, error) {
+	return r.override, nil
+}
+
+type fakeHolidayRepo struct {
+	domain.HolidayRepo
+	holidays map[string]*domain.Holiday
+}
+
+func (r *fakeHolidayRepo) FindByDate(ctx context.Context, agencyID uuid.UUID, date time.Time) (*domain.Holiday, error) {
+	return r.holidays[date.Format("2006-01-02")], nil
+}
+
+type fakeRotationRepo struct {
+	domain.ShiftRotationRepo
+}
+
+func (r *fakeRotationRepo) FindAssignment(ctx context.Context, userID uuid.UUID, date time.Time) (*domain.RotationAssignment, error) {
+	return nil, nil
+}
+
+type fakeLeaveRepo struct {
+	domain.LeaveRequestRepo
+}
+
+func (r *fakeLeaveRepo) ListApprovedForDate(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, date time.Time) ([]*domain.LeaveRequest, error) {
+	return nil, nil
+}
+
+func TestGetApplicableScheduleOverrides(t *testing.T) {
+	agencyID, userID := uuid.New(), uuid.New()
+
+	weekday := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC) // Wednesday
+	saturday := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	holiday := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC) // Thursday
+
+	regular := &domain.Schedule{
+		AgencyID:         agencyID,
+		Name:             "Office",
+		DaysOfWeek:       0b0111110, // Monday to Friday
+		EntryTimeMinutes: 9 * 60,
+		ExitTimeMinutes:  18 * 60,
+		IsDefault:        true,
+	}
+	agencyOverride := &domain.ScheduleOverride{AgencyID: agencyID, Name: "Early close", EntryTimeMinutes: 9 * 60, ExitTimeMinutes: 14 * 60}
+	userOverride := &domain.ScheduleOverride{AgencyID: agencyID, Name: "Inventory", EntryTimeMinutes: 8 * 60, ExitTimeMinutes: 13 * 60, Users: []domain.User{{ID: userID}}}
+
+	tests := []struct {
+		name         string
+		date         time.Time
+		override     *domain.ScheduleOverride
+		wantExit     int
+		wantOverride bool
+		wantErr      error
+		wantHoliday  bool
+	}{
+		{"regular weekday", weekday, nil, 18 * 60, false, nil, false},
+		{"agency override on a working day", weekday, agencyOverride, 14 * 60, true, nil, false},
+		{"agency override on a weekend", saturday, agencyOverride, 0, false, domain.ErrNoScheduleFound, false},
+		{"agency override on a holiday", holiday, agencyOverride, 0, false, nil, true},
+		{"user override on a weekend", saturday, userOverride, 13 * 60, true, nil, false},
+		{"user override on a holiday", holiday, userOverride, 13 * 60, true, nil, false},
+		{"holiday without override", holiday, nil, 0, false, nil, true},
+		{"weekend without override", saturday, nil, 0, false, domain.ErrNoScheduleFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &ScheduleService{
+				scheduleRepo: &fakeScheduleRepo{defaultSchedule: regular},
+				overrideRepo: &fakeOverrideRepo{override: tt.override},
+				holidayRepo: &fakeHolidayRepo{holidays: map[string]*domain.Holiday{
+					"2026-03-05": {AgencyID: agencyID, Date: holiday, Name: "Holiday"},
+				}},
+				rotationRepo: &fakeRotationRepo{},
+				leaveRepo:    &fakeLeaveRepo{},
+			}
+
+			sched, err := svc.GetApplicableSchedule(context.Background(), agencyID, userID, tt.date)
+
+			var nonWorking *domain.NonWorkingDayError
+			if tt.wantHoliday {
+				if !errors.As(err, &nonWorking) {
+					t.Fatalf("GetApplicableSchedule() error = %v, want NonWorkingDayError", err)
+				}
+				return
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetApplicableSchedule() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetApplicableSchedule() error = %v", err)
+			}
+			if sched.ExitTimeMinutes != tt.wantExit {
+				t.Errorf("ExitTimeMinutes = %d, want %d", sched.ExitTimeMinutes, tt.wantExit)
+			}
+			if (sched.Override != nil) != tt.wantOverride {
+				t.Errorf("Override = %v, want override %v", sched.Override, tt.wantOverride)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ShiftRotationService struct {
+	rotationRepo domain.ShiftRotationRepo
+	userRepo     domain.UserRepo
+	agencyRepo   domain.AgencyRepo
+	transactor   domain.Transactor
+}
+
+func NewShiftRotationService(
+	rotationRepo domain.ShiftRotationRepo,
+	userRepo domain.UserRepo,
+	agencyRepo domain.AgencyRepo,
+	transactor domain.Transactor,
+) *ShiftRotationService {
+	return &ShiftRotationService{
+		rotationRepo: rotationRepo,
+		userRepo:     userRepo,
+		agencyRepo:   agencyRepo,
+		transactor:   transactor,
+	}
+}
+
+func (s *ShiftRotationService) CreateRotation(ctx context.Context, agencyID uuid.UUID, req *dto.CreateShiftRotationRequest) (*dto.ShiftRotationResponse, error) {
+	anchor, err := time.Parse("2006-01-02", req.AnchorDate)
+	if err != nil {
+		return nil, domain.ErrInvalidShiftRotation
+	}
+
+	rotation := &domain.ShiftRotation{
+		AgencyID:   agencyID,
+		Name:       req.Name,
+		AnchorDate: anchor,
+	}
+
+	working := false
+	for i, shift := range req.Shifts {
+		rotation.CycleDays += shift.Days
+		if !shift.Off {
+			working = true
+		}
+		rotation.Shifts = append(rotation.Shifts, domain.RotationShift{
+			Position:                   i,
+			Name:                       shift.Name,
+			Days:                       shift.Days,
+			Off:                        shift.Off,
+			EntryTimeMinutes:           shift.EntryTimeMinutes,
+			ExitTimeMinutes:            shift.ExitTimeMinutes,
+			GracePeriodMinutes:         shift.GracePeriodMinutes,
+			EarlyLeaveToleranceMinutes: shift.EarlyLeaveToleranceMinutes,
+		})
+	}
+
+	// Un ciclo solo de descansos no define ningún turno
+	if !working || rotation.CycleDays > domain.MaxRotationCycleDays {
+		return nil, domain.ErrInvalidShiftRotation
+	}
+
+	if err := s.rotationRepo.Create(ctx, rotation); err != nil {
+		return nil, err
+	}
+	return dto.ToShiftRotationResponse(rotation), nil
+}
+
+func (s *ShiftRotationService) GetRotation(ctx context.Context, agencyID uuid.UUID, rotationID uuid.UUID) (*dto.ShiftRotationResponse, error) {
+	rotation, err := s.getRotation(ctx, agencyID, rotationID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToShiftRotationResponse(rotation), nil
+}
+
+func (s *ShiftRotationService) ListRotations(ctx context.Context, agencyID uuid.UUID, params *dto.ShiftRotationListParams) ([]*dto.ShiftRotationResponse, error) {
+	rotations, err := s.rotationRepo.List(ctx, agencyID, domain.ShiftRotationFilter{
+		Page:  params.Page,
+		Limit: params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ShiftRotationResponse, len(rotations))
+	for i, r := range rotations {
+		responses[i] = dto.ToShiftRotationResponse(r)
+	}
+	return responses, nil
+}
+
+// DeleteRotation solo elimina rotaciones que nunca se asignaron: las fechas pasadas de sus usuarios
+// se siguen resolviendo con ella
+func (s *ShiftRotationService) DeleteRotation(ctx context.Context, agencyID uuid.UUID, rotationID uuid.UUID) error {
+	if _, err := s.getRotation(ctx, agencyID, rotationID); err != nil {
+		return err
+	}
+
+	count, err := s.rotationRepo.CountAssignments(ctx, rotationID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain.ErrShiftRotationInUse
+	}
+	return s.rotationRepo.Delete(ctx, rotationID)
+}
+
+// AssignUser asigna el usuario a la rotación desde req.StartDate (hoy por defecto). Una asignación
+// anterior del usuario termina el día previo, y las que aún no empezaron se reemplazan.
+func (s *ShiftRotationService) AssignUser(ctx context.Context, agencyID uuid.UUID, rotationID uuid.UUID, req *dto.AssignRotationRequest) (*dto.RotationAssignmentResponse, error) {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	today := agencyToday(agency)
+
+	start := today
+	if req.StartDate != "" {
+		start, err = time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, domain.ErrInvalidEffectiveDate
+		}
+	}
+	// No se reescribe el horario de fechas pasadas
+	if start.Before(today) {
+		return nil, domain.ErrInvalidEffectiveDate
+	}
+
+	if _, err := s.getRotation(ctx, agencyID, rotationID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.AgencyID != agencyID {
+		return nil, domain.ErrUserNotFound
+	}
+
+	assignment := &domain.RotationAssignment{
+		AgencyID:   agencyID,
+		RotationID: rotationID,
+		UserID:     user.ID,
+		OffsetDays: req.OffsetDays,
+		StartDate:  start,
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.rotationRepo.ListUserAssignments(txCtx, user.ID, start)
+		if err != nil {
+			return err
+		}
+
+		end := start.AddDate(0, 0, -1)
+		for _, a := range current {
+			if !a.StartDate.Before(start) {
+				if err := s.rotationRepo.DeleteAssignment(txCtx, a.ID); err != nil {
+					return err
+				}
+				continue
+			}
+			a.EndDate = &end
+			if err := s.rotationRepo.UpdateAssignment(txCtx, a); err != nil {
+				return err
+			}
+		}
+
+		return s.rotationRepo.CreateAssignment(txCtx, assignment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToRotationAssignmentResponse(assignment), nil
+}
+
+func (s *ShiftRotationService) ListAssignments(ctx context.Context, agencyID uuid.UUID, rotationID uuid.UUID) ([]*dto.RotationAssignmentResponse, error) {
+	if _, err := s.getRotation(ctx, agencyID, rotationID); err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.rotationRepo.ListAssignments(ctx, rotationID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.RotationAssignmentResponse, len(assignments))
+	for i, a := range assignments {
+		responses[i] = dto.ToRotationAssignmentResponse(a)
+	}
+	return responses, nil
+}
+
+// EndAssignment saca al usuario de la rotación desde hoy; si la asignación aún no empezó se elimina
+func (s *ShiftRotationService) EndAssignment(ctx context.Context, agencyID uuid.UUID, assignmentID uuid.UUID) error {
+	assignment, err := s.rotationRepo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return err
+	}
+	if assignment.AgencyID != agencyID {
+		return domain.ErrRotationAssignmentNotFound
+	}
+
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return err
+	}
+	today := agencyToday(agency)
+
+	if !assignment.StartDate.Before(today) {
+		return s.rotationRepo.DeleteAssignment(ctx, assignmentID)
+	}
+
+	yesterday := today.AddDate(0, 0, -1)
+	if assignment.EndDate != nil && !assignment.EndDate.After(yesterday) {
+		return nil
+	}
+	assignment.EndDate = &yesterday
+	return s.rotationRepo.UpdateAssignment(ctx, assignment)
+}
+
+func (s *ShiftRotationService) getRotation(ctx context.Context, agencyID uuid.UUID, rotationID uuid.UUID) (*domain.ShiftRotation, error) {
+	rotation, err := s.rotationRepo.GetByID(ctx, rotationID)
+	if err != nil {
+		return nil, err
+	}
+	if rotation.AgencyID != agencyID {
+		return nil, domain.ErrShiftRotationNotFound
+	}
+	return rotation, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+
+	"github.com/google/uuid"
+)
+
+type SiteService struct {
+	siteRepo domain.SiteRepo
+}
+
+func NewSiteService(siteRepo domain.SiteRepo) *SiteService {
+	return &SiteService{siteRepo: siteRepo}
+}
+
+func (s *SiteService) CreateSite(ctx context.Context, agencyID uuid.UUID, req *dto.CreateSiteRequest) (*dto.SiteResponse, error) {
+	site := &domain.Site{
+		AgencyID:     agencyID,
+		Name:         req.Name,
+		RadiusMeters: req.RadiusMeters,
+		Polygon:      dto.ToGeoPoints(req.Polygon),
+		Active:       true,
+	}
+	if req.Latitude != nil && req.Longitude != nil {
+		site.Latitude = *req.Latitude
+		site.Longitude = *req.Longitude
+	} else {
+		site.Latitude, site.Longitude = polygonCenter(site.Polygon)
+	}
+	if err := validateFence(site); err != nil {
+		return nil, err
+	}
+
+	if err := s.siteRepo.Create(ctx, site); err != nil {
+		return nil, err
+	}
+	return dto.ToSiteResponse(site), nil
+}
+
+func (s *SiteService) GetAgencySites(ctx context.Context, agencyID uuid.UUID, params *dto.SiteListParams) ([]*dto.SiteResponse, error) {
+	sites, err := s.siteRepo.List(ctx, agencyID, domain.SiteFilter{
+		Active: params.Active,
+		Page:   params.Page,
+		Limit:  params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.SiteResponse, len(sites))
+	for i, site := range sites {
+		responses[i] = dto.ToSiteResponse(site)
+	}
+	return responses, nil
+}
+
+// UpdateSite modifica la geocerca o desactiva la sede. No se eliminan para conservar la sede de las marcas pasadas.
+func (s *SiteService) UpdateSite(ctx context.Context, agencyID uuid.UUID, siteID uuid.UUID, req *dto.UpdateSiteRequest) (*dto.SiteResponse, error) {
+	site, err := s.siteRepo.GetByID(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	if site.AgencyID != agencyID {
+		return nil, domain.ErrSiteNotFound
+	}
+
+	if req.Name != nil {
+		site.Name = *req.Name
+	}
+	if req.Latitude != nil {
+		site.Latitude = *req.Latitude
+	}
+	if req.Longitude != nil {
+		site.Longitude = *req.Longitude
+	}
+	if req.RadiusMeters != nil {
+		site.RadiusMeters = *req.RadiusMeters
+	}
+	if req.Polygon != nil {
+		site.Polygon = dto.ToGeoPoints(req.Polygon)
+		if req.Latitude == nil && req.Longitude == nil && site.IsPolygon() {
+			site.Latitude, site.Longitude = polygonCenter(site.Polygon)
+		}
+	}
+	if err := validateFence(site); err != nil {
+		return nil, err
+	}
+	if req.Active != nil {
+		site.Active = *req.Active
+	}
+
+	if err := s.siteRepo.Update(ctx, site); err != nil {
+		return nil, err
+	}
+	return dto.ToSiteResponse(site), nil
+}
+
+// validateFence exige un polígono de al menos 3 vértices o, si no hay polígono, un radio
+func validateFence(site *domain.Site) error {
+	if len(site.Polygon) > 0 && !site.IsPolygon() {
+		return domain.ErrInvalidSiteFence
+	}
+	if !site.IsPolygon() && site.RadiusMeters <= 0 {
+		return domain.ErrInvalidSiteFence
+	}
+	return nil
+}
+
+// polygonCenter promedia los vértices; sirve como referencia de la sede, no para validar marcas
+func polygonCenter(polygon []domain.GeoPoint) (float64, float64) {
+	if len(polygon) == 0 {
+		return 0, 0
+	}
+
+	var lat, lng float64
+	for _, p := range polygon {
+		lat += p.Latitude
+		lng += p.Longitude
+	}
+	n := float64(len(polygon))
+	return lat / n, lng / n
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TimeBankService struct {
+	timeBankRepo   domain.TimeBankRepo
+	attendanceRepo domain.AttendanceRepo
+	leaveRepo      domain.LeaveRequestRepo
+	userRepo       domain.UserRepo
+	agencyRepo     domain.AgencyRepo
+}
+
+func NewTimeBankService(
+	timeBankRepo domain.TimeBankRepo,
+	attendanceRepo domain.AttendanceRepo,
+	leaveRepo domain.LeaveRequestRepo,
+	userRepo domain.UserRepo,
+	agencyRepo domain.AgencyRepo,
+) *TimeBankService {
+	return &TimeBankService{
+		timeBankRepo:   timeBankRepo,
+		attendanceRepo: attendanceRepo,
+		leaveRepo:      leaveRepo,
+		userRepo:       userRepo,
+		agencyRepo:     agencyRepo,
+	}
+}
+
+// syncAttendance deja en la bolsa de horas lo que la asistencia aporta hoy: compara con lo ya registrado
+// para ese registro y agrega la diferencia, así una corrección o una anulación revierte lo acreditado.
+// Con la bolsa desactivada no se acreditan ni descuentan minutos nuevos, pero las reversiones se registran igual.
+// Debe llamarse dentro de la transacción que guarda el registro: la fila queda bloqueada hasta el final,
+// así dos cambios simultáneos del mismo registro no registran dos veces la misma diferencia.
+func (s *TimeBankService) syncAttendance(ctx context.Context, agency *domain.Agency, attendance *domain.Attendance) error {
+	if _, err := s.attendanceRepo.GetByIDForUpdate(ctx, attendance.ID); err != nil {
+		return err
+	}
+
+	credit, debit := timeBankMinutes(agency, attendance)
+
+	entries, err := s.timeBankRepo.ListByAttendanceID(ctx, attendance.ID)
+	if err != nil {
+		return err
+	}
+	overtime, earlyLeave := 0, 0
+	for _, e := range entries {
+		switch e.Kind {
+		case domain.TimeBankOvertime:
+			overtime += e.Minutes
+		case domain.TimeBankEarlyLeave:
+			earlyLeave += e.Minutes
+		}
+	}
+
+	if !agency.TimeBankEnabled {
+		// Solo se acerca a cero lo ya registrado
+		credit = max(min(credit, overtime), 0)
+		debit = max(min(debit, -earlyLeave), 0)
+	}
+
+	changes := []*domain.TimeBankEntry{
+		{Kind: domain.TimeBankOvertime, Minutes: credit - overtime},
+		{Kind: domain.TimeBankEarlyLeave, Minutes: -debit - earlyLeave},
+	}
+	for _, entry := range changes {
+		if entry.Minutes == 0 {
+			continue
+		}
+		entry.AgencyID = attendance.AgencyID
+		entry.UserID = attendance.UserID
+		entry.Date = attendance.Date
+		entry.AttendanceID = &attendance.ID
+		if err := s.timeBankRepo.Create(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpireAll registra el vencimiento de los créditos que cumplieron el plazo de su agencia sin usarse.
+// Es idempotente: cada crédito vence una sola vez.
+func (s *TimeBankService) ExpireAll(ctx context.Context, now time.Time) (int64, error) {
+	agencies, err := s.agencyRepo.ListActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var created int64
+	for _, agency := range agencies {
+		if !agency.TimeBankEnabled || agency.TimeBankExpiryDays <= 0 {
+			continue
+		}
+		today := dateIn(now.In(agency.Location()), time.UTC)
+
+		userIDs, err := s.timeBankRepo.ListUserIDs(ctx, agency.ID)
+		if err != nil {
+			return created, err
+		}
+
+		for _, userID := range userIDs {
+			entries, err := s.entries(ctx, userID)
+			if err != nil {
+				return created, err
+			}
+
+			expiries, _ := planTimeBankExpiries(entries, agency.TimeBankExpiryDays, today)
+			n, err := s.timeBankRepo.CreateBatch(ctx, expiries)
+			created += n
+			if err != nil {
+				return created, err
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// GetBalance devuelve el saldo del usuario y los créditos sin usar que vencerán, del más próximo al más lejano
+func (s *TimeBankService) GetBalance(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID) (*dto.TimeBankBalanceResponse, error) {
+	agency, err := s.getAgency(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getUser(ctx, agencyID, userID); err != nil {
+		return nil, err
+	}
+
+	today := agencyToday(agency)
+	entries, err := s.entries(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	expiries, lots := planTimeBankExpiries(entries, agency.TimeBankExpiryDays, today)
+
+	pending, err := s.pendingMinutes(ctx, agencyID, userID, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TimeBankBalanceResponse{
+		UserID:         userID,
+		BalanceMinutes: sumTimeBank(entries) + sumTimeBank(expiries),
+		PendingMinutes: pending,
+		Expirations:    []*dto.TimeBankExpirationResponse{},
+	}
+	if agency.TimeBankExpiryDays > 0 {
+		for _, l := range lots {
+			date := l.entry.Date.AddDate(0, 0, agency.TimeBankExpiryDays).Format("2006-01-02")
+			if n := len(response.Expirations); n > 0 && response.Expirations[n-1].Date == date {
+				response.Expirations[n-1].Minutes += l.remaining
+				continue
+			}
+			response.Expirations = append(response.Expirations, &dto.TimeBankExpirationResponse{Date: date, Minutes: l.remaining})
+		}
+	}
+	return response, nil
+}
+
+// Ledger devuelve los movimientos del usuario, del más reciente al más antiguo
+func (s *TimeBankService) Ledger(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, params *dto.TimeBankLedgerParams) ([]*dto.TimeBankEntryResponse, error) {
+	if _, err := s.getUser(ctx, agencyID, userID); err != nil {
+		return nil, err
+	}
+
+	filter := domain.TimeBankFilter{
+		UserID: userID,
+		Kind:   domain.TimeBankEntryKind(params.Kind),
+		Page:   params.Page,
+		Limit:  params.Limit,
+	}
+	if params.StartDate != "" {
+		if t, err := time.Parse("2006-01-02", params.StartDate); err == nil {
+			filter.StartDate = &t
+		}
+	}
+	if params.EndDate != "" {
+		if t, err := time.Parse("2006-01-02", params.EndDate); err == nil {
+			filter.EndDate = &t
+		}
+	}
+
+	entries, err := s.timeBankRepo.List(ctx, agencyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.TimeBankEntryResponse, len(entries))
+	for i, e := range entries {
+		responses[i] = dto.ToTimeBankEntryResponse(e)
+	}
+	return responses, nil
+}
+
+// Adjust registra un movimiento manual (ej: saldo inicial, horas extra pagadas que salen de la bolsa)
+func (s *TimeBankService) Adjust(ctx context.Context, agencyID uuid.UUID, adminID uuid.UUID, req *dto.CreateTimeBankAdjustmentRequest) (*dto.TimeBankEntryResponse, error) {
+	agency, err := s.getAgency(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if !agency.TimeBankEnabled {
+		return nil, domain.ErrTimeBankDisabled
+	}
+	if _, err := s.getUser(ctx, agencyID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if req.Minutes == 0 || note == "" {
+		return nil, domain.ErrInvalidTimeBankAdjustment
+	}
+
+	date := agencyToday(agency)
+	if req.Date != "" {
+		date, err = time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, domain.ErrInvalidTimeBankAdjustment
+		}
+	}
+
+	entry := &domain.TimeBankEntry{
+		AgencyID:    agencyID,
+		UserID:      req.UserID,
+		Date:        date,
+		Kind:        domain.TimeBankAdjustment,
+		Minutes:     req.Minutes,
+		CreatedByID: &adminID,
+		Note:        &note,
+	}
+	if err := s.timeBankRepo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	return dto.ToTimeBankEntryResponse(entry), nil
+}
+
+// lockBank bloquea los movimientos del usuario hasta el fin de la transacción, así dos aprobaciones
+// no usan el mismo saldo a la vez
+func (s *TimeBankService) lockBank(ctx context.Context, userID uuid.UUID) error {
+	return s.timeBankRepo.LockByUser(ctx, userID)
+}
+
+// availableMinutes devuelve el saldo actual del usuario menos lo comprometido en pending
+func (s *TimeBankService) availableMinutes(ctx context.Context, agency *domain.Agency, userID uuid.UUID, pending int) (int, error) {
+	entries, err := s.entries(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	expiries, _ := planTimeBankExpiries(entries, agency.TimeBankExpiryDays, agencyToday(agency))
+	return sumTimeBank(entries) + sumTimeBank(expiries) - pending, nil
+}
+
+// debitCompTime descuenta de la bolsa los minutos de un permiso compensatorio al aprobarlo:
+// se usan los créditos disponibles ese día, aunque alguno venza antes de que empiece el permiso
+func (s *TimeBankService) debitCompTime(ctx context.Context, agency *domain.Agency, leave *domain.LeaveRequest) error {
+	return s.timeBankRepo.Create(ctx, &domain.TimeBankEntry{
+		AgencyID:       leave.AgencyID,
+		UserID:         leave.UserID,
+		Date:           agencyToday(agency),
+		Kind:           domain.TimeBankCompTime,
+		Minutes:        -leave.Minutes,
+		LeaveRequestID: &leave.ID,
+	})
+}
+
+// pendingMinutes suma los minutos de los permisos compensatorios pendientes del usuario, sin contar exclude
+func (s *TimeBankService) pendingMinutes(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, exclude uuid.UUID) (int, error) {
+	leaves, err := s.leaveRepo.List(ctx, agencyID, domain.LeaveRequestFilter{
+		UserID: userID,
+		Status: domain.LeavePending,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, l := range leaves {
+		if l.LeaveType.UsesTimeBank && l.ID != exclude {
+			total += l.Minutes
+		}
+	}
+	return total, nil
+}
+
+func (s *TimeBankService) getAgency(ctx context.Context, agencyID uuid.UUID) (*domain.Agency, error) {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return nil, domain.ErrAgencyNotFound
+	}
+	return agency, nil
+}
+
+func (s *TimeBankService) getUser(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.AgencyID != agencyID {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// entries devuelve los movimientos del usuario con las fechas normalizadas a medianoche UTC para compararlas
+func (s *TimeBankService) entries(ctx context.Context, userID uuid.UUID) ([]*domain.TimeBankEntry, error) {
+	entries, err := s.timeBankRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		e.Date = dateIn(e.Date, time.UTC)
+	}
+	return entries, nil
+}
+
+// timeBankLot es lo que queda sin usar de un crédito
+type timeBankLot struct {
+	entry     *domain.TimeBankEntry
+	remaining int
+}
+
+// planTimeBankExpiries recorre los movimientos en orden cronológico consumiendo los créditos del más antiguo
+// al más nuevo (los débitos sin crédito disponible quedan como deuda que pagan los créditos siguientes).
+// Devuelve los vencimientos que faltan registrar hasta today, con fecha en que vence cada crédito,
+// y los créditos que siguen sin usar. Con expiryDays = 0 los créditos no vencen.
+func planTimeBankExpiries(entries []*domain.TimeBankEntry, expiryDays int, today time.Time) ([]*domain.TimeBankEntry, []*timeBankLot) {
+	expired := make(map[uuid.UUID]bool)
+	for _, e := range entries {
+		if e.Kind == domain.TimeBankExpiry && e.ExpiredEntryID != nil {
+			expired[*e.ExpiredEntryID] = true
+		}
+	}
+
+	var lots []*timeBankLot
+	var planned []*domain.TimeBankEntry
+	debt := 0
+
+	// expireDue vence los créditos cuyo plazo terminó hasta date (inclusive); nunca después de today
+	expireDue := func(date time.Time) {
+		if expiryDays <= 0 {
+			return
+		}
+		for _, l := range lots {
+			expiresOn := l.entry.Date.AddDate(0, 0, expiryDays)
+			if l.remaining == 0 || expiresOn.After(date) || expiresOn.After(today) {
+				continue
+			}
+			if !expired[l.entry.ID] {
+				planned = append(planned, &domain.TimeBankEntry{
+					AgencyID:       l.entry.AgencyID,
+					UserID:         l.entry.UserID,
+					Date:           expiresOn,
+					Kind:           domain.TimeBankExpiry,
+					Minutes:        -l.remaining,
+					ExpiredEntryID: &l.entry.ID,
+				})
+			}
+			l.remaining = 0
+		}
+	}
+
+	for _, e := range entries {
+		expireDue(e.Date)
+
+		switch {
+		case e.Kind == domain.TimeBankExpiry:
+			// Ya se descontó al vencer el crédito en expireDue
+		case e.Minutes > 0:
+			paid := min(debt, e.Minutes)
+			debt -= paid
+			if e.Minutes > paid {
+				lots = append(lots, &timeBankLot{entry: e, remaining: e.Minutes - paid})
+			}
+		default:
+			owed := -e.Minutes
+			for _, l := range lots {
+				used := min(l.remaining, owed)
+				l.remaining -= used
+				owed -= used
+			}
+			debt += owed
+		}
+	}
+	expireDue(today)
+
+	remaining := make([]*timeBankLot, 0, len(lots))
+	for _, l := range lots {
+		if l.remaining > 0 {
+			remaining = append(remaining, l)
+		}
+	}
+	return planned, remaining
+}
+
+func sumTimeBank(entries []*domain.TimeBankEntry) int {
+	total := 0
+	for _, e := range entries {
+		total += e.Minutes
+	}
+	return total
+}
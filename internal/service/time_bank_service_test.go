@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"quickattendance-go/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTimeBankMinutes(t *testing.T) {
+	day := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	ptr := func(t time.Time) *time.Time { return &t }
+	status := func(s domain.AttendanceStatus) *domain.AttendanceStatus { return &s }
+	punches := func(in, out time.Time) []domain.AttendancePunch {
+		return []domain.AttendancePunch{{Type: domain.TypeIn, Time: in}, {Type: domain.TypeOut, Time: out}}
+	}
+	// Turno de 9:00 a 18:00
+	shift := func(out time.Time, exit domain.AttendanceStatus) *domain.Attendance {
+		return &domain.Attendance{
+			CheckInTime:       ptr(at(9, 0)),
+			CheckOutTime:      &out,
+			ScheduleEntryTime: at(9, 0),
+			ScheduleExitTime:  at(18, 0),
+			ExitStatus:        status(exit),
+			Punches:           punches(at(9, 0), out),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		agency     domain.Agency
+		attendance *domain.Attendance
+		wantCredit int
+		wantDebit  int
+	}{
+		{
+			name:       "on time",
+			attendance: shift(at(18, 0), domain.StatusOnTime),
+		},
+		{
+			name:       "worked after the scheduled exit",
+			attendance: shift(at(19, 10), domain.StatusOnTime),
+			wantCredit: 70,
+		},
+		{
+			name:       "overtime under the agency minimum",
+			agency:     domain.Agency{MinOvertimeMinutes: 30},
+			attendance: shift(at(18, 20), domain.StatusOnTime),
+		},
+		{
+			name:       "overtime rounded by the agency",
+			agency:     domain.Agency{WorkRoundingMinutes: 15, WorkRoundingMode: domain.RoundingFloor},
+			attendance: shift(at(19, 10), domain.StatusOnTime),
+			wantCredit: 60,
+		},
+		{
+			name:       "left early",
+			attendance: shift(at(17, 15), domain.StatusEarly),
+			wantDebit:  45,
+		},
+		{
+			name:       "early leave under the agency minimum",
+			agency:     domain.Agency{MinOvertimeMinutes: 30},
+			attendance: shift(at(17, 59), domain.StatusEarly),
+		},
+		{
+			name:       "early leave from the agency minimum",
+			agency:     domain.Agency{MinOvertimeMinutes: 30},
+			attendance: shift(at(17, 30), domain.StatusEarly),
+			wantDebit:  30,
+		},
+		{
+			name:       "early leave rounded by the agency",
+			agency:     domain.Agency{WorkRoundingMinutes: 15, WorkRoundingMode: domain.RoundingFloor},
+			attendance: shift(at(17, 5), domain.StatusEarly),
+			wantDebit:  45,
+		},
+		{
+			name:       "overtime from the agency minimum",
+			agency:     domain.Agency{MinOvertimeMinutes: 30},
+			attendance: shift(at(18, 30), domain.StatusOnTime),
+			wantCredit: 30,
+		},
+		{
+			name: "break after the scheduled exit does not count",
+			attendance: func() *domain.Attendance {
+				a := shift(at(20, 0), domain.StatusOnTime)
+				a.Punches = []domain.AttendancePunch{
+					{Type: domain.TypeIn, Time: at(9, 0)},
+					{Type: domain.TypeBreakStart, Time: at(18, 30)},
+					{Type: domain.TypeBreakEnd, Time: at(19, 30)},
+					{Type: domain.TypeOut, Time: at(20, 0)},
+				}
+				return a
+			}(),
+			wantCredit: 60,
+		},
+		{
+			name: "flexible day uses overtime and deficit",
+			attendance: func() *domain.Attendance {
+				a := shift(at(16, 0), domain.StatusShortHours)
+				a.RequiredMinutes = 480
+				a.DeficitMinutes = 60
+				return a
+			}(),
+			wantDebit: 60,
+		},
+		{
+			name:   "flexible deficit under the agency minimum",
+			agency: domain.Agency{MinOvertimeMinutes: 30},
+			attendance: func() *domain.Attendance {
+				a := shift(at(17, 50), domain.StatusShortHours)
+				a.RequiredMinutes = 480
+				a.DeficitMinutes = 10
+				return a
+			}(),
+		},
+		{
+			name: "without checkout",
+			attendance: func() *domain.Attendance {
+				a := shift(at(19, 0), domain.StatusOnTime)
+				a.CheckOutTime = nil
+				return a
+			}(),
+		},
+		{
+			name: "voided",
+			attendance: func() *domain.Attendance {
+				a := shift(at(19, 0), domain.StatusOnTime)
+				a.VoidedAt = ptr(at(20, 0))
+				return a
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credit, debit := timeBankMinutes(&tt.agency, tt.attendance)
+			if credit != tt.wantCredit || debit != tt.wantDebit {
+				t.Errorf("timeBankMinutes() = %d, %d, want %d, %d", credit, debit, tt.wantCredit, tt.wantDebit)
+			}
+		})
+	}
+}
+
+type fakeTimeBankRepo struct {
+	domain.TimeBankRepo
+	entries []*domain.TimeBankEntry
+}
+
+func (r *fakeTimeBankRepo) ListByAttendanceID(ctx context.Context, attendanceID uuid.UUID) ([]*domain.TimeBankEntry, error) {
+	var entries []*domain.TimeBankEntry
+	for _, e := range r.entries {
+		if e.AttendanceID != nil && *e.AttendanceID == attendanceID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeTimeBankRepo) Create(ctx context.Context, entry *domain.TimeBankEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// fakeLockingAttendanceRepo records which attendance rows were locked
+type fakeLockingAttendanceRepo struct {
+	domain.AttendanceRepo
+	locked []uuid.UUID
+}
+
+func (r *fakeLockingAttendanceRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Attendance, error) {
+	r.locked = append(r.locked, id)
+	return &domain.Attendance{ID: id}, nil
+}
+
+func TestSyncAttendance(t *testing.T) {
+	day := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+	onTime := domain.StatusOnTime
+
+	// 9:00 a 18:00 con salida a las 20:00: 120 minutos a favor
+	newAttendance := func() *domain.Attendance {
+		in, out := at(9), at(20)
+		return &domain.Attendance{
+			ID:                uuid.New(),
+			CheckInTime:       &in,
+			CheckOutTime:      &out,
+			ScheduleEntryTime: at(9),
+			ScheduleExitTime:  at(18),
+			ExitStatus:        &onTime,
+			Punches:           []domain.AttendancePunch{{Type: domain.TypeIn, Time: in}, {Type: domain.TypeOut, Time: out}},
+		}
+	}
+	credited := func(a *domain.Attendance, minutes int) []*domain.TimeBankEntry {
+		return []*domain.TimeBankEntry{{Kind: domain.TimeBankOvertime, Minutes: minutes, AttendanceID: &a.ID}}
+	}
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		existing int
+		mutate   func(a *domain.Attendance)
+		want     string
+	}{
+		{"credits a new attendance", true, 0, nil, "[120]"},
+		{"nothing changes", true, 120, nil, "[]"},
+		{"edit adds the difference", true, 60, nil, "[60]"},
+		{"void reverses the credit", true, 120, func(a *domain.Attendance) { now := at(21); a.VoidedAt = &now }, "[-120]"},
+		{"disabled bank does not credit", false, 0, nil, "[]"},
+		{"disabled bank does not add to an edit", false, 60, nil, "[]"},
+		{"disabled bank still reverses a void", false, 120, func(a *domain.Attendance) { now := at(21); a.VoidedAt = &now }, "[-120]"},
+		{"disabled bank still reverses a shorter day", false, 120, func(a *domain.Attendance) {
+			out := at(19)
+			a.CheckOutTime = &out
+			a.Punches[1].Time = out
+		}, "[-60]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attendance := newAttendance()
+			repo := &fakeTimeBankRepo{}
+			if tt.existing != 0 {
+				repo.entries = credited(attendance, tt.existing)
+			}
+			if tt.mutate != nil {
+				tt.mutate(attendance)
+			}
+			attendanceRepo := &fakeLockingAttendanceRepo{}
+			svc := &TimeBankService{timeBankRepo: repo, attendanceRepo: attendanceRepo}
+
+			before := len(repo.entries)
+			if err := svc.syncAttendance(context.Background(), &domain.Agency{TimeBankEnabled: tt.enabled}, attendance); err != nil {
+				t.Fatalf("syncAttendance() error = %v", err)
+			}
+
+			var got []int
+			for _, e := range repo.entries[before:] {
+				got = append(got, e.Minutes)
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("syncAttendance() created %v, want %s", got, tt.want)
+			}
+			if len(attendanceRepo.locked) != 1 || attendanceRepo.locked[0] != attendance.ID {
+				t.Errorf("syncAttendance() locked %v, want the attendance row", attendanceRepo.locked)
+			}
+		})
+	}
+}
+
+func TestPlanTimeBankExpiries(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	entry := func(date time.Time, kind domain.TimeBankEntryKind, minutes int) *domain.TimeBankEntry {
+		return &domain.TimeBankEntry{ID: uuid.New(), Date: date, Kind: kind, Minutes: minutes}
+	}
+	format := func(date time.Time, minutes int) string {
+		return fmt.Sprintf("%s %d", date.Format("2006-01-02"), minutes)
+	}
+
+	first := entry(day(1, 1), domain.TimeBankOvertime, 60)
+	alreadyExpired := entry(day(1, 31), domain.TimeBankExpiry, -60)
+	alreadyExpired.ExpiredEntryID = &first.ID
+
+	tests := []struct {
+		name        string
+		entries     []*domain.TimeBankEntry
+		expiryDays  int
+		today       time.Time
+		wantExpired []string
+		wantLots    []string
+	}{
+		{
+			name:        "unused credit expires",
+			entries:     []*domain.TimeBankEntry{first},
+			expiryDays:  30,
+			today:       day(2, 15),
+			wantExpired: []string{"2026-01-31 -60"},
+		},
+		{
+			name:       "credit not yet due",
+			entries:    []*domain.TimeBankEntry{first},
+			expiryDays: 30,
+			today:      day(1, 30),
+			wantLots:   []string{"2026-01-01 60"},
+		},
+		{
+			name:        "comp time uses the oldest credit first",
+			entries:     []*domain.TimeBankEntry{first, entry(day(1, 10), domain.TimeBankOvertime, 60), entry(day(1, 15), domain.TimeBankCompTime, -90)},
+			expiryDays:  30,
+			today:       day(2, 15),
+			wantExpired: []string{"2026-02-09 -30"},
+		},
+		{
+			name:       "comp time leaves the rest of the credit",
+			entries:    []*domain.TimeBankEntry{first, entry(day(1, 5), domain.TimeBankCompTime, -40)},
+			expiryDays: 30,
+			today:      day(1, 20),
+			wantLots:   []string{"2026-01-01 20"},
+		},
+		{
+			name:        "debt is paid by the next credit",
+			entries:     []*domain.TimeBankEntry{entry(day(1, 1), domain.TimeBankEarlyLeave, -30), entry(day(1, 2), domain.TimeBankOvertime, 60)},
+			expiryDays:  30,
+			today:       day(2, 15),
+			wantExpired: []string{"2026-02-01 -30"},
+		},
+		{
+			name:       "comp time after the expiry cannot use the expired credit",
+			entries:    []*domain.TimeBankEntry{first, alreadyExpired, entry(day(2, 5), domain.TimeBankOvertime, 60), entry(day(2, 10), domain.TimeBankCompTime, -30)},
+			expiryDays: 30,
+			today:      day(2, 15),
+			wantLots:   []string{"2026-02-05 30"},
+		},
+		{
+			name:       "expiry already registered",
+			entries:    []*domain.TimeBankEntry{first, alreadyExpired},
+			expiryDays: 30,
+			today:      day(2, 15),
+		},
+		{
+			name:       "credits do not expire without a term",
+			entries:    []*domain.TimeBankEntry{first},
+			expiryDays: 0,
+			today:      day(12, 31),
+			wantLots:   []string{"2026-01-01 60"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planned, lots := planTimeBankExpiries(tt.entries, tt.expiryDays, tt.today)
+
+			var expired []string
+			for _, e := range planned {
+				expired = append(expired, format(e.Date, e.Minutes))
+			}
+			var remaining []string
+			for _, l := range lots {
+				remaining = append(remaining, format(l.entry.Date, l.remaining))
+			}
+			if fmt.Sprint(expired) != fmt.Sprint(tt.wantExpired) {
+				t.Errorf("planned expiries = %v, want %v", expired, tt.wantExpired)
+			}
+			if fmt.Sprint(remaining) != fmt.Sprint(tt.wantLots) {
+				t.Errorf("remaining lots = %v, want %v", remaining, tt.wantLots)
+			}
+		})
+	}
+}
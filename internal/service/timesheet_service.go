@@ -0,0 +1,471 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TimesheetService struct {
+	timesheetRepo  domain.TimesheetRepo
+	attendanceRepo domain.AttendanceRepo
+	userRepo       domain.UserRepo
+	agencyRepo     domain.AgencyRepo
+	scheduleSvc    *ScheduleService
+	transactor     domain.Transactor
+}
+
+func NewTimesheetService(
+	timesheetRepo domain.TimesheetRepo,
+	attendanceRepo domain.AttendanceRepo,
+	userRepo domain.UserRepo,
+	agencyRepo domain.AgencyRepo,
+	scheduleSvc *ScheduleService,
+	transactor domain.Transactor,
+) *TimesheetService {
+	return &TimesheetService{
+		timesheetRepo:  timesheetRepo,
+		attendanceRepo: attendanceRepo,
+		userRepo:       userRepo,
+		agencyRepo:     agencyRepo,
+		scheduleSvc:    scheduleSvc,
+		transactor:     transactor,
+	}
+}
+
+// Get devuelve la planilla del usuario para period (YYYY-MM). Una planilla cerrada muestra lo que se aceptó;
+// las demás se calculan con los datos actuales e indican si cambiaron desde el acuse.
+func (s *TimesheetService) Get(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, period string) (*dto.TimesheetResponse, error) {
+	agency, err := s.getAgency(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getUser(ctx, agencyID, userID); err != nil {
+		return nil, err
+	}
+
+	start, end, err := parsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet, err := s.timesheetRepo.GetByUserAndPeriod(ctx, userID, start)
+	if err != nil {
+		return nil, err
+	}
+	if timesheet != nil && timesheet.Status == domain.TimesheetLocked {
+		return dto.ToTimesheetResponse(timesheet), nil
+	}
+
+	days, totals, err := s.compute(ctx, agency, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if timesheet == nil {
+		return &dto.TimesheetResponse{
+			UserID:    userID,
+			Period:    start.Format("2006-01"),
+			StartDate: start.Format("2006-01-02"),
+			EndDate:   end.Format("2006-01-02"),
+			Status:    domain.TimesheetOpen,
+			Days:      days,
+			Totals:    totals,
+		}, nil
+	}
+
+	response := dto.ToTimesheetResponse(timesheet)
+	response.Days = days
+	response.Totals = totals
+	response.Changed = timesheet.Status == domain.TimesheetAcknowledged && timesheetHash(days, totals) != timesheet.ContentHash
+	return response, nil
+}
+
+// List devuelve el estado de la planilla de period para cada usuario activo de la agencia
+func (s *TimesheetService) List(ctx context.Context, agencyID uuid.UUID, params *dto.TimesheetListParams) ([]*dto.TimesheetSummaryResponse, error) {
+	start, _, err := parsePeriod(params.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.ListByAgencyID(ctx, agencyID, domain.UserFilter{
+		Status: string(domain.StatusActive),
+		Page:   params.Page,
+		Limit:  params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, len(users))
+	for i, u := range users {
+		userIDs[i] = u.ID
+	}
+	timesheets, err := s.timesheetRepo.ListByPeriod(ctx, agencyID, start, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uuid.UUID]*domain.Timesheet, len(timesheets))
+	for _, t := range timesheets {
+		byUser[t.UserID] = t
+	}
+
+	responses := make([]*dto.TimesheetSummaryResponse, len(users))
+	for i, u := range users {
+		summary := &dto.TimesheetSummaryResponse{
+			UserID:    u.ID,
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Period:    start.Format("2006-01"),
+			Status:    domain.TimesheetOpen,
+		}
+		if t := byUser[u.ID]; t != nil && t.Status != domain.TimesheetOpen {
+			summary.Status = t.Status
+			summary.Totals = &t.Totals
+			summary.AcknowledgedAt = t.AcknowledgedAt
+			summary.LockedAt = t.LockedAt
+		}
+		responses[i] = summary
+	}
+	return responses, nil
+}
+
+// Acknowledge registra que el empleado revisó y acepta su planilla de un mes ya terminado: se guarda
+// el contenido que vio, cuándo y desde qué IP. Se puede volver a aceptar mientras no esté cerrada.
+func (s *TimesheetService) Acknowledge(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID, req *dto.AcknowledgeTimesheetRequest, ip string) (*dto.TimesheetResponse, error) {
+	agency, err := s.getAgency(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := parsePeriod(req.Period)
+	if err != nil {
+		return nil, err
+	}
+	if !agencyToday(agency).After(end) {
+		return nil, domain.ErrTimesheetPeriodNotEnded
+	}
+
+	var timesheet *domain.Timesheet
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		timesheet, err = s.timesheetRepo.GetByUserAndPeriodForUpdate(txCtx, userID, start)
+		if err != nil {
+			return err
+		}
+		if timesheet != nil && timesheet.Status == domain.TimesheetLocked {
+			return domain.ErrTimesheetLocked
+		}
+
+		days, totals, err := s.compute(txCtx, agency, userID, start, end)
+		if err != nil {
+			return err
+		}
+
+		isNew := timesheet == nil
+		if isNew {
+			timesheet = &domain.Timesheet{
+				AgencyID:    agencyID,
+				UserID:      userID,
+				PeriodStart: start,
+				PeriodEnd:   end,
+			}
+		}
+
+		now := time.Now()
+		timesheet.Status = domain.TimesheetAcknowledged
+		timesheet.Days = days
+		timesheet.Totals = totals
+		timesheet.ContentHash = timesheetHash(days, totals)
+		timesheet.AcknowledgedAt = &now
+		timesheet.AcknowledgedIP = &ip
+
+		if isNew {
+			return s.timesheetRepo.Create(txCtx, timesheet)
+		}
+		return s.timesheetRepo.Update(txCtx, timesheet)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToTimesheetResponse(timesheet), nil
+}
+
+// Lock cierra una planilla aceptada. Si la asistencia cambió después del acuse el empleado debe
+// aceptarla de nuevo. Desde entonces no se pueden editar registros del período.
+func (s *TimesheetService) Lock(ctx context.Context, agencyID uuid.UUID, adminID uuid.UUID, req *dto.TimesheetActionRequest) (*dto.TimesheetResponse, error) {
+	agency, err := s.getAgency(ctx, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getUser(ctx, agencyID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	start, end, err := parsePeriod(req.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	var timesheet *domain.Timesheet
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		timesheet, err = s.timesheetRepo.GetByUserAndPeriodForUpdate(txCtx, req.UserID, start)
+		if err != nil {
+			return err
+		}
+		if timesheet == nil || timesheet.Status == domain.TimesheetOpen {
+			return domain.ErrTimesheetNotAcknowledged
+		}
+		if timesheet.Status == domain.TimesheetLocked {
+			return domain.ErrTimesheetLocked
+		}
+
+		days, totals, err := s.compute(txCtx, agency, req.UserID, start, end)
+		if err != nil {
+			return err
+		}
+		if timesheetHash(days, totals) != timesheet.ContentHash {
+			return domain.ErrTimesheetChanged
+		}
+
+		now := time.Now()
+		timesheet.Status = domain.TimesheetLocked
+		timesheet.LockedAt = &now
+		timesheet.LockedByID = &adminID
+		return s.timesheetRepo.Update(txCtx, timesheet)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToTimesheetResponse(timesheet), nil
+}
+
+// Unlock reabre una planilla cerrada para corregir la asistencia del período; el acuse se descarta
+// y el empleado debe aceptarla de nuevo
+func (s *TimesheetService) Unlock(ctx context.Context, agencyID uuid.UUID, req *dto.TimesheetActionRequest) (*dto.TimesheetResponse, error) {
+	if _, err := s.getUser(ctx, agencyID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	start, _, err := parsePeriod(req.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	var timesheet *domain.Timesheet
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		timesheet, err = s.timesheetRepo.GetByUserAndPeriodForUpdate(txCtx, req.UserID, start)
+		if err != nil {
+			return err
+		}
+		if timesheet == nil || timesheet.Status != domain.TimesheetLocked {
+			return domain.ErrTimesheetNotLocked
+		}
+
+		timesheet.Status = domain.TimesheetOpen
+		timesheet.AcknowledgedAt = nil
+		timesheet.AcknowledgedIP = nil
+		timesheet.LockedAt = nil
+		timesheet.LockedByID = nil
+		return s.timesheetRepo.Update(txCtx, timesheet)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToTimesheetResponse(timesheet), nil
+}
+
+// checkUnlocked rechaza los cambios de asistencia en un día que pertenece a una planilla cerrada.
+// Bloquea la planilla del día hasta el fin de la transacción, así un cierre simultáneo espera
+// a que termine el cambio y lo detecta al comparar la huella.
+func (s *TimesheetService) checkUnlocked(ctx context.Context, userID uuid.UUID, date time.Time) error {
+	timesheet, err := s.timesheetRepo.GetForDateForUpdate(ctx, userID, date)
+	if err != nil {
+		return err
+	}
+	if timesheet != nil && timesheet.Status == domain.TimesheetLocked {
+		return domain.ErrTimesheetLocked
+	}
+	return nil
+}
+
+// compute arma la planilla del período con los horarios, permisos y feriados de cada día y la asistencia registrada
+func (s *TimesheetService) compute(ctx context.Context, agency *domain.Agency, userID uuid.UUID, start time.Time, end time.Time) ([]domain.TimesheetDay, domain.TimesheetTotals, error) {
+	var totals domain.TimesheetTotals
+
+	attendances, err := s.attendanceRepo.List(ctx, agency.ID, domain.AttendanceFilter{
+		UserID:    userID,
+		StartDate: &start,
+		EndDate:   &end,
+	})
+	if err != nil {
+		return nil, totals, err
+	}
+	byDate := make(map[string]*domain.Attendance, len(attendances))
+	for _, a := range attendances {
+		byDate[a.Date.Format("2006-01-02")] = a
+	}
+
+	days := make([]domain.TimesheetDay, 0, end.Day())
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		row := domain.TimesheetDay{Date: day.Format("2006-01-02")}
+		if err := s.scheduleDay(ctx, agency, userID, day, &row); err != nil {
+			return nil, totals, err
+		}
+		if attendance := byDate[row.Date]; attendance != nil {
+			attendanceDay(&row, attendance)
+		}
+
+		addTimesheetDay(&totals, &row)
+		days = append(days, row)
+	}
+	return days, totals, nil
+}
+
+// scheduleDay completa lo que se esperaba del usuario en day: los minutos del turno, el permiso o el feriado
+func (s *TimesheetService) scheduleDay(ctx context.Context, agency *domain.Agency, userID uuid.UUID, day time.Time, row *domain.TimesheetDay) error {
+	date := dateIn(day, agency.Location())
+
+	var onLeave *domain.OnLeaveError
+	var nonWorking *domain.NonWorkingDayError
+
+	sched, err := s.scheduleSvc.GetApplicableSchedule(ctx, agency.ID, userID, date)
+	switch {
+	case errors.As(err, &onLeave):
+		// El permiso solo cuenta en los días que tenían turno, como al solicitarlo
+		_, err := s.scheduleSvc.scheduleForDate(ctx, agency.ID, userID, date)
+		if errors.As(err, &nonWorking) {
+			row.Holiday = nonWorking.Holiday.Name
+			return nil
+		}
+		if errors.Is(err, domain.ErrNoScheduleFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		row.Leave = onLeave.Leave.LeaveType.Name
+		row.LeaveDays = 1
+	case errors.As(err, &nonWorking):
+		row.Holiday = nonWorking.Holiday.Name
+	case errors.Is(err, domain.ErrNoScheduleFound):
+		// Día libre
+	case err != nil:
+		return err
+	default:
+		row.ScheduledMinutes = scheduledLength(sched)
+		if sched.Leave != nil {
+			row.Leave = sched.Leave.LeaveType.Name
+			row.LeaveDays = 0.5
+		}
+	}
+	return nil
+}
+
+// attendanceDay copia en la fila los datos del registro del día
+func attendanceDay(row *domain.TimesheetDay, attendance *domain.Attendance) {
+	row.AttendanceID = &attendance.ID
+	row.CheckInTime = utcTime(attendance.CheckInTime)
+	row.CheckOutTime = utcTime(attendance.CheckOutTime)
+	row.EntryStatus = attendance.EntryStatus
+	row.ExitStatus = attendance.ExitStatus
+	row.WorkedMinutes = attendance.WorkedMinutes
+	row.OvertimeMinutes = attendance.OvertimeMinutes
+	row.DeficitMinutes = attendance.DeficitMinutes
+
+	if attendance.EntryStatus == domain.StatusAbsent {
+		row.Absent = true
+		return
+	}
+	row.LateMinutes = lateMinutes(attendance)
+	row.EarlyLeaveMinutes = earlyLeaveMinutes(attendance)
+}
+
+// utcTime copia t en UTC: la huella de la planilla no debe depender de la zona en que el driver devuelve las horas
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func addTimesheetDay(totals *domain.TimesheetTotals, row *domain.TimesheetDay) {
+	if row.ScheduledMinutes > 0 {
+		totals.ScheduledDays++
+	}
+	if row.CheckInTime != nil {
+		totals.WorkedDays++
+	}
+	if row.EntryStatus == domain.StatusLate {
+		totals.LateDays++
+	}
+	if row.ExitStatus != nil && *row.ExitStatus == domain.StatusEarly {
+		totals.EarlyLeaveDays++
+	}
+	if row.Absent {
+		totals.AbsenceDays++
+	}
+	if row.Holiday != "" {
+		totals.HolidayDays++
+	}
+
+	totals.ScheduledMinutes += row.ScheduledMinutes
+	totals.WorkedMinutes += row.WorkedMinutes
+	totals.OvertimeMinutes += row.OvertimeMinutes
+	totals.DeficitMinutes += row.DeficitMinutes
+	totals.LateMinutes += row.LateMinutes
+	totals.EarlyLeaveMinutes += row.EarlyLeaveMinutes
+	totals.LeaveDays += row.LeaveDays
+}
+
+// timesheetHash es la huella del contenido de la planilla, para saber si cambió desde que se aceptó
+func timesheetHash(days []domain.TimesheetDay, totals domain.TimesheetTotals) string {
+	content, _ := json.Marshal(struct {
+		Days   []domain.TimesheetDay  `json:"days"`
+		Totals domain.TimesheetTotals `json:"totals"`
+	}{days, totals})
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// parsePeriod convierte un mes (YYYY-MM) en su primer y último día, a medianoche UTC como se leen las columnas date
+func parsePeriod(period string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, time.Time{}, domain.ErrInvalidTimesheetPeriod
+	}
+	return start, start.AddDate(0, 1, -1), nil
+}
+
+func (s *TimesheetService) getAgency(ctx context.Context, agencyID uuid.UUID) (*domain.Agency, error) {
+	agency, err := s.agencyRepo.GetByID(ctx, agencyID)
+	if err != nil {
+		return nil, domain.ErrAgencyNotFound
+	}
+	return agency, nil
+}
+
+func (s *TimesheetService) getUser(ctx context.Context, agencyID uuid.UUID, userID uuid.UUID) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.AgencyID != agencyID {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"quickattendance-go/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTimesheetHash(t *testing.T) {
+	checkIn := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	days := func() []domain.TimesheetDay {
+		return []domain.TimesheetDay{
+			{Date: "2026-03-01"},
+			{Date: "2026-03-02", ScheduledMinutes: 540, CheckInTime: utcTime(&checkIn), WorkedMinutes: 540},
+		}
+	}
+	totals := domain.TimesheetTotals{ScheduledDays: 1, WorkedDays: 1, ScheduledMinutes: 540, WorkedMinutes: 540}
+	hash := timesheetHash(days(), totals)
+
+	if len(hash) != 64 {
+		t.Fatalf("timesheetHash() = %q, want a hex SHA-256", hash)
+	}
+	if got := timesheetHash(days(), totals); got != hash {
+		t.Errorf("timesheetHash() is not stable: %s != %s", got, hash)
+	}
+
+	// The driver may return the same instant in another zone
+	santiago := time.FixedZone("CLT", -3*60*60)
+	local := checkIn.In(santiago)
+	sameInstant := days()
+	sameInstant[1].CheckInTime = utcTime(&local)
+	if got := timesheetHash(sameInstant, totals); got != hash {
+		t.Errorf("timesheetHash() changed with the time zone of the same instant")
+	}
+
+	edited := days()
+	edited[1].WorkedMinutes = 530
+	if got := timesheetHash(edited, totals); got == hash {
+		t.Errorf("timesheetHash() did not change after editing a day")
+	}
+
+	changedTotals := totals
+	changedTotals.LateDays = 1
+	if got := timesheetHash(days(), changedTotals); got == hash {
+		t.Errorf("timesheetHash() did not change after editing the totals")
+	}
+}
+
+type fakeTimesheetRepo struct {
+	domain.TimesheetRepo
+	timesheet *domain.Timesheet
+}
+
+func (r *fakeTimesheetRepo) GetForDateForUpdate(ctx context.Context, userID uuid.UUID, date time.Time) (*domain.Timesheet, error) {
+	t := r.timesheet
+	if t == nil || t.UserID != userID || date.Before(t.PeriodStart) || date.After(t.PeriodEnd) {
+		return nil, nil
+	}
+	return t, nil
+}
+
+func TestTimesheetCheckUnlocked(t *testing.T) {
+	userID := uuid.New()
+	timesheet := func(status domain.TimesheetStatus) *domain.Timesheet {
+		return &domain.Timesheet{
+			UserID:      userID,
+			PeriodStart: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:   time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			Status:      status,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		timesheet *domain.Timesheet
+		date      time.Time
+		want      error
+	}{
+		{"no saved timesheet", nil, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), nil},
+		{"acknowledged", timesheet(domain.TimesheetAcknowledged), time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), nil},
+		{"locked", timesheet(domain.TimesheetLocked), time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), domain.ErrTimesheetLocked},
+		{"last day of a locked period", timesheet(domain.TimesheetLocked), time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), domain.ErrTimesheetLocked},
+		{"day after a locked period", timesheet(domain.TimesheetLocked), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &TimesheetService{timesheetRepo: &fakeTimesheetRepo{timesheet: tt.timesheet}}
+			if err := svc.checkUnlocked(context.Background(), userID, tt.date); !errors.Is(err, tt.want) {
+				t.Errorf("checkUnlocked() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
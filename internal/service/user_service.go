@@ -45,6 +45,15 @@ func (s *UserService) Invite(ctx context.Context, agencyID uuid.UUID, req *dto.I
 
 	expiry := time.Now().Add(time.Hour * 24)
 
+	var hireDate *time.Time
+	if req.HireDate != "" {
+		date, err := time.Parse("2006-01-02", req.HireDate)
+		if err != nil {
+			return domain.ErrInvalidUser
+		}
+		hireDate = &date
+	}
+
 	user := &domain.User{
 		FirstName:      req.FirstName,
 		LastName:       &req.LastName,
@@ -52,6 +61,7 @@ func (s *UserService) Invite(ctx context.Context, agencyID uuid.UUID, req *dto.I
 		AgencyID:       agencyID,
 		Role:           domain.RoleEmployee,
 		Status:         domain.StatusPending,
+		HireDate:       hireDate,
 		ActivationCode: &activationToken,
 		CodeExpiry:     &expiry,
 	}
@@ -183,6 +193,14 @@ func (s *UserService) UpdateUserProfile(ctx context.Context, agencyID uuid.UUID,
 		user.HomeRadiusMeters = req.HomeRadiusMeters
 	}
 
+	if req.HireDate != nil {
+		date, err := time.Parse("2006-01-02", *req.HireDate)
+		if err != nil {
+			return nil, domain.ErrInvalidUser
+		}
+		user.HireDate = &date
+	}
+
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
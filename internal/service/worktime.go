@@ -0,0 +1,124 @@
+package service
+
+import (
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"time"
+)
+
+// applyWorkTotals recalcula los minutos trabajados según las reglas de redondeo de la agencia y,
+// si el registro ya tiene checkout, persiste los minutos programados, horas extra y déficit.
+// En una jornada flexible solo cuenta lo trabajado dentro de la banda y se exigen los minutos requeridos.
+func applyWorkTotals(agency *domain.Agency, attendance *domain.Attendance) {
+	worked := workedMinutes(attendance.Punches)
+	if attendance.FlexBandStart != nil && attendance.FlexBandEnd != nil {
+		worked = workedMinutesBetween(attendance.Punches, *attendance.FlexBandStart, *attendance.FlexBandEnd)
+	}
+	attendance.WorkedMinutes = roundMinutes(worked, agency.WorkRoundingMinutes, agency.WorkRoundingMode)
+	attendance.ScheduledMinutes = 0
+	attendance.OvertimeMinutes = 0
+	attendance.DeficitMinutes = 0
+
+	if attendance.CheckOutTime == nil {
+		return
+	}
+
+	attendance.ScheduledMinutes = int(attendance.ScheduleExitTime.Sub(attendance.ScheduleEntryTime) / time.Minute)
+	if attendance.RequiredMinutes > 0 {
+		attendance.ScheduledMinutes = attendance.RequiredMinutes
+	}
+
+	diff := attendance.WorkedMinutes - attendance.ScheduledMinutes
+	switch {
+	case diff >= agency.MinOvertimeMinutes && diff > 0:
+		attendance.OvertimeMinutes = diff
+	case diff < 0:
+		attendance.DeficitMinutes = -diff
+	}
+
+	// Jornada flexible: cumplir el tiempo núcleo no basta si faltan minutos por completar
+	if attendance.RequiredMinutes > 0 && attendance.DeficitMinutes > 0 &&
+		attendance.ExitStatus != nil && *attendance.ExitStatus == domain.StatusOnTime {
+		short := domain.StatusShortHours
+		attendance.ExitStatus = &short
+	}
+}
+
+func roundMinutes(minutes int, interval int, mode domain.RoundingMode) int {
+	if interval <= 0 {
+		return minutes
+	}
+
+	switch mode {
+	case domain.RoundingFloor:
+		return minutes / interval * interval
+	case domain.RoundingCeil:
+		return (minutes + interval - 1) / interval * interval
+	default:
+		return (minutes + interval/2) / interval * interval
+	}
+}
+
+// timeBankMinutes devuelve lo que el registro aporta a la bolsa de horas: los minutos trabajados después
+// de la salida programada y los que faltaron por salir antes de tiempo. Ambos lados usan el redondeo de
+// la agencia y no cuentan bajo su mínimo de horas extra, así un minuto de diferencia no suma ni resta.
+// En una jornada flexible no hay una salida fija: cuentan las horas extra y el déficit del día.
+func timeBankMinutes(agency *domain.Agency, attendance *domain.Attendance) (credit int, debit int) {
+	if attendance.VoidedAt != nil || attendance.CheckOutTime == nil {
+		return 0, 0
+	}
+	if attendance.RequiredMinutes > 0 {
+		// applyWorkTotals ya redondeó lo trabajado y aplicó el mínimo a las horas extra
+		return attendance.OvertimeMinutes, bankableMinutes(agency, attendance.DeficitMinutes, false)
+	}
+
+	credit = workedMinutesBetween(attendance.Punches, attendance.ScheduleExitTime, *attendance.CheckOutTime)
+	credit = bankableMinutes(agency, credit, true)
+
+	if attendance.ExitStatus != nil && *attendance.ExitStatus == domain.StatusEarly {
+		debit = int(attendance.ScheduleExitTime.Sub(*attendance.CheckOutTime) / time.Minute)
+		debit = bankableMinutes(agency, debit, true)
+	}
+	return credit, debit
+}
+
+// bankableMinutes aplica el redondeo de la agencia (si round) y descarta lo que no alcanza su mínimo de horas extra
+func bankableMinutes(agency *domain.Agency, minutes int, round bool) int {
+	if round {
+		minutes = roundMinutes(minutes, agency.WorkRoundingMinutes, agency.WorkRoundingMode)
+	}
+	if minutes < agency.MinOvertimeMinutes {
+		return 0
+	}
+	return minutes
+}
+
+// lateMinutes devuelve cuánto después de la hora de entrada marcó un registro atrasado
+func lateMinutes(attendance *domain.Attendance) int {
+	if attendance.EntryStatus != domain.StatusLate || attendance.CheckInTime == nil {
+		return 0
+	}
+	return int(attendance.CheckInTime.Sub(attendance.ScheduleEntryTime) / time.Minute)
+}
+
+// earlyLeaveMinutes devuelve cuánto antes de la hora de salida marcó un registro con salida anticipada
+func earlyLeaveMinutes(attendance *domain.Attendance) int {
+	if attendance.ExitStatus == nil || *attendance.ExitStatus != domain.StatusEarly || attendance.CheckOutTime == nil {
+		return 0
+	}
+	return int(attendance.ScheduleExitTime.Sub(*attendance.CheckOutTime) / time.Minute)
+}
+
+// scheduledLength devuelve los minutos que se esperan trabajar con el horario resuelto de un día:
+// los requeridos en una jornada flexible, si no la duración del turno (un turno de 24 horas empieza y
+// termina a la misma hora)
+func scheduledLength(sched *dto.ScheduleResponse) int {
+	if sched.Type == domain.ScheduleFlexible {
+		return sched.RequiredMinutes
+	}
+	length := domain.BandOffset(sched.EntryTimeMinutes, sched.ExitTimeMinutes)
+	if length == 0 {
+		length = 24 * 60
+	}
+	return length
+}
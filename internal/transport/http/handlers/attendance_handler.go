@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
 	"quickattendance-go/internal/domain"
 	"quickattendance-go/internal/dto"
 	"quickattendance-go/internal/service"
+	"quickattendance-go/pkg/xlsx"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -20,7 +25,7 @@ func NewAttendanceHandler(svc *service.AttendanceService) *AttendanceHandler {
 
 // Mark godoc
 // @Summary Mark attendance
-// @Description Marks user attendance for today. Geolocation check is applied for remote work.
+// @Description Registers a punch (in, out, break_start, break_end) on the daily attendance of the current shift. Punches after check-in attach to the open shift, even if it started the previous day. A new "in" after an "out" starts another interval (split shift). Geolocation check is applied for remote work. Method "qr" requires the qr_token shown by the agency's QR code; each code can be used once per user. Method "nfc" requires the nfc_tag_uid of a registered, active tag of the agency. Non-remote punches with coordinates record the site whose geofence contains them; if the agency requires on-site attendance, punches outside every site are rejected with the nearest site and distance. Geofences can be circles or polygons; "accuracy" (meters) is the fix's uncertainty radius, which must overlap or fit inside the fence depending on the agency's geofence_strictness, and is required and capped when the agency sets max_location_accuracy_meters.
 // @Tags attendance
 // @Accept json
 // @Produce json
@@ -28,7 +33,9 @@ func NewAttendanceHandler(svc *service.AttendanceService) *AttendanceHandler {
 // @Success 200 {object} domain.Attendance
 // @Failure 400 {object} map[string]string
 // @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /attendance/mark [post]
@@ -55,18 +62,92 @@ func (h *AttendanceHandler) Mark(c *gin.Context) {
 
 	res, err := h.svc.MarkAttendance(c.Request.Context(), &req)
 	if err != nil {
+		var outside *domain.OutsideSiteError
+		if errors.As(err, &outside) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":           "you are outside the allowed sites",
+				"nearest_site_id": outside.NearestSiteID,
+				"nearest_site":    outside.NearestSiteName,
+				"distance_meters": math.Round(outside.DistanceMeters),
+			})
+			return
+		}
+		if err == domain.ErrLocationRequired {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "latitude and longitude are required for on-site attendance"})
+			return
+		}
+		if err == domain.ErrAccuracyRequired {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "accuracy is required when sending a location"})
+			return
+		}
+		if err == domain.ErrLocationImprecise {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "location is too imprecise, wait for a better gps fix"})
+			return
+		}
+		if err == domain.ErrNoSitesConfigured {
+			c.JSON(http.StatusForbidden, gin.H{"error": "on-site attendance is required but the agency has no active sites"})
+			return
+		}
 		if err == domain.ErrAttendanceExists {
 			c.JSON(http.StatusConflict, gin.H{"error": "attendance already registered for today"})
 			return
 		}
+		if err == domain.ErrAttendanceNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no open attendance for this punch"})
+			return
+		}
+		if err == domain.ErrInvalidPunchOrder {
+			c.JSON(http.StatusConflict, gin.H{"error": "punch out of order for the current attendance"})
+			return
+		}
+		if err == domain.ErrTimesheetLocked {
+			c.JSON(http.StatusConflict, gin.H{"error": "the timesheet for this shift is locked"})
+			return
+		}
 		if err == domain.ErrNoScheduleFound {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "no schedule found for today"})
 			return
 		}
+		if errors.Is(err, domain.ErrOnLeave) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "you are on approved leave today"})
+			return
+		}
+		if errors.Is(err, domain.ErrNonWorkingDay) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "today is a non-working day"})
+			return
+		}
 		if err == domain.ErrManualNotAllowed {
 			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can mark attendance manually"})
 			return
 		}
+		if err == domain.ErrQRTokenRequired {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "qr_token is required for qr method"})
+			return
+		}
+		if err == domain.ErrQRTokenInvalid {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid qr code"})
+			return
+		}
+		if err == domain.ErrQRTokenExpired {
+			c.JSON(http.StatusForbidden, gin.H{"error": "qr code expired, scan the current one"})
+			return
+		}
+		if err == domain.ErrQRTokenReplayed {
+			c.JSON(http.StatusConflict, gin.H{"error": "qr code already used, scan the current one"})
+			return
+		}
+		if err == domain.ErrNFCTagRequired {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "nfc_tag_uid is required for nfc method"})
+			return
+		}
+		if err == domain.ErrNFCTagNotFound {
+			c.JSON(http.StatusForbidden, gin.H{"error": "nfc tag not registered in this agency"})
+			return
+		}
+		if err == domain.ErrNFCTagInactive {
+			c.JSON(http.StatusForbidden, gin.H{"error": "nfc tag is inactive"})
+			return
+		}
 		if err == domain.ErrGeofenceViolation {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "you are out of the allowed range from your home"})
 			return
@@ -90,6 +171,27 @@ func (h *AttendanceHandler) Mark(c *gin.Context) {
 	c.JSON(http.StatusOK, res)
 }
 
+// QRToken godoc
+// @Summary Current attendance QR code (Admin)
+// @Description Returns the signed token the agency's QR display must show right now. It rotates every QR_TOKEN_ROTATION and stays valid for one extra rotation; the display should fetch a new one at refresh_at.
+// @Tags attendance
+// @Produce json
+// @Success 200 {object} dto.QRTokenResponse
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/qr [get]
+func (h *AttendanceHandler) QRToken(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	res, err := h.svc.GetQRToken(c.Request.Context(), agencyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
 // List godoc
 // @Summary List attendance records
 // @Description Returns a list of attendance records for the agency. Employees can only see their own records.
@@ -97,6 +199,9 @@ func (h *AttendanceHandler) Mark(c *gin.Context) {
 // @Produce json
 // @Param user_id query string false "User ID filter (Admins only)"
 // @Param date query string false "Date filter (YYYY-MM-DD)"
+// @Param status query string false "Entry or exit status"
+// @Param entry_status query string false "Entry status (present, late, absent)"
+// @Param exit_status query string false "Exit status (on_time, early, short_hours, absent)"
 // @Success 200 {array} domain.Attendance
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -130,3 +235,345 @@ func (h *AttendanceHandler) List(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+// Export godoc
+// @Summary Export attendance records
+// @Description Downloads the attendance records matching the same filters as /attendance/list as a CSV or XLSX file, with user names, dates, check-in and check-out times (agency timezone), methods, statuses, site and computed durations in minutes (scheduled, worked, break, overtime, deficit, late, early leave). Pagination is ignored: every matching record is exported, ordered by date, and the file is streamed so large ranges do not need to fit in memory. Column labels follow lang, then the Accept-Language header, then Spanish. Employees can only export their own records.
+// @Tags attendance
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format (csv, xlsx). Default: csv"
+// @Param lang query string false "Column labels language (es, en)"
+// @Param user_id query string false "User ID filter (Admins only)"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param status query string false "Entry or exit status"
+// @Param entry_status query string false "Entry status (present, late, absent)"
+// @Param exit_status query string false "Exit status (on_time, early, short_hours, absent)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/export [get]
+func (h *AttendanceHandler) Export(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.AttendanceExportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Security: Employees can only export their own attendance
+	role := c.MustGet("role").(domain.Role)
+	if role == domain.RoleEmployee {
+		params.UserID = c.MustGet("user_id").(uuid.UUID).String()
+	} else if params.UserID != "" {
+		if _, err := uuid.Parse(params.UserID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+			return
+		}
+	}
+
+	lang := exportLang(c, params.Lang)
+	name := attendanceExportNames[service.ExportLanguage(lang)]
+
+	var table interface {
+		service.TableWriter
+		Close() error
+	}
+	if params.Format == "xlsx" {
+		c.Header("Content-Type", xlsx.ContentType)
+		table = xlsx.NewWriter(c.Writer, name)
+	} else {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		table = newCSVTable(c.Writer)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, params.Format))
+
+	err := h.svc.ExportAttendances(c.Request.Context(), agencyID, &params.AttendanceListParams, lang, table)
+	if err == nil {
+		err = table.Close()
+	}
+	if err != nil {
+		// Si el archivo ya empezó a enviarse el estado no se puede cambiar: se corta la conexión para que
+		// el cliente no reciba un archivo truncado como si estuviera completo
+		if c.Writer.Written() {
+			slog.Error("Attendance export interrupted", "agency_id", agencyID, "error", err)
+			panic(http.ErrAbortHandler)
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
+
+// Nombre del archivo exportado y de la hoja xlsx, por idioma
+var attendanceExportNames = map[string]string{
+	"es": "asistencias",
+	"en": "attendance",
+}
+
+// Suspicious godoc
+// @Summary List suspicious attendance (Admin)
+// @Description Returns attendance records flagged for review because their locations look spoofed: impossible travel since the previous punch, exact coordinates reused from another day, or an implausible precision (accuracy below 1 m or coordinates rounded to 4 decimals). Reused coordinates and implausible precision only flag a record together with another signal. Resolved flags are no longer listed. Newest first.
+// @Tags attendance
+// @Produce json
+// @Param user_id query string false "User ID filter"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.AttendanceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/suspicious [get]
+func (h *AttendanceHandler) Suspicious(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.AttendanceListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if params.UserID != "" {
+		if _, err := uuid.Parse(params.UserID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+			return
+		}
+	}
+
+	res, err := h.svc.GetSuspiciousAttendances(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// History godoc
+// @Summary Attendance change history
+// @Description Returns the changes applied to an attendance record after it was registered (approved corrections, admin edits and voids), with previous and new values and who made them. Employees can only see their own records.
+// @Tags attendance
+// @Produce json
+// @Param id path string true "Attendance ID"
+// @Success 200 {array} dto.AttendanceRevisionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/{id}/history [get]
+func (h *AttendanceHandler) History(c *gin.Context) {
+	attendanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil || attendanceID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance ID"})
+		return
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	userID := c.MustGet("user_id").(uuid.UUID)
+	role := c.MustGet("role").(domain.Role)
+
+	res, err := h.svc.GetHistory(c.Request.Context(), agencyID, attendanceID, userID, role)
+	if err != nil {
+		if err == domain.ErrAttendanceNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "attendance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Create godoc
+// @Summary Create attendance for any date (Admin)
+// @Description Records an attendance for a user on an arbitrary date with explicit check-in and optional check-out times (e.g. the employee's phone died). Statuses are computed from the schedule applicable on that date. The check-in must fall within that date's shift in the agency time zone (from midnight until the next midnight, or until the scheduled exit for overnight shifts) and the check-out within 24 hours of it, otherwise 400. The reason is mandatory and stored in the attendance history. Dates within a locked timesheet are rejected with 409.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Param request body dto.AdminCreateAttendanceRequest true "Attendance details"
+// @Success 201 {object} dto.AttendanceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance [post]
+func (h *AttendanceHandler) Create(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	var req dto.AdminCreateAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.AdminCreate(c.Request.Context(), agencyID, adminID, &req)
+	if err != nil {
+		if err == domain.ErrAttendanceExists {
+			c.JSON(http.StatusConflict, gin.H{"error": "attendance already registered for this date, edit it instead"})
+			return
+		}
+		handleAdminAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// Update godoc
+// @Summary Edit attendance (Admin)
+// @Description Changes the check-in time (first "in" punch), the check-out time (last "out" punch, added if missing) and/or the notes of an attendance record. Statuses and totals are recalculated and the previous values are stored in the attendance history with the mandatory reason. The times must stay within the shift of the record's date, as on creation (400). Records within a locked timesheet cannot be edited (409).
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Param id path string true "Attendance ID"
+// @Param request body dto.AdminUpdateAttendanceRequest true "Changes"
+// @Success 200 {object} dto.AttendanceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/{id} [put]
+func (h *AttendanceHandler) Update(c *gin.Context) {
+	attendanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil || attendanceID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance ID"})
+		return
+	}
+
+	var req dto.AdminUpdateAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	res, err := h.svc.AdminUpdate(c.Request.Context(), agencyID, adminID, attendanceID, &req)
+	if err != nil {
+		handleAdminAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Void godoc
+// @Summary Void attendance (Admin)
+// @Description Voids an attendance record. It is kept with its history but excluded from listings, and the day can be registered again. The reason is mandatory. Records within a locked timesheet cannot be voided (409).
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Param id path string true "Attendance ID"
+// @Param request body dto.VoidAttendanceRequest true "Reason"
+// @Success 200 {object} dto.AttendanceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/{id}/void [post]
+func (h *AttendanceHandler) Void(c *gin.Context) {
+	attendanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil || attendanceID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance ID"})
+		return
+	}
+
+	var req dto.VoidAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	res, err := h.svc.Void(c.Request.Context(), agencyID, adminID, attendanceID, &req)
+	if err != nil {
+		handleAdminAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ResolveFlag godoc
+// @Summary Resolve a suspicious attendance flag (Admin)
+// @Description Clears the review flag of a suspicious attendance once an admin has checked it. The fraud signals and score are kept, and the record is only flagged again if a later punch adds a signal it did not have. The reason is mandatory and recorded in the history. Returns 409 if the record is not flagged.
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Param id path string true "Attendance ID"
+// @Param request body dto.ResolveFlagRequest true "Reason"
+// @Success 200 {object} dto.AttendanceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/{id}/resolve-flag [post]
+func (h *AttendanceHandler) ResolveFlag(c *gin.Context) {
+	attendanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil || attendanceID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance ID"})
+		return
+	}
+
+	var req dto.ResolveFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	res, err := h.svc.ResolveFlag(c.Request.Context(), agencyID, adminID, attendanceID, &req)
+	if err != nil {
+		handleAdminAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func handleAdminAttendanceError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNonWorkingDay) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no work expected on this date"})
+		return
+	}
+
+	switch err {
+	case domain.ErrAttendanceNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "attendance not found"})
+	case domain.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case domain.ErrAttendanceVoided:
+		c.JSON(http.StatusConflict, gin.H{"error": "attendance has been voided"})
+	case domain.ErrAttendanceNotFlagged:
+		c.JSON(http.StatusConflict, gin.H{"error": "attendance is not flagged for review"})
+	case domain.ErrTimesheetLocked:
+		c.JSON(http.StatusConflict, gin.H{"error": "the timesheet for this date is locked"})
+	case domain.ErrInvalidPunchOrder:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "check-out must be after check-in and keep the punch order"})
+	case domain.ErrAttendanceInFuture:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "attendance times cannot be in the future"})
+	case domain.ErrOutsideShiftWindow:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "check-in must fall within the shift of the given date and check-out within 24 hours of it"})
+	case domain.ErrNoScheduleFound:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no schedule found for this date"})
+	case domain.ErrInvalidAttendance:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance data"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CorrectionHandler struct {
+	svc *service.CorrectionService
+}
+
+func NewCorrectionHandler(svc *service.CorrectionService) *CorrectionHandler {
+	return &CorrectionHandler{svc: svc}
+}
+
+// Create godoc
+// @Summary Request an attendance correction
+// @Description Submits a correction request for the current user's attendance: a missing punch (punch_type + requested_time) or a wrong punch time (attendance_id + punch_id + requested_time). Dates within a locked timesheet are rejected with 409. Admins of the agency are notified by email.
+// @Tags corrections
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCorrectionRequest true "Correction details"
+// @Success 201 {object} dto.CorrectionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/corrections [post]
+func (h *CorrectionHandler) Create(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req dto.CreateCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.Submit(c.Request.Context(), agencyID, userID, &req)
+	if err != nil {
+		if err == domain.ErrInvalidCorrection {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid correction request"})
+			return
+		}
+		if err == domain.ErrCorrectionInTheFuture {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "requested time cannot be in the future"})
+			return
+		}
+		if err == domain.ErrAttendanceNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "attendance not found"})
+			return
+		}
+		if err == domain.ErrPunchNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "punch not found"})
+			return
+		}
+		if err == domain.ErrTimesheetLocked {
+			c.JSON(http.StatusConflict, gin.H{"error": "the timesheet for this date is locked"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// List godoc
+// @Summary List correction requests
+// @Description Returns the correction requests of the agency. Employees can only see their own requests.
+// @Tags corrections
+// @Produce json
+// @Param user_id query string false "User ID filter (Admins only)"
+// @Param status query string false "Status (pending, approved, rejected)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.CorrectionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/corrections [get]
+func (h *CorrectionHandler) List(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.CorrectionListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Security: Employees can only see their own requests
+	role := c.MustGet("role").(domain.Role)
+	if role == domain.RoleEmployee {
+		params.UserID = c.MustGet("user_id").(uuid.UUID).String()
+	} else if params.UserID != "" {
+		if _, err := uuid.Parse(params.UserID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+			return
+		}
+	}
+
+	res, err := h.svc.List(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Approve godoc
+// @Summary Approve a correction request
+// @Description Applies the correction to the attendance record (creating it for a missing check-in), recalculates statuses and totals, records the previous values in the attendance history and notifies the employee.
+// @Tags corrections
+// @Accept json
+// @Produce json
+// @Param id path string true "Correction ID"
+// @Param request body dto.ReviewCorrectionRequest false "Review note"
+// @Success 200 {object} dto.CorrectionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/corrections/{id}/approve [post]
+func (h *CorrectionHandler) Approve(c *gin.Context) {
+	h.review(c, h.svc.Approve)
+}
+
+// Reject godoc
+// @Summary Reject a correction request
+// @Description Rejects a pending correction request and notifies the employee. The attendance record is not modified.
+// @Tags corrections
+// @Accept json
+// @Produce json
+// @Param id path string true "Correction ID"
+// @Param request body dto.ReviewCorrectionRequest false "Review note"
+// @Success 200 {object} dto.CorrectionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /attendance/corrections/{id}/reject [post]
+func (h *CorrectionHandler) Reject(c *gin.Context) {
+	h.review(c, h.svc.Reject)
+}
+
+type reviewFunc func(ctx context.Context, agencyID uuid.UUID, reviewerID uuid.UUID, correctionID uuid.UUID, req *dto.ReviewCorrectionRequest) (*dto.CorrectionResponse, error)
+
+func (h *CorrectionHandler) review(c *gin.Context, review reviewFunc) {
+	correctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil || correctionID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid correction ID"})
+		return
+	}
+
+	// La nota es opcional: un body vacío es válido
+	var req dto.ReviewCorrectionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	reviewerID := c.MustGet("user_id").(uuid.UUID)
+
+	res, err := review(c.Request.Context(), agencyID, reviewerID, correctionID, &req)
+	if err != nil {
+		if err == domain.ErrCorrectionNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "correction request not found"})
+			return
+		}
+		if err == domain.ErrCorrectionNotPending {
+			c.JSON(http.StatusConflict, gin.H{"error": "correction request already reviewed"})
+			return
+		}
+		if err == domain.ErrInvalidPunchOrder {
+			c.JSON(http.StatusConflict, gin.H{"error": "the corrected punches are out of order"})
+			return
+		}
+		if err == domain.ErrOutsideShiftWindow {
+			c.JSON(http.StatusConflict, gin.H{"error": "the corrected punches fall outside the shift of the correction date"})
+			return
+		}
+		if err == domain.ErrTimesheetLocked {
+			c.JSON(http.StatusConflict, gin.H{"error": "the timesheet for the correction date is locked"})
+			return
+		}
+		if err == domain.ErrInvalidCorrection {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "correction cannot be applied to this attendance"})
+			return
+		}
+		if err == domain.ErrPunchNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "punch not found"})
+			return
+		}
+		if err == domain.ErrNoScheduleFound {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "no schedule found for the correction date"})
+			return
+		}
+		if errors.Is(err, domain.ErrNonWorkingDay) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "the correction date is a non-working day"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// csvTable escribe una exportación como csv. Empieza con el BOM de UTF-8 para que Excel muestre bien los acentos.
+type csvTable struct {
+	w   *csv.Writer
+	out io.Writer
+}
+
+func newCSVTable(out io.Writer) *csvTable {
+	return &csvTable{w: csv.NewWriter(out), out: out}
+}
+
+func (t *csvTable) WriteHeader(labels []string) error {
+	if _, err := io.WriteString(t.out, "\ufeff"); err != nil {
+		return err
+	}
+	return t.w.Write(labels)
+}
+
+func (t *csvTable) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			record[i] = csvSafe(v)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return t.w.Write(record)
+}
+
+func (t *csvTable) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// csvSafe antepone un apóstrofo a los textos que una planilla interpretaría como fórmula (=, +, -, @),
+// para que un nombre ingresado por un usuario no se ejecute al abrir el archivo
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// exportLang elige el idioma de los encabezados: el pedido, si no el primero soportado de Accept-Language, si no español
+func exportLang(c *gin.Context, requested string) string {
+	if requested != "" {
+		return requested
+	}
+	for _, tag := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		lang, _, _ := strings.Cut(strings.TrimSpace(tag), ";")
+		lang, _, _ = strings.Cut(strings.ToLower(lang), "-")
+		if lang == "es" || lang == "en" {
+			return lang
+		}
+	}
+	return "es"
+}
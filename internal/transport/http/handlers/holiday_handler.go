@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Tamaño máximo de un archivo .ics importado
+const maxCalendarFileBytes = 1 << 20
+
+type HolidayHandler struct {
+	svc *service.HolidayService
+}
+
+func NewHolidayHandler(svc *service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{svc: svc}
+}
+
+// Create godoc
+// @Summary Add a holiday
+// @Description Adds a holiday or closure day to the agency calendar (Admin only). No work is expected on these dates: schedules do not apply and no absences are recorded. Recurring holidays repeat every year on the same day and month.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateHolidayRequest true "Holiday details"
+// @Success 201 {object} dto.HolidayResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /holidays [post]
+func (h *HolidayHandler) Create(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var req dto.CreateHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.CreateHoliday(c.Request.Context(), agencyID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidHoliday:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+		case domain.ErrHolidayExists:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// Import godoc
+// @Summary Import holidays from iCalendar
+// @Description Adds the events of an .ics file to the agency calendar (Admin only). Events with an endless yearly RRULE become recurring holidays; when the rule has COUNT or UNTIL, each occurrence is added as its own holiday. Multi-day events are expanded day by day, and UTC times are converted to the agency's time zone before taking the day. Dates already in the calendar are skipped.
+// @Tags holidays
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "iCalendar (.ics) file"
+// @Param type formData string false "holiday or closure (default holiday)"
+// @Success 200 {object} dto.HolidayImportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /holidays/import [post]
+func (h *HolidayHandler) Import(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	holidayType := domain.HolidayType(c.PostForm("type"))
+	if holidayType != "" && holidayType != domain.HolidayPublic && holidayType != domain.HolidayClosure {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be holiday or closure"})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if header.Size > maxCalendarFileBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is too large"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not read file"})
+		return
+	}
+	defer file.Close()
+
+	res, err := h.svc.ImportICS(c.Request.Context(), agencyID, holidayType, file)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidCalendar:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is not a valid iCalendar (.ics) file"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// List godoc
+// @Summary List holidays
+// @Description Returns the agency calendar. With year, returns that year's holidays plus recurring ones from earlier years.
+// @Tags holidays
+// @Produce json
+// @Param year query int false "Year filter"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.HolidayResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /holidays/list [get]
+func (h *HolidayHandler) List(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.HolidayListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.GetAgencyHolidays(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Delete godoc
+// @Summary Delete a holiday
+// @Description Removes a date from the agency calendar (Admin only). Absences are not recreated for past dates until the absence backfill runs.
+// @Tags holidays
+// @Produce json
+// @Param id path string true "Holiday ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /holidays/{id} [delete]
+func (h *HolidayHandler) Delete(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	holidayID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid holiday ID"})
+		return
+	}
+
+	if err := h.svc.DeleteHoliday(c.Request.Context(), agencyID, holidayID); err != nil {
+		switch err {
+		case domain.ErrHolidayNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "holiday deleted"})
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"net/http"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LeaveBalanceHandler struct {
+	svc *service.LeaveBalanceService
+}
+
+func NewLeaveBalanceHandler(svc *service.LeaveBalanceService) *LeaveBalanceHandler {
+	return &LeaveBalanceHandler{svc: svc}
+}
+
+// List godoc
+// @Summary Get leave balances
+// @Description Returns, for each leave type that tracks a balance, the user's balance today and the balance projected to date, including accruals, anniversary grants, carry-over caps and expiries not generated yet and approved leave already deducted. pending_days are requested but not approved yet. Employees can only see their own balances.
+// @Tags leave
+// @Produce json
+// @Param user_id query string false "User ID (Admins only, defaults to the current user)"
+// @Param date query string false "Projection date (YYYY-MM-DD), defaults to today. At most 5 years ahead"
+// @Success 200 {array} dto.LeaveBalanceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/balances [get]
+func (h *LeaveBalanceHandler) List(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.LeaveBalanceParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := balanceUserID(c, params.UserID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetBalances(c.Request.Context(), agencyID, userID, &params)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidDateRange:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD and at most 5 years from today"})
+		case domain.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Ledger godoc
+// @Summary List leave balance movements
+// @Description Returns every recorded grant, accrual, deduction, adjustment, carry-over cap and expiry of the user's leave balances, latest first. Employees can only see their own movements.
+// @Tags leave
+// @Produce json
+// @Param user_id query string false "User ID (Admins only, defaults to the current user)"
+// @Param leave_type_id query string false "Leave type filter"
+// @Param kind query string false "Kind (accrual, grant, deduction, adjustment, carry_over_cap, expiry)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.LeaveBalanceEntryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/balances/ledger [get]
+func (h *LeaveBalanceHandler) Ledger(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.LeaveBalanceLedgerParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := balanceUserID(c, params.UserID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.Ledger(c.Request.Context(), agencyID, userID, &params)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Adjust godoc
+// @Summary Adjust a leave balance
+// @Description Records a manual movement on a user's balance, e.g. the opening balance when tracking starts or a correction (Admin only). days is positive to add and negative to subtract; a note is required.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateLeaveAdjustmentRequest true "Adjustment details"
+// @Success 201 {object} dto.LeaveBalanceEntryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/balances/adjustments [post]
+func (h *LeaveBalanceHandler) Adjust(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	var req dto.CreateLeaveAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.Adjust(c.Request.Context(), agencyID, adminID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidLeaveAdjustment:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must not round to zero, note is required and date must be YYYY-MM-DD"})
+		case domain.ErrLeaveBalanceNotTracked:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case domain.ErrLeaveTypeNotFound, domain.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// balanceUserID resuelve de quién es el saldo o la planilla consultada: los empleados solo ven los propios
+func balanceUserID(c *gin.Context, requested string) (uuid.UUID, bool) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	role := c.MustGet("role").(domain.Role)
+	if role == domain.RoleEmployee || requested == "" {
+		return userID, true
+	}
+
+	id, err := uuid.Parse(requested)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LeaveHandler struct {
+	svc *service.LeaveService
+}
+
+func NewLeaveHandler(svc *service.LeaveService) *LeaveHandler {
+	return &LeaveHandler{svc: svc}
+}
+
+// CreateType godoc
+// @Summary Create a leave type
+// @Description Adds a kind of leave to the agency, e.g. vacation, sick leave or personal day (Admin only). With uses_time_bank the type is comp time and approved requests are paid from the time bank. With track_balance each employee gets a balance that grows with the accrual policy (monthly, or a yearly grant on the hire anniversary); on every anniversary the balance above carry_over_cap_days is dropped, and the carried days still unused carry_over_expiry_months into the new year expire. Tracking starts on the current date.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateLeaveTypeRequest true "Leave type details"
+// @Success 201 {object} dto.LeaveTypeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/types [post]
+func (h *LeaveHandler) CreateType(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var req dto.CreateLeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.CreateLeaveType(c.Request.Context(), agencyID, &req)
+	if err != nil {
+		handleLeaveTypeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// ListTypes godoc
+// @Summary List leave types
+// @Description Returns the leave types of the agency.
+// @Tags leave
+// @Produce json
+// @Param active query bool false "Active filter"
+// @Success 200 {array} dto.LeaveTypeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/types/list [get]
+func (h *LeaveHandler) ListTypes(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.LeaveTypeListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.GetLeaveTypes(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// UpdateType godoc
+// @Summary Update a leave type
+// @Description Renames a leave type, changes whether it is paid, its balance policy, or deactivates it (Admin only). Inactive types accept no new requests; existing ones are kept. Policy changes apply to movements generated from then on; turning track_balance on restarts tracking on the current date.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Param id path string true "Leave type ID"
+// @Param request body dto.UpdateLeaveTypeRequest true "Updated details"
+// @Success 200 {object} dto.LeaveTypeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/types/{id} [put]
+func (h *LeaveHandler) UpdateType(c *gin.Context) {
+	leaveTypeID, err := uuid.Parse(c.Param("id"))
+	if err != nil || leaveTypeID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid leave type ID"})
+		return
+	}
+
+	var req dto.UpdateLeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	res, err := h.svc.UpdateLeaveType(c.Request.Context(), agencyID, leaveTypeID, &req)
+	if err != nil {
+		handleLeaveTypeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func handleLeaveTypeError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrLeaveTypeNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case domain.ErrLeaveTypeExists:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
+
+// Create godoc
+// @Summary Request leave
+// @Description Submits a leave request for the current user covering full days (start_date to end_date) or half of a single day (half_day). It cannot overlap another pending or approved request. Only days with a scheduled shift count towards days; for leave types that track a balance they cannot exceed the balance projected to start_date minus other pending requests (409). Comp time types (uses_time_bank) likewise check the scheduled minutes against the time bank. Admins of the agency are notified by email.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateLeaveRequest true "Leave details"
+// @Success 201 {object} dto.LeaveRequestResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/requests [post]
+func (h *LeaveHandler) Create(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req dto.CreateLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.Submit(c.Request.Context(), agencyID, userID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidLeaveRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dates: use YYYY-MM-DD, half_day only for a single day"})
+		case domain.ErrInvalidDateRange:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must be on or after start_date and within a year"})
+		case domain.ErrLeaveTypeNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case domain.ErrLeaveTypeInactive, domain.ErrTimeBankDisabled:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case domain.ErrLeaveOverlap, domain.ErrInsufficientLeaveBalance, domain.ErrInsufficientTimeBank:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// List godoc
+// @Summary List leave requests
+// @Description Returns the leave requests of the agency, latest start date first. Employees can only see their own requests.
+// @Tags leave
+// @Produce json
+// @Param user_id query string false "User ID filter (Admins only)"
+// @Param leave_type_id query string false "Leave type filter"
+// @Param status query string false "Status (pending, approved, rejected, cancelled)"
+// @Param start_date query string false "Requests overlapping from this date (YYYY-MM-DD)"
+// @Param end_date query string false "Requests overlapping until this date (YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.LeaveRequestResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/requests/list [get]
+func (h *LeaveHandler) List(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.LeaveRequestListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Security: Employees can only see their own requests
+	role := c.MustGet("role").(domain.Role)
+	if role == domain.RoleEmployee {
+		params.UserID = c.MustGet("user_id").(uuid.UUID).String()
+	} else if params.UserID != "" {
+		if _, err := uuid.Parse(params.UserID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+			return
+		}
+	}
+
+	res, err := h.svc.List(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Approve godoc
+// @Summary Approve a leave request
+// @Description Approves a pending leave request and notifies the employee. From then on no work is expected on the covered days (a half-day leave shortens the shift to the half still worked), and absences already recorded by the job on those days are voided and logged in the attendance history (409 if one of them falls within a locked timesheet). For leave types that track a balance the days are deducted on start_date; approval fails with 409 if the balance is no longer enough. Comp time types debit their scheduled minutes from the time bank on the approval date.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Param id path string true "Leave request ID"
+// @Param request body dto.ReviewLeaveRequest false "Review note"
+// @Success 200 {object} dto.LeaveRequestResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/requests/{id}/approve [post]
+func (h *LeaveHandler) Approve(c *gin.Context) {
+	h.review(c, h.svc.Approve)
+}
+
+// Reject godoc
+// @Summary Reject a leave request
+// @Description Rejects a pending leave request and notifies the employee.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Param id path string true "Leave request ID"
+// @Param request body dto.ReviewLeaveRequest false "Review note"
+// @Success 200 {object} dto.LeaveRequestResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/requests/{id}/reject [post]
+func (h *LeaveHandler) Reject(c *gin.Context) {
+	h.review(c, h.svc.Reject)
+}
+
+// Cancel godoc
+// @Summary Cancel a leave request
+// @Description Withdraws one of the current user's own leave requests while it is still pending.
+// @Tags leave
+// @Produce json
+// @Param id path string true "Leave request ID"
+// @Success 200 {object} dto.LeaveRequestResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /leave/requests/{id}/cancel [post]
+func (h *LeaveHandler) Cancel(c *gin.Context) {
+	leaveID, err := uuid.Parse(c.Param("id"))
+	if err != nil || leaveID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid leave request ID"})
+		return
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	res, err := h.svc.Cancel(c.Request.Context(), agencyID, userID, leaveID)
+	if err != nil {
+		handleLeaveReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+type leaveReviewFunc func(ctx context.Context, agencyID uuid.UUID, reviewerID uuid.UUID, leaveID uuid.UUID, req *dto.ReviewLeaveRequest) (*dto.LeaveRequestResponse, error)
+
+func (h *LeaveHandler) review(c *gin.Context, review leaveReviewFunc) {
+	leaveID, err := uuid.Parse(c.Param("id"))
+	if err != nil || leaveID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid leave request ID"})
+		return
+	}
+
+	// La nota es opcional: un body vacío es válido
+	var req dto.ReviewLeaveRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	reviewerID := c.MustGet("user_id").(uuid.UUID)
+
+	res, err := review(c.Request.Context(), agencyID, reviewerID, leaveID, &req)
+	if err != nil {
+		handleLeaveReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func handleLeaveReviewError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrLeaveRequestNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case domain.ErrLeaveNotPending, domain.ErrInsufficientLeaveBalance, domain.ErrInsufficientTimeBank, domain.ErrTimesheetLocked:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case domain.ErrTimeBankDisabled:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NFCTagHandler struct {
+	svc *service.NFCTagService
+}
+
+func NewNFCTagHandler(svc *service.NFCTagService) *NFCTagHandler {
+	return &NFCTagHandler{svc: svc}
+}
+
+// Create godoc
+// @Summary Register an NFC tag
+// @Description Registers an NFC tag of the agency (Admin only). The UID is stored in uppercase hexadecimal without separators. The optional site_id is the site where the tag is installed (400 if it is not a site of the agency); punches read from the tag are recorded at that site.
+// @Tags nfc-tags
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateNFCTagRequest true "Tag details"
+// @Success 201 {object} dto.NFCTagResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /nfc-tags [post]
+func (h *NFCTagHandler) Create(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var req dto.CreateNFCTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.CreateTag(c.Request.Context(), agencyID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrNFCTagRequired:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag uid"})
+		case domain.ErrSiteNotFound:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "site not found"})
+		case domain.ErrNFCTagExists:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// List godoc
+// @Summary List NFC tags
+// @Description Returns the NFC tags registered in the agency (Admin only).
+// @Tags nfc-tags
+// @Produce json
+// @Param active query bool false "Filter by active state"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.NFCTagResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /nfc-tags/list [get]
+func (h *NFCTagHandler) List(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.NFCTagListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.GetAgencyTags(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list nfc tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Update godoc
+// @Summary Update an NFC tag
+// @Description Changes the label, site or active state of a tag (Admin only). Send the nil UUID as site_id to detach the tag from its site. Inactive tags are rejected when marking attendance.
+// @Tags nfc-tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param request body dto.UpdateNFCTagRequest true "Updated details"
+// @Success 200 {object} dto.NFCTagResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /nfc-tags/{id} [put]
+func (h *NFCTagHandler) Update(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("id"))
+	if err != nil || tagID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag ID"})
+		return
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var req dto.UpdateNFCTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.UpdateTag(c.Request.Context(), agencyID, tagID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrNFCTagNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case domain.ErrSiteNotFound:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "site not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Usage godoc
+// @Summary List NFC tag usage
+// @Description Returns the punches registered with NFC tags, most recent first. Punches of voided attendance records are excluded (Admin only).
+// @Tags nfc-tags
+// @Produce json
+// @Param tag_id query string false "Tag ID"
+// @Param user_id query string false "User ID"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.NFCTagUsageResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /nfc-tags/usage [get]
+func (h *NFCTagHandler) Usage(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.NFCTagUsageParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if params.TagID != "" {
+		if _, err := uuid.Parse(params.TagID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag_id format"})
+			return
+		}
+	}
+	if params.UserID != "" {
+		if _, err := uuid.Parse(params.UserID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+			return
+		}
+	}
+
+	res, err := h.svc.GetTagUsage(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
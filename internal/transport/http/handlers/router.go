@@ -17,11 +17,21 @@ func NewRouter(
 	userSvc *service.UserService,
 	scheduleSvc *service.ScheduleService,
 	attendanceSvc *service.AttendanceService,
+	correctionSvc *service.CorrectionService,
+	nfcTagSvc *service.NFCTagService,
+	siteSvc *service.SiteService,
+	holidaySvc *service.HolidayService,
+	rotationSvc *service.ShiftRotationService,
+	leaveSvc *service.LeaveService,
+	leaveBalanceSvc *service.LeaveBalanceService,
+	timeBankSvc *service.TimeBankService,
+	timesheetSvc *service.TimesheetService,
 	jwtSvc *security.JWTService,
 	rps rate.Limit,
 	burst int,
 ) *gin.Engine {
-	r := gin.Default()
+	r := gin.New()
+	r.Use(gin.Logger(), middleware.Recovery())
 	r.Use(middleware.RateLimitByIP(rps, burst))
 
 	// Handlers
@@ -29,6 +39,15 @@ func NewRouter(
 	userHandler := NewUserHandler(userSvc)
 	scheduleHandler := NewScheduleHandler(scheduleSvc)
 	attendanceHandler := NewAttendanceHandler(attendanceSvc)
+	correctionHandler := NewCorrectionHandler(correctionSvc)
+	nfcTagHandler := NewNFCTagHandler(nfcTagSvc)
+	siteHandler := NewSiteHandler(siteSvc)
+	holidayHandler := NewHolidayHandler(holidaySvc)
+	rotationHandler := NewShiftRotationHandler(rotationSvc)
+	leaveHandler := NewLeaveHandler(leaveSvc)
+	leaveBalanceHandler := NewLeaveBalanceHandler(leaveBalanceSvc)
+	timeBankHandler := NewTimeBankHandler(timeBankSvc)
+	timesheetHandler := NewTimesheetHandler(timesheetSvc)
 
 	// Middlewares
 	authMiddleware := middleware.Auth(jwtSvc)
@@ -107,15 +126,111 @@ func NewRouter(
 				adminOnly.POST("", scheduleHandler.Create)
 				adminOnly.PUT("/:id", scheduleHandler.Update)
 				adminOnly.DELETE("/:id", scheduleHandler.Delete)
+				adminOnly.GET("/:id/versions", scheduleHandler.Versions)
+				adminOnly.GET("/conflicts", scheduleHandler.Conflicts)
+				adminOnly.POST("/overrides", scheduleHandler.CreateOverride)
+				adminOnly.GET("/overrides/list", scheduleHandler.ListOverrides)
+				adminOnly.DELETE("/overrides/:id", scheduleHandler.DeleteOverride)
+				adminOnly.POST("/rotations", rotationHandler.Create)
+				adminOnly.GET("/rotations/list", rotationHandler.List)
+				adminOnly.GET("/rotations/:id", rotationHandler.GetByID)
+				adminOnly.DELETE("/rotations/:id", rotationHandler.Delete)
+				adminOnly.POST("/rotations/:id/assignments", rotationHandler.Assign)
+				adminOnly.GET("/rotations/:id/assignments", rotationHandler.ListAssignments)
+				adminOnly.DELETE("/rotations/assignments/:id", rotationHandler.EndAssignment)
 			}
 		}
 
+		// Sites routes
+		sites := v1.Group("sites")
+		sites.Use(authMiddleware)
+		{
+			sites.GET("/list", middleware.RequireRole(domain.RoleAdmin), siteHandler.List)
+			sites.POST("", middleware.RequireRole(domain.RoleAdmin), siteHandler.Create)
+			sites.PUT("/:id", middleware.RequireRole(domain.RoleAdmin), siteHandler.Update)
+		}
+
+		// Holiday calendar routes
+		holidays := v1.Group("holidays")
+		holidays.Use(authMiddleware)
+		{
+			holidays.GET("/list", holidayHandler.List)
+			holidays.POST("", middleware.RequireRole(domain.RoleAdmin), holidayHandler.Create)
+			holidays.POST("/import", middleware.RequireRole(domain.RoleAdmin), holidayHandler.Import)
+			holidays.DELETE("/:id", middleware.RequireRole(domain.RoleAdmin), holidayHandler.Delete)
+		}
+
+		// Leave routes
+		leave := v1.Group("leave")
+		leave.Use(authMiddleware)
+		{
+			leave.GET("/types/list", leaveHandler.ListTypes)
+			leave.POST("/types", middleware.RequireRole(domain.RoleAdmin), leaveHandler.CreateType)
+			leave.PUT("/types/:id", middleware.RequireRole(domain.RoleAdmin), leaveHandler.UpdateType)
+
+			leave.POST("/requests", leaveHandler.Create)
+			leave.GET("/requests/list", leaveHandler.List)
+			leave.POST("/requests/:id/cancel", leaveHandler.Cancel)
+			leave.POST("/requests/:id/approve", middleware.RequireRole(domain.RoleAdmin), leaveHandler.Approve)
+			leave.POST("/requests/:id/reject", middleware.RequireRole(domain.RoleAdmin), leaveHandler.Reject)
+
+			leave.GET("/balances", leaveBalanceHandler.List)
+			leave.GET("/balances/ledger", leaveBalanceHandler.Ledger)
+			leave.POST("/balances/adjustments", middleware.RequireRole(domain.RoleAdmin), leaveBalanceHandler.Adjust)
+		}
+
+		// Time bank routes
+		timeBank := v1.Group("time-bank")
+		timeBank.Use(authMiddleware)
+		{
+			timeBank.GET("/balance", timeBankHandler.Balance)
+			timeBank.GET("/ledger", timeBankHandler.Ledger)
+			timeBank.POST("/adjustments", middleware.RequireRole(domain.RoleAdmin), timeBankHandler.Adjust)
+		}
+
+		// Timesheet routes
+		timesheets := v1.Group("timesheets")
+		timesheets.Use(authMiddleware)
+		{
+			timesheets.GET("", timesheetHandler.Get)
+			timesheets.POST("/acknowledge", timesheetHandler.Acknowledge)
+			timesheets.GET("/list", middleware.RequireRole(domain.RoleAdmin), timesheetHandler.List)
+			timesheets.POST("/lock", middleware.RequireRole(domain.RoleAdmin), timesheetHandler.Lock)
+			timesheets.POST("/unlock", middleware.RequireRole(domain.RoleAdmin), timesheetHandler.Unlock)
+		}
+
+		// NFC tags routes (Admin only)
+		nfcTags := v1.Group("nfc-tags")
+		nfcTags.Use(authMiddleware, middleware.RequireRole(domain.RoleAdmin))
+		{
+			nfcTags.POST("", nfcTagHandler.Create)
+			nfcTags.GET("/list", nfcTagHandler.List)
+			nfcTags.GET("/usage", nfcTagHandler.Usage)
+			nfcTags.PUT("/:id", nfcTagHandler.Update)
+		}
+
 		// Attendance routes
 		attendance := v1.Group("attendance")
 		attendance.Use(authMiddleware)
 		{
 			attendance.POST("/mark", attendanceHandler.Mark)
+			attendance.GET("/qr", middleware.RequireRole(domain.RoleAdmin), attendanceHandler.QRToken)
 			attendance.GET("/list", attendanceHandler.List)
+			attendance.GET("/export", attendanceHandler.Export)
+			attendance.GET("/suspicious", middleware.RequireRole(domain.RoleAdmin), attendanceHandler.Suspicious)
+			attendance.GET("/:id/history", attendanceHandler.History)
+
+			// Admin only: records for any date, edits and voids
+			attendance.POST("", middleware.RequireRole(domain.RoleAdmin), attendanceHandler.Create)
+			attendance.PUT("/:id", middleware.RequireRole(domain.RoleAdmin), attendanceHandler.Update)
+			attendance.POST("/:id/void", middleware.RequireRole(domain.RoleAdmin), attendanceHandler.Void)
+			attendance.POST("/:id/resolve-flag", middleware.RequireRole(domain.RoleAdmin), attendanceHandler.ResolveFlag)
+
+			// Correction requests
+			attendance.POST("/corrections", correctionHandler.Create)
+			attendance.GET("/corrections", correctionHandler.List)
+			attendance.POST("/corrections/:id/approve", middleware.RequireRole(domain.RoleAdmin), correctionHandler.Approve)
+			attendance.POST("/corrections/:id/reject", middleware.RequireRole(domain.RoleAdmin), correctionHandler.Reject)
 		}
 	}
 	return r
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"quickattendance-go/internal/domain"
 	"quickattendance-go/internal/dto"
@@ -21,13 +22,14 @@ func NewScheduleHandler(svc *service.ScheduleService) *ScheduleHandler {
 
 // Create godoc
 // @Summary Create a new schedule
-// @Description Creates a new work schedule for the agency (Admin only).
+// @Description Creates a new work schedule for the agency (Admin only). With effective_from the schedule only applies from that date. Assigned users cannot have another schedule on the same weekdays (409 with the conflicting schedule). With type "flexible", entry and exit define the band where work counts, core_start/core_end the time the employee must be present (lateness and early leave are checked against it) and required_minutes the daily total; checking out with fewer minutes is reported as short_hours.
 // @Tags schedules
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateScheduleRequest true "Schedule details"
 // @Success 201 {object} domain.Schedule
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
@@ -42,9 +44,20 @@ func (h *ScheduleHandler) Create(c *gin.Context) {
 
 	res, err := h.svc.CreateSchedule(c.Request.Context(), agencyID, &req)
 	if err != nil {
+		if writeScheduleConflict(c, err) {
+			return
+		}
 		switch err {
 		case domain.ErrDefaultScheduleAlreadyExists, domain.ErrScheduleNameAlreadyExists:
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case domain.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case domain.ErrInvalidEffectiveDate:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid effective_from, use YYYY-MM-DD"})
+		case domain.ErrInvalidWeekday:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case domain.ErrInvalidFlexibleSchedule:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "core time must fall within the entry and exit band and required_minutes must be positive and fit in it"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		}
@@ -86,7 +99,7 @@ func (h *ScheduleHandler) GetByID(c *gin.Context) {
 
 // List godoc
 // @Summary List all schedules
-// @Description Returns a list of schedules for the agency.
+// @Description Returns the schedules of the agency: the versions in effect today and the ones scheduled to start later. Ended versions are listed in /schedules/{id}/versions.
 // @Tags schedules
 // @Produce json
 // @Success 200 {array} domain.Schedule
@@ -114,12 +127,12 @@ func (h *ScheduleHandler) List(c *gin.Context) {
 
 // GetApplicable godoc
 // @Summary Get applicable schedule for a date
-// @Description Returns the schedule that applies to a user on a specific date.
+// @Description Returns the schedule that applies to a user on a specific date. A date override replaces entry, exit and grace period and is reported in "override"; agency-wide overrides only apply on days the user already works. On holidays and closure days of the agency calendar no work is expected: the response is 404 with the holiday. The same applies to days covered by an approved full-day leave (404 with the leave), while an approved half-day leave shortens the shift to the half still worked and is reported in "leave".
 // @Tags schedules
 // @Produce json
 // @Param user_id query string false "User ID (defaults to current user)"
 // @Param date query string true "Date (YYYY-MM-DD)"
-// @Success 200 {object} domain.Schedule
+// @Success 200 {object} dto.ScheduleResponse
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Security BearerAuth
@@ -151,6 +164,24 @@ func (h *ScheduleHandler) GetApplicable(c *gin.Context) {
 
 	res, err := h.svc.GetApplicableSchedule(c.Request.Context(), agencyID, userID, parsedDate)
 	if err != nil {
+		var onLeave *domain.OnLeaveError
+		if errors.As(err, &onLeave) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "user is on leave on this date",
+				"leave": dto.ToLeaveRequestResponse(onLeave.Leave),
+			})
+			return
+		}
+		var nonWorking *domain.NonWorkingDayError
+		if errors.As(err, &nonWorking) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":        "no work expected on this date",
+				"holiday_id":   nonWorking.Holiday.ID,
+				"holiday":      nonWorking.Holiday.Name,
+				"holiday_type": nonWorking.Holiday.Type,
+			})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "no applicable schedule found"})
 		return
 	}
@@ -160,7 +191,7 @@ func (h *ScheduleHandler) GetApplicable(c *gin.Context) {
 
 // Update godoc
 // @Summary Update a schedule
-// @Description Updates an existing schedule (Admin only).
+// @Description Updates an existing schedule (Admin only). Name and is_default apply to every version. Changes to days, times, flexible settings or assigned users create a new version starting on effective_from (default today, never in the past) and close the previous one the day before, so past dates keep the rules they had. Only the latest version can be edited, and assigned users cannot end up with another schedule on the same weekdays (409).
 // @Tags schedules
 // @Accept json
 // @Produce json
@@ -190,11 +221,78 @@ func (h *ScheduleHandler) Update(c *gin.Context) {
 
 	res, err := h.svc.UpdateSchedule(c.Request.Context(), &req, scheduleID, agencyID)
 	if err != nil {
+		if writeScheduleConflict(c, err) {
+			return
+		}
 		switch err {
 		case domain.ErrScheduleNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case domain.ErrScheduleNameAlreadyExists:
+		case domain.ErrScheduleNameAlreadyExists, domain.ErrScheduleVersionNotCurrent:
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case domain.ErrInvalidEffectiveDate:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "effective_from must be a YYYY-MM-DD date not before today nor before the current version"})
+		case domain.ErrInvalidWeekday:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case domain.ErrInvalidFlexibleSchedule:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "core time must fall within the entry and exit band and required_minutes must be positive and fit in it"})
+		case domain.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Conflicts godoc
+// @Summary List overlapping schedule assignments
+// @Description Returns users assigned to two schedules that apply on the same weekdays from today on (Admin only). New assignments are validated, so these come from earlier data; meanwhile the oldest schedule is applied.
+// @Tags schedules
+// @Produce json
+// @Success 200 {array} dto.ScheduleConflictResponse
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/conflicts [get]
+func (h *ScheduleHandler) Conflicts(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	res, err := h.svc.ListConflicts(c.Request.Context(), agencyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Versions godoc
+// @Summary List the versions of a schedule
+// @Description Returns every version of the schedule series the given version belongs to, oldest first, with their effective dates (Admin only).
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Schedule ID (any version)"
+// @Success 200 {array} dto.ScheduleResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/{id}/versions [get]
+func (h *ScheduleHandler) Versions(c *gin.Context) {
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil || scheduleID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	res, err := h.svc.GetScheduleVersions(c.Request.Context(), scheduleID, agencyID)
+	if err != nil {
+		switch err {
+		case domain.ErrScheduleNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		}
@@ -206,7 +304,7 @@ func (h *ScheduleHandler) Update(c *gin.Context) {
 
 // Delete godoc
 // @Summary Delete a schedule
-// @Description Deletes a schedule from the agency (Admin only).
+// @Description Retires a schedule as of today (Admin only): versions that have not started yet are removed and the rest end yesterday, so past dates keep their schedule.
 // @Tags schedules
 // @Produce json
 // @Param id path string true "Schedule ID"
@@ -241,3 +339,134 @@ func (h *ScheduleHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "schedule deleted"})
 }
+
+// CreateOverride godoc
+// @Summary Create a schedule override
+// @Description Replaces entry time, exit time and grace period for a date or date range (Admin only), e.g. closing early on a Friday. Without user_ids it applies to the whole agency, but only on days each user already works (never on holidays or days off). With user_ids it also adds the working day for those users, even on holidays or days off. User overrides win over agency ones.
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateScheduleOverrideRequest true "Override details"
+// @Success 201 {object} dto.ScheduleOverrideResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/overrides [post]
+func (h *ScheduleHandler) CreateOverride(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	var req dto.CreateScheduleOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.CreateOverride(c.Request.Context(), agencyID, adminID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidScheduleOverride:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+		case domain.ErrInvalidDateRange:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must be on or after start_date and within a year"})
+		case domain.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// ListOverrides godoc
+// @Summary List schedule overrides
+// @Description Returns the schedule overrides of the agency (Admin only). With user_id, returns that user's overrides plus agency-wide ones.
+// @Tags schedules
+// @Produce json
+// @Param user_id query string false "User ID filter"
+// @Param start_date query string false "Overrides ending on or after this date (YYYY-MM-DD)"
+// @Param end_date query string false "Overrides starting on or before this date (YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.ScheduleOverrideResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/overrides/list [get]
+func (h *ScheduleHandler) ListOverrides(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.ScheduleOverrideListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if params.UserID != "" {
+		if _, err := uuid.Parse(params.UserID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+			return
+		}
+	}
+
+	res, err := h.svc.ListOverrides(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// DeleteOverride godoc
+// @Summary Delete a schedule override
+// @Description Removes a schedule override (Admin only). Attendance already recorded keeps the times it was evaluated with.
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Override ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/overrides/{id} [delete]
+func (h *ScheduleHandler) DeleteOverride(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	overrideID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid override ID"})
+		return
+	}
+
+	if err := h.svc.DeleteOverride(c.Request.Context(), agencyID, overrideID); err != nil {
+		switch err {
+		case domain.ErrScheduleOverrideNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "schedule override deleted"})
+}
+
+// writeScheduleConflict responde 409 con el horario que se cruza si err es una asignación superpuesta
+func writeScheduleConflict(c *gin.Context, err error) bool {
+	var conflict *domain.ScheduleConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+
+	c.JSON(http.StatusConflict, gin.H{
+		"error":         domain.ErrScheduleAssignmentConflict.Error(),
+		"user_id":       conflict.UserID,
+		"schedule_id":   conflict.Schedule.ID,
+		"schedule_name": conflict.Schedule.Name,
+		"days_of_week":  conflict.Weekdays,
+	})
+	return true
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"net/http"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ShiftRotationHandler struct {
+	svc *service.ShiftRotationService
+}
+
+func NewShiftRotationHandler(svc *service.ShiftRotationService) *ShiftRotationHandler {
+	return &ShiftRotationHandler{svc: svc}
+}
+
+// Create godoc
+// @Summary Create a shift rotation
+// @Description Creates a rotating shift pattern (Admin only): an ordered list of shifts, each lasting some consecutive days, repeated in a cycle from anchor_date (e.g. 2 days, 2 nights, 4 off).
+// @Tags rotations
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateShiftRotationRequest true "Rotation details"
+// @Success 201 {object} dto.ShiftRotationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/rotations [post]
+func (h *ShiftRotationHandler) Create(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var req dto.CreateShiftRotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.CreateRotation(c.Request.Context(), agencyID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidShiftRotation:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "anchor_date must be YYYY-MM-DD and the cycle needs a working shift and at most 366 days"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// List godoc
+// @Summary List shift rotations
+// @Description Returns the shift rotations of the agency (Admin only).
+// @Tags rotations
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.ShiftRotationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/rotations/list [get]
+func (h *ShiftRotationHandler) List(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.ShiftRotationListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.ListRotations(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// GetByID godoc
+// @Summary Get a shift rotation
+// @Description Returns a shift rotation with its shifts (Admin only).
+// @Tags rotations
+// @Produce json
+// @Param id path string true "Rotation ID"
+// @Success 200 {object} dto.ShiftRotationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/rotations/{id} [get]
+func (h *ShiftRotationHandler) GetByID(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	rotationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rotation ID"})
+		return
+	}
+
+	res, err := h.svc.GetRotation(c.Request.Context(), agencyID, rotationID)
+	if err != nil {
+		switch err {
+		case domain.ErrShiftRotationNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Delete godoc
+// @Summary Delete a shift rotation
+// @Description Deletes a shift rotation that was never assigned (Admin only). Rotations with assignments are kept so past dates still resolve.
+// @Tags rotations
+// @Produce json
+// @Param id path string true "Rotation ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/rotations/{id} [delete]
+func (h *ShiftRotationHandler) Delete(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	rotationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rotation ID"})
+		return
+	}
+
+	if err := h.svc.DeleteRotation(c.Request.Context(), agencyID, rotationID); err != nil {
+		switch err {
+		case domain.ErrShiftRotationNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case domain.ErrShiftRotationInUse:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "shift rotation deleted"})
+}
+
+// Assign godoc
+// @Summary Assign a user to a shift rotation
+// @Description Assigns a user to the rotation from start_date (default today, never in the past) with an offset in days within the cycle (Admin only). A previous assignment of the user ends the day before. While assigned, the rotation replaces the user's weekly schedule.
+// @Tags rotations
+// @Accept json
+// @Produce json
+// @Param id path string true "Rotation ID"
+// @Param request body dto.AssignRotationRequest true "Assignment details"
+// @Success 201 {object} dto.RotationAssignmentResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/rotations/{id}/assignments [post]
+func (h *ShiftRotationHandler) Assign(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	rotationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rotation ID"})
+		return
+	}
+
+	var req dto.AssignRotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.AssignUser(c.Request.Context(), agencyID, rotationID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidEffectiveDate:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be a YYYY-MM-DD date not before today"})
+		case domain.ErrShiftRotationNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case domain.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// ListAssignments godoc
+// @Summary List the assignments of a shift rotation
+// @Description Returns current, upcoming and ended assignments of the rotation (Admin only).
+// @Tags rotations
+// @Produce json
+// @Param id path string true "Rotation ID"
+// @Success 200 {array} dto.RotationAssignmentResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/rotations/{id}/assignments [get]
+func (h *ShiftRotationHandler) ListAssignments(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	rotationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rotation ID"})
+		return
+	}
+
+	res, err := h.svc.ListAssignments(c.Request.Context(), agencyID, rotationID)
+	if err != nil {
+		switch err {
+		case domain.ErrShiftRotationNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// EndAssignment godoc
+// @Summary End a rotation assignment
+// @Description Removes the user from the rotation as of today (Admin only); the assignment is kept for past dates. An assignment that has not started yet is deleted.
+// @Tags rotations
+// @Produce json
+// @Param id path string true "Assignment ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/rotations/assignments/{id} [delete]
+func (h *ShiftRotationHandler) EndAssignment(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	assignmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid assignment ID"})
+		return
+	}
+
+	if err := h.svc.EndAssignment(c.Request.Context(), agencyID, assignmentID); err != nil {
+		switch err {
+		case domain.ErrRotationAssignmentNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "rotation assignment ended"})
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SiteHandler struct {
+	svc *service.SiteService
+}
+
+func NewSiteHandler(svc *service.SiteService) *SiteHandler {
+	return &SiteHandler{svc: svc}
+}
+
+// Create godoc
+// @Summary Create a site
+// @Description Creates an office site for on-site attendance (Admin only). The geofence is a circle (latitude, longitude and radius_meters) or a polygon of at least 3 points; a polygon's center defaults to the average of its points.
+// @Tags sites
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSiteRequest true "Site details"
+// @Success 201 {object} dto.SiteResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /sites [post]
+func (h *SiteHandler) Create(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var req dto.CreateSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.CreateSite(c.Request.Context(), agencyID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrSiteExists:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case domain.ErrInvalidSiteFence:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// List godoc
+// @Summary List sites (Admin)
+// @Description Returns the sites of the agency with their geofences. Admin only: employees cannot read the geofences.
+// @Tags sites
+// @Produce json
+// @Param active query bool false "Filter by active state"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.SiteResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /sites/list [get]
+func (h *SiteHandler) List(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.SiteListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.GetAgencySites(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list sites"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Update godoc
+// @Summary Update a site
+// @Description Changes the name or geofence of a site, or deactivates it (Admin only). Sending "polygon" replaces the polygon; an empty list turns the site back into a circle, which needs radius_meters.
+// @Tags sites
+// @Accept json
+// @Produce json
+// @Param id path string true "Site ID"
+// @Param request body dto.UpdateSiteRequest true "Updated details"
+// @Success 200 {object} dto.SiteResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /sites/{id} [put]
+func (h *SiteHandler) Update(c *gin.Context) {
+	siteID, err := uuid.Parse(c.Param("id"))
+	if err != nil || siteID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid site ID"})
+		return
+	}
+
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var req dto.UpdateSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.UpdateSite(c.Request.Context(), agencyID, siteID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrSiteNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case domain.ErrSiteExists:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case domain.ErrInvalidSiteFence:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
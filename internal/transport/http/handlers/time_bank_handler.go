@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TimeBankHandler struct {
+	svc *service.TimeBankService
+}
+
+func NewTimeBankHandler(svc *service.TimeBankService) *TimeBankHandler {
+	return &TimeBankHandler{svc: svc}
+}
+
+// Balance godoc
+// @Summary Get time bank balance
+// @Description Returns the user's banked minutes: credited for working past the scheduled exit, debited for leaving early, taking comp time or expired credits. Credits are used oldest first; expirations lists when the unused ones expire. pending_minutes are requested as comp time but not approved yet. Employees can only see their own balance.
+// @Tags time-bank
+// @Produce json
+// @Param user_id query string false "User ID (Admins only, defaults to the current user)"
+// @Success 200 {object} dto.TimeBankBalanceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /time-bank/balance [get]
+func (h *TimeBankHandler) Balance(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.TimeBankBalanceParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := balanceUserID(c, params.UserID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetBalance(c.Request.Context(), agencyID, userID)
+	if err != nil {
+		if err == domain.ErrUserNotFound || err == domain.ErrAgencyNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Ledger godoc
+// @Summary List time bank movements
+// @Description Returns every movement of the user's time bank, latest first. Movements from an attendance are recorded as differences: when the record is corrected or voided a new movement reverses or adjusts what it contributed. Employees can only see their own movements.
+// @Tags time-bank
+// @Produce json
+// @Param user_id query string false "User ID (Admins only, defaults to the current user)"
+// @Param kind query string false "Kind (overtime, early_leave, comp_time, adjustment, expiry)"
+// @Param start_date query string false "From date (YYYY-MM-DD)"
+// @Param end_date query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.TimeBankEntryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /time-bank/ledger [get]
+func (h *TimeBankHandler) Ledger(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.TimeBankLedgerParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := balanceUserID(c, params.UserID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.Ledger(c.Request.Context(), agencyID, userID, &params)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Adjust godoc
+// @Summary Adjust a time bank
+// @Description Records a manual movement on a user's time bank, e.g. an opening balance or overtime paid out instead of banked (Admin only). minutes is positive to credit and negative to debit; a note is required. The agency must have the time bank enabled.
+// @Tags time-bank
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTimeBankAdjustmentRequest true "Adjustment details"
+// @Success 201 {object} dto.TimeBankEntryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /time-bank/adjustments [post]
+func (h *TimeBankHandler) Adjust(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	var req dto.CreateTimeBankAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.Adjust(c.Request.Context(), agencyID, adminID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidTimeBankAdjustment:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "minutes must not be zero, note is required and date must be YYYY-MM-DD"})
+		case domain.ErrTimeBankDisabled:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case domain.ErrUserNotFound, domain.ErrAgencyNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
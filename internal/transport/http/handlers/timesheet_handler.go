@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"net/http"
+	"quickattendance-go/internal/domain"
+	"quickattendance-go/internal/dto"
+	"quickattendance-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TimesheetHandler struct {
+	svc *service.TimesheetService
+}
+
+func NewTimesheetHandler(svc *service.TimesheetService) *TimesheetHandler {
+	return &TimesheetHandler{svc: svc}
+}
+
+// Get godoc
+// @Summary Get a monthly timesheet
+// @Description Returns the user's timesheet for a month: one row per day with the scheduled minutes, attendance, lateness, early leave, absence, approved leave or holiday, and the month totals. Locked timesheets return the content that was acknowledged; otherwise it is computed from the current data and changed tells whether it differs from the acknowledged version. Employees can only see their own timesheet.
+// @Tags timesheets
+// @Produce json
+// @Param period query string true "Month (YYYY-MM)"
+// @Param user_id query string false "User ID (Admins only, defaults to the current user)"
+// @Success 200 {object} dto.TimesheetResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /timesheets [get]
+func (h *TimesheetHandler) Get(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.TimesheetParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := balanceUserID(c, params.UserID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.Get(c.Request.Context(), agencyID, userID, params.Period)
+	if err != nil {
+		handleTimesheetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// List godoc
+// @Summary List timesheets of a month (Admin)
+// @Description Returns the timesheet status of every active user of the agency for a month, with the totals they acknowledged. Users who have not acknowledged it yet are listed as open.
+// @Tags timesheets
+// @Produce json
+// @Param period query string true "Month (YYYY-MM)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.TimesheetSummaryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /timesheets/list [get]
+func (h *TimesheetHandler) List(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var params dto.TimesheetListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.List(c.Request.Context(), agencyID, &params)
+	if err != nil {
+		handleTimesheetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Acknowledge godoc
+// @Summary Acknowledge a timesheet
+// @Description The current user confirms they reviewed their timesheet for a month that has already ended. The content they saw is stored with the time and IP address of the acknowledgement. It can be acknowledged again while it is not locked, e.g. after a correction.
+// @Tags timesheets
+// @Accept json
+// @Produce json
+// @Param request body dto.AcknowledgeTimesheetRequest true "Month"
+// @Success 200 {object} dto.TimesheetResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /timesheets/acknowledge [post]
+func (h *TimesheetHandler) Acknowledge(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req dto.AcknowledgeTimesheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.Acknowledge(c.Request.Context(), agencyID, userID, &req, c.ClientIP())
+	if err != nil {
+		handleTimesheetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Lock godoc
+// @Summary Lock a timesheet (Admin)
+// @Description Locks a timesheet the employee acknowledged. From then on attendance records of that user within the month cannot be created, edited, voided or corrected (409). Fails with 409 if the timesheet was not acknowledged or the attendance changed since then.
+// @Tags timesheets
+// @Accept json
+// @Produce json
+// @Param request body dto.TimesheetActionRequest true "User and month"
+// @Success 200 {object} dto.TimesheetResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /timesheets/lock [post]
+func (h *TimesheetHandler) Lock(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	var req dto.TimesheetActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.Lock(c.Request.Context(), agencyID, adminID, &req)
+	if err != nil {
+		handleTimesheetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Unlock godoc
+// @Summary Unlock a timesheet (Admin)
+// @Description Reopens a locked timesheet so the month's attendance can be corrected. The acknowledgement is discarded and the employee must acknowledge it again.
+// @Tags timesheets
+// @Accept json
+// @Produce json
+// @Param request body dto.TimesheetActionRequest true "User and month"
+// @Success 200 {object} dto.TimesheetResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /timesheets/unlock [post]
+func (h *TimesheetHandler) Unlock(c *gin.Context) {
+	agencyID := c.MustGet("agency_id").(uuid.UUID)
+
+	var req dto.TimesheetActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.svc.Unlock(c.Request.Context(), agencyID, &req)
+	if err != nil {
+		handleTimesheetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func handleTimesheetError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrInvalidTimesheetPeriod:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must be YYYY-MM"})
+	case domain.ErrUserNotFound, domain.ErrAgencyNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case domain.ErrTimesheetPeriodNotEnded, domain.ErrTimesheetLocked, domain.ErrTimesheetNotLocked,
+		domain.ErrTimesheetNotAcknowledged, domain.ErrTimesheetChanged:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery responde 500 ante un panic como gin.Recovery, pero deja pasar http.ErrAbortHandler para que
+// net/http corte la conexión: es la forma de interrumpir una respuesta que ya empezó a enviarse
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, err any) {
+		if err == http.ErrAbortHandler {
+			panic(err)
+		}
+		slog.Error("Panic recovered", "path", c.Request.URL.Path, "error", err, "stack", string(debug.Stack()))
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
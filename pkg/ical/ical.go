@@ -0,0 +1,255 @@
+// Package ical lee los eventos de un archivo iCalendar (RFC 5545). Solo interpreta lo necesario
+// para importar calendarios de feriados: fechas de inicio y fin, resumen y recurrencia anual (con COUNT y UNTIL).
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotCalendar = errors.New("not an iCalendar file")
+	ErrInvalidDate = errors.New("invalid date")
+	ErrInvalidRule = errors.New("invalid recurrence rule")
+)
+
+// Event es un VEVENT del calendario
+type Event struct {
+	UID     string
+	Summary string
+	Start   time.Time // Día de inicio, medianoche UTC
+	End     time.Time // Día de término exclusivo; para eventos de un día es Start + 1 día
+	Yearly  bool      // RRULE con FREQ=YEARLY
+	Count   int       // COUNT de la RRULE: cuántas veces ocurre en total; 0 si no lo indica
+	Until   time.Time // UNTIL de la RRULE: último día en que puede empezar una repetición; cero si no lo indica
+}
+
+// Recurring indica si el evento se repite todos los años sin fin
+func (e Event) Recurring() bool {
+	return e.Yearly && e.Count == 0 && e.Until.IsZero()
+}
+
+// Occurrences devuelve el día de inicio de cada ocurrencia, hasta max. Un evento sin RRULE anual, o que se
+// repite sin fin (Recurring), ocurre una vez: repetirlo queda a cargo de quien lo guarde. Como pide RFC 5545,
+// los años en que la fecha no existe (29 de febrero) se saltan sin contar para COUNT.
+func (e Event) Occurrences(max int) []time.Time {
+	if !e.Yearly || e.Recurring() {
+		return []time.Time{e.Start}
+	}
+
+	var starts []time.Time
+	for year := 0; len(starts) < max; year++ {
+		d := e.Start.AddDate(year, 0, 0)
+		if !e.Until.IsZero() && d.After(e.Until) {
+			break
+		}
+		if d.Day() != e.Start.Day() {
+			continue
+		}
+		starts = append(starts, d)
+		if e.Count > 0 && len(starts) == e.Count {
+			break
+		}
+	}
+	return starts
+}
+
+// Days devuelve cada día de la ocurrencia que empieza en start, hasta max días
+func (e Event) Days(start time.Time, max int) []time.Time {
+	var days []time.Time
+	end := start.Add(e.End.Sub(e.Start))
+	for d := start; d.Before(end) && len(days) < max; d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
+
+// Parse lee todos los VEVENT de r. Las horas se descartan: de cada evento solo importan los días que cubre.
+// Las horas UTC (sufijo Z) se pasan a loc antes de tomar el día; las con TZID o sin zona, en la zona en que fueron escritas.
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, ErrNotCalendar
+	}
+
+	var events []Event
+	var current *Event
+	hasEnd := false
+
+	for i, line := range lines {
+		name, params, value, ok := splitLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &Event{}
+			hasEnd = false
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if current == nil {
+				continue
+			}
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: event without DTSTART: %w", i+1, ErrInvalidDate)
+			}
+			if !hasEnd || !current.End.After(current.Start) {
+				current.End = current.Start.AddDate(0, 0, 1)
+			}
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescape(value)
+		case name == "DTSTART":
+			current.Start, _, err = parseDate(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		case name == "DTEND":
+			end, partial, err := parseDate(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			// Un DTEND con hora dentro del día siguiente aún cubre ese día
+			if partial {
+				end = end.AddDate(0, 0, 1)
+			}
+			current.End = end
+			hasEnd = true
+		case name == "RRULE":
+			if err := parseRule(current, value, loc); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// unfold une las líneas plegadas: una línea que empieza con espacio o tabulación continúa la anterior
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitLine separa "NOMBRE;PARAM=VALOR:valor" en sus partes. El nombre y los parámetros se normalizan a mayúsculas.
+func splitLine(line string) (string, map[string]string, string, bool) {
+	idx := strings.Index(line, ":")
+	if idx < 0 {
+		return "", nil, "", false
+	}
+
+	head, value := line[:idx], line[idx+1:]
+	parts := strings.Split(head, ";")
+
+	params := map[string]string{}
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+func isDateOnly(value string, params map[string]string) bool {
+	return strings.EqualFold(params["VALUE"], "DATE") || len(value) == len("20060102")
+}
+
+// parseRule toma de una RRULE la frecuencia anual y sus límites COUNT y UNTIL; las demás partes se ignoran
+func parseRule(event *Event, value string, loc *time.Location) error {
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			event.Yearly = strings.EqualFold(v, "YEARLY")
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return fmt.Errorf("COUNT %q: %w", v, ErrInvalidRule)
+			}
+			event.Count = n
+		case "UNTIL":
+			until, _, err := parseDate(v, nil, loc)
+			if err != nil {
+				return err
+			}
+			event.Until = until
+		}
+	}
+	return nil
+}
+
+// parseDate devuelve el día calendario de una fecha DATE o DATE-TIME como medianoche UTC, y si la hora
+// cae después de la medianoche (la fecha cubre solo parte de ese día).
+// Las horas con TZID se interpretan en esa zona; las UTC (sufijo Z) se pasan a loc antes de tomar el día.
+func parseDate(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if isDateOnly(value, params) {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%q: %w", value, ErrInvalidDate)
+		}
+		return t, false, nil
+	}
+
+	written := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			written = l
+		}
+	}
+
+	layout := "20060102T150405"
+	utc := strings.HasSuffix(value, "Z")
+	if utc {
+		layout += "Z"
+		written = time.UTC
+	}
+	t, err := time.ParseInLocation(layout, value, written)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q: %w", value, ErrInvalidDate)
+	}
+	if utc {
+		t = t.In(loc)
+	}
+
+	partial := t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), partial, nil
+}
+
+// unescape revierte el escape de textos de RFC 5545 (\\, \; \, y \n)
+func unescape(value string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, `;`, `\,`, `,`, `\n`, "\n", `\N`, "\n").Replace(value)
+}
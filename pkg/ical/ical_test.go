@@ -0,0 +1,243 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func calendar(lines ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	tests := []struct {
+		name      string
+		event     []string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantCount int
+		wantUntil time.Time
+		recurring bool
+	}{
+		{
+			name:      "all-day event",
+			event:     []string{"DTSTART;VALUE=DATE:20260918", "DTEND;VALUE=DATE:20260919"},
+			wantStart: day(2026, 9, 18), wantEnd: day(2026, 9, 19),
+		},
+		{
+			name:      "without DTEND",
+			event:     []string{"DTSTART;VALUE=DATE:20260918"},
+			wantStart: day(2026, 9, 18), wantEnd: day(2026, 9, 19),
+		},
+		{
+			name:      "multi-day event",
+			event:     []string{"DTSTART;VALUE=DATE:20260918", "DTEND;VALUE=DATE:20260921"},
+			wantStart: day(2026, 9, 18), wantEnd: day(2026, 9, 21),
+		},
+		{
+			name:      "UTC midnight is the previous day in the agency",
+			event:     []string{"DTSTART:20260918T000000Z", "DTEND:20260919T000000Z"},
+			wantStart: day(2026, 9, 17), wantEnd: day(2026, 9, 19),
+		},
+		{
+			name:      "UTC time within the agency day",
+			event:     []string{"DTSTART:20260918T120000Z", "DTEND:20260918T200000Z"},
+			wantStart: day(2026, 9, 18), wantEnd: day(2026, 9, 19),
+		},
+		{
+			name:      "time with TZID stays in its zone",
+			event:     []string{"DTSTART;TZID=Europe/Madrid:20260918T000000", "DTEND;TZID=Europe/Madrid:20260919T000000"},
+			wantStart: day(2026, 9, 18), wantEnd: day(2026, 9, 19),
+		},
+		{
+			name:      "endless yearly rule",
+			event:     []string{"DTSTART;VALUE=DATE:20260918", "RRULE:FREQ=YEARLY"},
+			wantStart: day(2026, 9, 18), wantEnd: day(2026, 9, 19), recurring: true,
+		},
+		{
+			name:      "yearly rule with COUNT",
+			event:     []string{"DTSTART;VALUE=DATE:20260918", "RRULE:FREQ=YEARLY;COUNT=3"},
+			wantStart: day(2026, 9, 18), wantEnd: day(2026, 9, 19), wantCount: 3,
+		},
+		{
+			name:      "yearly rule with UTC UNTIL",
+			event:     []string{"DTSTART;VALUE=DATE:20260918", "RRULE:FREQ=YEARLY;UNTIL=20280918T020000Z"},
+			wantStart: day(2026, 9, 18), wantEnd: day(2026, 9, 19), wantUntil: day(2028, 9, 17),
+		},
+		{
+			name:      "monthly rule is not yearly",
+			event:     []string{"DTSTART;VALUE=DATE:20260918", "RRULE:FREQ=MONTHLY"},
+			wantStart: day(2026, 9, 18), wantEnd: day(2026, 9, 19),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"BEGIN:VEVENT", "SUMMARY:Fiestas Patrias"}, tt.event...)
+			lines = append(lines, "END:VEVENT")
+
+			events, err := Parse(strings.NewReader(calendar(lines...)), santiago)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("Parse() returned %d events, want 1", len(events))
+			}
+			e := events[0]
+			if !e.Start.Equal(tt.wantStart) || !e.End.Equal(tt.wantEnd) {
+				t.Errorf("event spans %s to %s, want %s to %s", e.Start, e.End, tt.wantStart, tt.wantEnd)
+			}
+			if e.Count != tt.wantCount || !e.Until.Equal(tt.wantUntil) {
+				t.Errorf("COUNT = %d, UNTIL = %s, want %d and %s", e.Count, e.Until, tt.wantCount, tt.wantUntil)
+			}
+			if e.Recurring() != tt.recurring {
+				t.Errorf("Recurring() = %v, want %v", e.Recurring(), tt.recurring)
+			}
+		})
+	}
+}
+
+func TestParseTextAndFolding(t *testing.T) {
+	ics := calendar(
+		"BEGIN:VEVENT",
+		"UID:abc-1",
+		"SUMMARY:Día de la Independencia\\, feriado\\; nacional",
+		"DTSTART;VALUE=DATE:2026",
+		" 0918",
+		"END:VEVENT",
+	)
+
+	events, err := Parse(strings.NewReader(ics), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Parse() returned %d events, want 1", len(events))
+	}
+	if events[0].UID != "abc-1" || events[0].Summary != "Día de la Independencia, feriado; nacional" {
+		t.Errorf("UID = %q, Summary = %q", events[0].UID, events[0].Summary)
+	}
+	if !events[0].Start.Equal(day(2026, 9, 18)) {
+		t.Errorf("Start = %s, want folded date 2026-09-18", events[0].Start)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+		want error
+	}{
+		{"not a calendar", "hello\r\n", ErrNotCalendar},
+		{"empty", "", ErrNotCalendar},
+		{"event without DTSTART", calendar("BEGIN:VEVENT", "SUMMARY:x", "END:VEVENT"), ErrInvalidDate},
+		{"invalid date", calendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20261345", "END:VEVENT"), ErrInvalidDate},
+		{"invalid COUNT", calendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260918", "RRULE:FREQ=YEARLY;COUNT=0", "END:VEVENT"), ErrInvalidRule},
+		{"invalid UNTIL", calendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260918", "RRULE:FREQ=YEARLY;UNTIL=never", "END:VEVENT"), ErrInvalidDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.ics), time.UTC)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		max   int
+		want  []time.Time
+	}{
+		{
+			name:  "single event",
+			event: Event{Start: day(2026, 9, 18), End: day(2026, 9, 19)},
+			max:   10,
+			want:  []time.Time{day(2026, 9, 18)},
+		},
+		{
+			name:  "endless yearly event is stored once",
+			event: Event{Start: day(2026, 9, 18), End: day(2026, 9, 19), Yearly: true},
+			max:   10,
+			want:  []time.Time{day(2026, 9, 18)},
+		},
+		{
+			name:  "COUNT",
+			event: Event{Start: day(2026, 9, 18), End: day(2026, 9, 19), Yearly: true, Count: 3},
+			max:   10,
+			want:  []time.Time{day(2026, 9, 18), day(2027, 9, 18), day(2028, 9, 18)},
+		},
+		{
+			name:  "UNTIL is inclusive",
+			event: Event{Start: day(2026, 9, 18), End: day(2026, 9, 19), Yearly: true, Until: day(2028, 9, 18)},
+			max:   10,
+			want:  []time.Time{day(2026, 9, 18), day(2027, 9, 18), day(2028, 9, 18)},
+		},
+		{
+			name:  "UNTIL before the next occurrence",
+			event: Event{Start: day(2026, 9, 18), End: day(2026, 9, 19), Yearly: true, Until: day(2027, 9, 17)},
+			max:   10,
+			want:  []time.Time{day(2026, 9, 18)},
+		},
+		{
+			name:  "COUNT over max",
+			event: Event{Start: day(2026, 9, 18), End: day(2026, 9, 19), Yearly: true, Count: 100},
+			max:   2,
+			want:  []time.Time{day(2026, 9, 18), day(2027, 9, 18)},
+		},
+		{
+			name:  "February 29 skips non-leap years without counting them",
+			event: Event{Start: day(2024, 2, 29), End: day(2024, 3, 1), Yearly: true, Count: 2},
+			max:   10,
+			want:  []time.Time{day(2024, 2, 29), day(2028, 2, 29)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.event.Occurrences(tt.max)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Occurrences()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDays(t *testing.T) {
+	event := Event{Start: day(2026, 12, 30), End: day(2027, 1, 2), Yearly: true, Count: 2}
+
+	got := event.Days(day(2027, 12, 30), 31)
+	want := []time.Time{day(2027, 12, 30), day(2027, 12, 31), day(2028, 1, 1)}
+	if len(got) != len(want) {
+		t.Fatalf("Days() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("Days()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	if got := event.Days(event.Start, 2); len(got) != 2 {
+		t.Errorf("Days() with max 2 returned %d days", len(got))
+	}
+}
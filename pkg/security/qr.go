@@ -0,0 +1,112 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrQRTokenInvalid = errors.New("invalid qr token")
+	ErrQRTokenExpired = errors.New("qr token expired")
+)
+
+const qrAudience = "qr-attendance"
+
+// QRService firma los tokens que muestran los códigos QR de cada agencia.
+// El token es el mismo durante toda una ventana de rotación, así varias pantallas
+// (o varias consultas de la misma pantalla) muestran el mismo código.
+type QRService struct {
+	secret   []byte
+	rotation time.Duration
+}
+
+// NewQRService deriva su propia clave a partir de secret, de modo que un token QR nunca
+// sea aceptado como token de sesión aunque ambos se configuren con el mismo secreto.
+func NewQRService(secret string, rotation time.Duration) *QRService {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(qrAudience))
+
+	return &QRService{
+		secret:   mac.Sum(nil),
+		rotation: rotation,
+	}
+}
+
+type QRClaims struct {
+	AgencyID uuid.UUID `json:"agency_id"`
+	jwt.RegisteredClaims
+}
+
+type QRToken struct {
+	Token     string
+	RefreshAt time.Time // Cuando la pantalla debe pedir el siguiente código
+	ExpiresAt time.Time
+}
+
+func (q *QRService) Rotation() time.Duration {
+	return q.rotation
+}
+
+// Issue devuelve el token de la ventana que contiene now. Sigue siendo válido una ventana
+// más después de rotar, para no rechazar a quien escaneó justo antes del cambio.
+func (q *QRService) Issue(agencyID uuid.UUID, now time.Time) (*QRToken, error) {
+	windowStart := now.Truncate(q.rotation)
+	refreshAt := windowStart.Add(q.rotation)
+	expiresAt := refreshAt.Add(q.rotation)
+
+	claims := QRClaims{
+		AgencyID: agencyID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        fmt.Sprintf("%s:%d", agencyID, windowStart.Unix()),
+			Audience:  jwt.ClaimStrings{qrAudience},
+			IssuedAt:  jwt.NewNumericDate(windowStart),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(q.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QRToken{
+		Token:     token,
+		RefreshAt: refreshAt,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (q *QRService) Verify(tokenStr string) (*QRClaims, error) {
+	token, err := jwt.ParseWithClaims(
+		tokenStr,
+		&QRClaims{},
+		func(t *jwt.Token) (any, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, ErrQRTokenInvalid
+			}
+			return q.secret, nil
+		},
+		jwt.WithAudience(qrAudience),
+		jwt.WithExpirationRequired(),
+	)
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrQRTokenExpired
+		}
+		return nil, ErrQRTokenInvalid
+	}
+
+	claims, ok := token.Claims.(*QRClaims)
+	if !ok || claims.ID == "" {
+		return nil, ErrQRTokenInvalid
+	}
+
+	return claims, nil
+}
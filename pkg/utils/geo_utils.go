@@ -22,3 +22,67 @@ func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
 
 	return R * c
 }
+
+// Point is a geographic coordinate in decimal degrees
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// PointInPolygon reports whether p lies inside the polygon using ray casting.
+// The polygon is a list of vertices in order; it is closed implicitly.
+// Coordinates are treated as planar, which is accurate enough for building-sized fences.
+func PointInPolygon(p Point, polygon []Point) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) {
+			crossLng := a.Lng + (p.Lat-a.Lat)/(b.Lat-a.Lat)*(b.Lng-a.Lng)
+			if p.Lng < crossLng {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// DistanceToPolygonEdge calculates the distance in meters from p to the closest edge of the polygon,
+// whether p is inside or outside. It projects the coordinates onto a local plane centered on p.
+func DistanceToPolygonEdge(p Point, polygon []Point) float64 {
+	if len(polygon) == 0 {
+		return math.Inf(1)
+	}
+
+	const R = 6371000 // Earth radius in meters
+	cosLat := math.Cos(p.Lat * math.Pi / 180)
+	project := func(q Point) (float64, float64) {
+		x := (q.Lng - p.Lng) * math.Pi / 180 * R * cosLat
+		y := (q.Lat - p.Lat) * math.Pi / 180 * R
+		return x, y
+	}
+
+	best := math.Inf(1)
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		ax, ay := project(polygon[j])
+		bx, by := project(polygon[i])
+		best = math.Min(best, distanceToSegment(ax, ay, bx, by))
+	}
+	return best
+}
+
+// distanceToSegment returns the distance from the origin to the segment AB
+func distanceToSegment(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	lengthSq := dx*dx + dy*dy
+	if lengthSq == 0 {
+		return math.Hypot(ax, ay)
+	}
+
+	// Parameter of the projection of the origin onto AB, clamped to the segment
+	t := math.Max(0, math.Min(1, -(ax*dx+ay*dy)/lengthSq))
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
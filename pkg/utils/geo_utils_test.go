@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+// A ~100 m square block around (0, 0). 0.0009° ≈ 100 m at the equator.
+var square = []Point{
+	{Lat: 0, Lng: 0},
+	{Lat: 0, Lng: 0.0009},
+	{Lat: 0.0009, Lng: 0.0009},
+	{Lat: 0.0009, Lng: 0},
+}
+
+// An L-shaped (concave) building: the square above without its upper-right quadrant.
+var lShape = []Point{
+	{Lat: 0, Lng: 0},
+	{Lat: 0, Lng: 0.0009},
+	{Lat: 0.00045, Lng: 0.0009},
+	{Lat: 0.00045, Lng: 0.00045},
+	{Lat: 0.0009, Lng: 0.00045},
+	{Lat: 0.0009, Lng: 0},
+}
+
+func TestPointInPolygon(t *testing.T) {
+	tests := []struct {
+		name    string
+		point   Point
+		polygon []Point
+		want    bool
+	}{
+		{"center of square", Point{Lat: 0.00045, Lng: 0.00045}, square, true},
+		{"outside square to the east", Point{Lat: 0.00045, Lng: 0.002}, square, false},
+		{"outside square to the south", Point{Lat: -0.0001, Lng: 0.00045}, square, false},
+		{"inside lower arm of L", Point{Lat: 0.0002, Lng: 0.0007}, lShape, true},
+		{"inside left arm of L", Point{Lat: 0.0007, Lng: 0.0002}, lShape, true},
+		{"in the notch of L", Point{Lat: 0.0007, Lng: 0.0007}, lShape, false},
+		{"degenerate polygon", Point{Lat: 0, Lng: 0}, square[:2], false},
+		{"empty polygon", Point{Lat: 0, Lng: 0}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PointInPolygon(tt.point, tt.polygon); got != tt.want {
+				t.Errorf("PointInPolygon(%v) = %v, want %v", tt.point, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointInPolygonNegativeCoordinates(t *testing.T) {
+	// Plaza de Armas, Santiago (southern and western hemispheres)
+	plaza := []Point{
+		{Lat: -33.4372, Lng: -70.6515},
+		{Lat: -33.4372, Lng: -70.6497},
+		{Lat: -33.4385, Lng: -70.6497},
+		{Lat: -33.4385, Lng: -70.6515},
+	}
+
+	if !PointInPolygon(Point{Lat: -33.4378, Lng: -70.6506}, plaza) {
+		t.Error("expected point inside the plaza")
+	}
+	if PointInPolygon(Point{Lat: -33.4400, Lng: -70.6506}, plaza) {
+		t.Error("expected point south of the plaza to be outside")
+	}
+}
+
+func TestDistanceToPolygonEdge(t *testing.T) {
+	metersPerDegree := 6371000 * math.Pi / 180
+
+	tests := []struct {
+		name  string
+		point Point
+		want  float64
+	}{
+		{"center of square", Point{Lat: 0.00045, Lng: 0.00045}, 0.00045 * metersPerDegree},
+		{"on an edge", Point{Lat: 0, Lng: 0.00045}, 0},
+		{"outside to the east", Point{Lat: 0.00045, Lng: 0.0018}, 0.0009 * metersPerDegree},
+		{"outside past a corner", Point{Lat: -0.0003, Lng: -0.0004}, 0.0005 * metersPerDegree},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceToPolygonEdge(tt.point, square)
+			if math.Abs(got-tt.want) > 0.5 {
+				t.Errorf("DistanceToPolygonEdge(%v) = %.2f m, want %.2f m", tt.point, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHaversine(t *testing.T) {
+	// One degree of latitude along a meridian
+	got := Haversine(0, 0, 1, 0)
+	want := 6371000 * math.Pi / 180
+	if math.Abs(got-want) > 1 {
+		t.Errorf("Haversine = %.1f m, want %.1f m", got, want)
+	}
+
+	if d := Haversine(-33.4378, -70.6506, -33.4378, -70.6506); d != 0 {
+		t.Errorf("Haversine between identical points = %f, want 0", d)
+	}
+}
@@ -0,0 +1,238 @@
+// Package xlsx escribe una planilla Office Open XML (.xlsx) de una sola hoja fila por fila, sin guardarla
+// en memoria: cada fila se comprime y se envía al io.Writer apenas se escribe. Solo soporta lo necesario para
+// exportar tablas: textos, números y una fila de encabezado en negrita que queda fija al desplazarse.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrClosed = errors.New("xlsx writer is closed")
+
+const (
+	// MIME de los archivos .xlsx, para el Content-Type de una descarga
+	ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// Excel no acepta nombres de hoja más largos
+	maxSheetName = 31
+)
+
+// Partes fijas del paquete; la hoja se escribe aparte a medida que llegan las filas
+var staticParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	// Estilo 0 es el normal y el 1 el del encabezado (negrita)
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+		`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+		`</styleSheet>`},
+}
+
+// Writer escribe una planilla de una hoja. Nada se envía hasta la primera fila (o hasta Close), así
+// quien lo usa todavía puede responder con un error si falla antes de empezar.
+type Writer struct {
+	w       io.Writer
+	sheet   string
+	zw      *zip.Writer
+	buf     *bufio.Writer
+	rows    int
+	started bool
+	closed  bool
+}
+
+// NewWriter crea una planilla cuya única hoja se llama sheet
+func NewWriter(w io.Writer, sheet string) *Writer {
+	return &Writer{w: w, sheet: sheetName(sheet)}
+}
+
+// WriteHeader escribe la fila de encabezado en negrita y la deja fija. Debe ser la primera fila.
+func (w *Writer) WriteHeader(labels []string) error {
+	if w.closed {
+		return ErrClosed
+	}
+	if w.started {
+		return errors.New("xlsx header must be the first row")
+	}
+	if err := w.start(true); err != nil {
+		return err
+	}
+
+	values := make([]any, len(labels))
+	for i, label := range labels {
+		values[i] = label
+	}
+	return w.writeRow(values, 1)
+}
+
+// WriteRow escribe una fila. Los enteros y decimales quedan como números, nil como celda vacía y
+// cualquier otro valor como texto.
+func (w *Writer) WriteRow(values []any) error {
+	if w.closed {
+		return ErrClosed
+	}
+	if !w.started {
+		if err := w.start(false); err != nil {
+			return err
+		}
+	}
+	return w.writeRow(values, 0)
+}
+
+// Close termina la hoja y el archivo zip. No cierra el io.Writer de destino.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if !w.started {
+		if err := w.start(false); err != nil {
+			return err
+		}
+	}
+	w.closed = true
+
+	if _, err := w.buf.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// start escribe las partes fijas y abre la hoja. Como la vista va antes de los datos, al abrirla
+// hay que saber si la primera fila quedará fija.
+func (w *Writer) start(freezeHeader bool) error {
+	w.started = true
+	w.zw = zip.NewWriter(w.w)
+
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + escape(w.sheet) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	for _, part := range staticParts {
+		if err := w.writePart(part.name, part.content); err != nil {
+			return err
+		}
+	}
+	if err := w.writePart("xl/workbook.xml", workbook); err != nil {
+		return err
+	}
+
+	f, err := w.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	w.buf = bufio.NewWriter(f)
+
+	w.buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	w.buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if freezeHeader {
+		w.buf.WriteString(`<sheetViews><sheetView workbookViewId="0">` +
+			`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>` +
+			`</sheetView></sheetViews>`)
+	}
+	_, err = w.buf.WriteString(`<sheetData>`)
+	return err
+}
+
+func (w *Writer) writePart(name, content string) error {
+	f, err := w.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+func (w *Writer) writeRow(values []any, style int) error {
+	w.rows++
+	row := strconv.Itoa(w.rows)
+
+	var sb strings.Builder
+	sb.WriteString(`<row r="` + row + `">`)
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		ref := columnName(i) + row
+		attrs := `r="` + ref + `"`
+		if style > 0 {
+			attrs += ` s="` + strconv.Itoa(style) + `"`
+		}
+
+		switch v := value.(type) {
+		case int, int32, int64, uint, uint32, uint64:
+			fmt.Fprintf(&sb, `<c %s><v>%d</v></c>`, attrs, v)
+		case float32, float64:
+			fmt.Fprintf(&sb, `<c %s><v>%v</v></c>`, attrs, v)
+		case string:
+			sb.WriteString(`<c ` + attrs + ` t="inlineStr"><is><t xml:space="preserve">` + escape(v) + `</t></is></c>`)
+		default:
+			sb.WriteString(`<c ` + attrs + ` t="inlineStr"><is><t xml:space="preserve">` + escape(fmt.Sprint(v)) + `</t></is></c>`)
+		}
+	}
+	sb.WriteString(`</row>`)
+
+	_, err := w.buf.WriteString(sb.String())
+	return err
+}
+
+// columnName convierte un índice desde 0 en la letra de columna de Excel (0 → A, 26 → AA)
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// escape escapa el texto para XML; los caracteres que XML no admite se reemplazan por U+FFFD
+func escape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+// sheetName quita los caracteres que Excel no permite en el nombre de una hoja y lo recorta a 31 caracteres
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > maxSheetName {
+		name = string(runes[:maxSheetName])
+	}
+	if name == "" {
+		name = "Sheet1"
+	}
+	return name
+}
@@ -0,0 +1,248 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+		{16383, "XFD"}, // Last column in Excel
+	}
+
+	for _, tt := range tests {
+		if got := columnName(tt.index); got != tt.want {
+			t.Errorf("columnName(%d) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{`a < b & c > "d" 'e'`, "a &lt; b &amp; c &gt; &#34;d&#34; &#39;e&#39;"},
+		{"línea\tcon tab", "línea&#x9;con tab"},
+		{"control\x01char", "control�char"},
+	}
+
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"asistencias", "asistencias"},
+		{"2026/03: [marzo]?*", "202603 marzo"},
+		{`a\b`, "ab"},
+		{"", "Sheet1"},
+		{"[]:*?/\\", "Sheet1"},
+		{strings.Repeat("x", 40), strings.Repeat("x", 31)},
+		{strings.Repeat("ñ", 40), strings.Repeat("ñ", 31)},
+	}
+
+	for _, tt := range tests {
+		if got := sheetName(tt.in); got != tt.want {
+			t.Errorf("sheetName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// worksheet holds the parts of sheet1.xml the tests check
+type worksheet struct {
+	Pane *struct {
+		YSplit string `xml:"ySplit,attr"`
+		State  string `xml:"state,attr"`
+	} `xml:"sheetViews>sheetView>pane"`
+	Rows []struct {
+		R     string `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			S      string `xml:"s,attr"`
+			T      string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readParts(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("output is not a zip file: %v", err)
+	}
+
+	parts := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		parts[f.Name] = content
+	}
+
+	for _, name := range []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/_rels/workbook.xml.rels",
+		"xl/styles.xml",
+		"xl/workbook.xml",
+		"xl/worksheets/sheet1.xml",
+	} {
+		content, ok := parts[name]
+		if !ok {
+			t.Fatalf("missing part %s", name)
+		}
+		// Every part must be well-formed XML
+		dec := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed XML: %v", name, err)
+			}
+		}
+	}
+	return parts
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "Asistencias: marzo")
+
+	if err := w.WriteHeader([]string{"Nombre", "Minutos", "Nota"}); err != nil {
+		t.Fatalf("WriteHeader() error = %v", err)
+	}
+	rows := [][]any{
+		{"Ana & <Bea>", 480, nil},
+		{"Carlos", 1.5, true},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow() error = %v", err)
+		}
+	}
+	wide := make([]any, 28)
+	wide[27] = "AB"
+	if err := w.WriteRow(wide); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+	if err := w.WriteRow([]any{"late"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("WriteRow() after Close error = %v, want ErrClosed", err)
+	}
+
+	parts := readParts(t, buf.Bytes())
+
+	if !bytes.Contains(parts["xl/workbook.xml"], []byte(`<sheet name="Asistencias marzo"`)) {
+		t.Errorf("workbook.xml does not name the sheet: %s", parts["xl/workbook.xml"])
+	}
+
+	var sheet worksheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatalf("parse sheet1.xml: %v", err)
+	}
+	if sheet.Pane == nil || sheet.Pane.YSplit != "1" || sheet.Pane.State != "frozen" {
+		t.Errorf("header row is not frozen: %+v", sheet.Pane)
+	}
+	if len(sheet.Rows) != 4 {
+		t.Fatalf("sheet has %d rows, want 4", len(sheet.Rows))
+	}
+
+	header := sheet.Rows[0]
+	if header.R != "1" || len(header.Cells) != 3 || header.Cells[2].R != "C1" || header.Cells[2].S != "1" || header.Cells[2].Inline != "Nota" {
+		t.Errorf("unexpected header row: %+v", header)
+	}
+
+	first := sheet.Rows[1]
+	if len(first.Cells) != 2 {
+		t.Fatalf("nil value should leave the cell out: %+v", first)
+	}
+	if c := first.Cells[0]; c.R != "A2" || c.T != "inlineStr" || c.S != "" || c.Inline != "Ana & <Bea>" {
+		t.Errorf("unexpected text cell: %+v", c)
+	}
+	if c := first.Cells[1]; c.R != "B2" || c.T != "" || c.Value != "480" {
+		t.Errorf("unexpected integer cell: %+v", c)
+	}
+
+	second := sheet.Rows[2]
+	if c := second.Cells[1]; c.Value != "1.5" || c.T != "" {
+		t.Errorf("unexpected decimal cell: %+v", c)
+	}
+	if c := second.Cells[2]; c.T != "inlineStr" || c.Inline != "true" {
+		t.Errorf("other values should be written as text: %+v", c)
+	}
+
+	if c := sheet.Rows[3].Cells; len(c) != 1 || c[0].R != "AB4" {
+		t.Errorf("unexpected cell past column Z: %+v", c)
+	}
+}
+
+func TestWriterWithoutRows(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "")
+	if buf.Len() != 0 {
+		t.Fatalf("NewWriter() wrote before the first row")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	parts := readParts(t, buf.Bytes())
+	var sheet worksheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatalf("parse sheet1.xml: %v", err)
+	}
+	if len(sheet.Rows) != 0 || sheet.Pane != nil {
+		t.Errorf("empty sheet has rows or a frozen pane: %+v", sheet)
+	}
+	if !bytes.Contains(parts["xl/workbook.xml"], []byte(`<sheet name="Sheet1"`)) {
+		t.Errorf("empty sheet name should default to Sheet1: %s", parts["xl/workbook.xml"])
+	}
+}
+
+func TestWriteHeaderAfterRow(t *testing.T) {
+	w := NewWriter(io.Discard, "x")
+	if err := w.WriteRow([]any{1}); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := w.WriteHeader([]string{"late"}); err == nil {
+		t.Errorf("WriteHeader() after a row should fail")
+	}
+}